@@ -22,12 +22,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology/common/log"
+	scabi "github.com/ontio/ontology/smartcontract/abi"
 	"io/ioutil"
 	"strings"
 )
 
 var DefAbiMgr = NewAbiMgr()
 
+const NEOVM_ABI_DIR = "neovm"
+
 type AbiMgr struct {
 	Path       string
 	nativeAbis map[string]*NativeContractAbi
@@ -39,6 +42,17 @@ func NewAbiMgr() *AbiMgr {
 	}
 }
 
+//GetNeovmAbi return the ABI of neovm contract by address in hex, nil if not registered
+func (this *AbiMgr) GetNeovmAbi(address string) *scabi.NeovmContractAbi {
+	return scabi.DefNeovmAbiRegistry.GetNeovmAbi(address)
+}
+
+//RegisterNeovmAbi register the ABI of neovm contract to scabi.DefNeovmAbiRegistry,
+//replacing the old one of the same address
+func (this *AbiMgr) RegisterNeovmAbi(abi *scabi.NeovmContractAbi) {
+	scabi.DefNeovmAbiRegistry.RegisterNeovmAbi(abi)
+}
+
 func (this *AbiMgr) GetNativeAbi(address string) *NativeContractAbi {
 	abi, ok := this.nativeAbis[address]
 	if ok {
@@ -50,6 +64,36 @@ func (this *AbiMgr) GetNativeAbi(address string) *NativeContractAbi {
 func (this *AbiMgr) Init(path string) {
 	this.Path = path
 	this.loadNativeAbi()
+	this.loadNeovmAbi()
+}
+
+//loadNeovmAbi load neovm contract ABIs in the neovm sub directory of path, the directory is optional
+func (this *AbiMgr) loadNeovmAbi() {
+	path := fmt.Sprintf("%s/%s", this.Path, NEOVM_ABI_DIR)
+	abiFiles, err := ioutil.ReadDir(path)
+	if err != nil {
+		log.Debugf("AbiMgr loadNeovmAbi read dir:%s error:%s", path, err)
+		return
+	}
+	for _, abiFile := range abiFiles {
+		fileName := abiFile.Name()
+		if abiFile.IsDir() || !strings.HasSuffix(fileName, ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", path, fileName))
+		if err != nil {
+			log.Errorf("AbiMgr loadNeovmAbi name:%s error:%s", fileName, err)
+			continue
+		}
+		neovmAbi := &scabi.NeovmContractAbi{}
+		err = json.Unmarshal(data, neovmAbi)
+		if err != nil {
+			log.Errorf("AbiMgr loadNeovmAbi name:%s error:%s", fileName, err)
+			continue
+		}
+		this.RegisterNeovmAbi(neovmAbi)
+		log.Infof("Neovm contract name:%s address:%s abi load success", fileName, neovmAbi.Address)
+	}
 }
 
 func (this *AbiMgr) loadNativeAbi() {
@@ -19,15 +19,23 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	cmdcom "github.com/ontio/ontology/cmd/common"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/types"
 	httpcom "github.com/ontio/ontology/http/base/common"
+	"github.com/ontio/ontology/smartcontract/debugger"
+	cstates "github.com/ontio/ontology/smartcontract/states"
 	"github.com/urfave/cli"
 	"io/ioutil"
+	"os"
+	"strconv"
 	"strings"
 )
 
@@ -89,6 +97,7 @@ var (
 					utils.ContractParamsFlag,
 					utils.ContractVersionFlag,
 					utils.ContractPrepareInvokeFlag,
+					utils.ContractProfileFlag,
 					utils.ContractReturnTypeFlag,
 					utils.WalletFileFlag,
 					utils.AccountAddressFlag,
@@ -106,9 +115,36 @@ var (
 					utils.TransactionGasLimitFlag,
 					utils.WalletFileFlag,
 					utils.ContractPrepareInvokeFlag,
+					utils.ContractProfileFlag,
 					utils.AccountAddressFlag,
 				},
 			},
+			{
+				Action: debugContract,
+				Name:   "debug",
+				Usage:  "Debug smart contract execution step by step",
+				ArgsUsage: `Debug the pre-execution of contract invoke (--address and --params) or invoke code (--code) on a node started with --ws --wsdebug.
+
+  Commands
+     s, step                      Execute one instruction
+     n, next                      Execute one instruction, step over APPCALL
+     c, continue                  Run until a breakpoint or the end
+     b <offset>                   Add breakpoint on instruction offset of all contracts
+     b <contract>:<offset>        Add breakpoint on instruction offset of contract
+     b syscall:<name>             Add breakpoint on syscall, e.g. b syscall:System.Storage.Put
+     d <breakpoint>               Delete breakpoint, same syntax as b
+     bl                           List breakpoints
+     bt, stack, alt, storage, notify
+                                  Print call stack, evaluation stack, alt stack, storage writes or notifications
+     q, quit                      Abort execution and quit
+`,
+				Flags: []cli.Flag{
+					utils.WsPortFlag,
+					utils.ContractAddrFlag,
+					utils.ContractParamsFlag,
+					utils.ContractCodeFileFlag,
+				},
+			},
 		},
 	}
 )
@@ -205,7 +241,7 @@ func invokeCodeContract(ctx *cli.Context) error {
 	}
 
 	if ctx.IsSet(utils.GetFlagName(utils.ContractPrepareInvokeFlag)) {
-		preResult, err := utils.PrepareInvokeCodeNeoVMContract(c)
+		preResult, err := utils.PrepareInvokeCodeNeoVMContract(c, ctx.Bool(utils.GetFlagName(utils.ContractProfileFlag)))
 		if err != nil {
 			return fmt.Errorf("PrepareInvokeCodeNeoVMContract error:%s", err)
 		}
@@ -214,6 +250,9 @@ func invokeCodeContract(ctx *cli.Context) error {
 		}
 		PrintInfoMsg("Contract pre-invoke successfully")
 		PrintInfoMsg("  Gas limit:%d", preResult.Gas)
+		if preResult.Profile != nil {
+			printGasProfile(preResult.Profile)
+		}
 
 		rawReturnTypes := ctx.String(utils.GetFlagName(utils.ContractReturnTypeFlag))
 		if rawReturnTypes == "" {
@@ -297,7 +336,7 @@ func invokeContract(ctx *cli.Context) error {
 	PrintInfoMsg("Invoke:%x Params:%s", contractAddr[:], paramData)
 
 	if ctx.IsSet(utils.GetFlagName(utils.ContractPrepareInvokeFlag)) {
-		preResult, err := utils.PrepareInvokeNeoVMContract(contractAddr, params)
+		preResult, err := utils.PrepareInvokeNeoVMContract(contractAddr, params, ctx.Bool(utils.GetFlagName(utils.ContractProfileFlag)))
 		if err != nil {
 			return fmt.Errorf("PrepareInvokeNeoVMSmartContact error:%s", err)
 		}
@@ -306,6 +345,9 @@ func invokeContract(ctx *cli.Context) error {
 		}
 		PrintInfoMsg("Contract invoke successfully")
 		PrintInfoMsg("  Gas limit:%d", preResult.Gas)
+		if preResult.Profile != nil {
+			printGasProfile(preResult.Profile)
+		}
 
 		rawReturnTypes := ctx.String(utils.GetFlagName(utils.ContractReturnTypeFlag))
 		if rawReturnTypes == "" {
@@ -350,3 +392,199 @@ func invokeContract(ctx *cli.Context) error {
 	PrintInfoMsg("  Using './ontology info status %s' to query transaction status.", txHash)
 	return nil
 }
+
+func printGasProfile(profile *cstates.Profile) {
+	PrintInfoMsg("  Gas profile:%d", profile.Gas)
+	groups := []struct {
+		name  string
+		costs []*cstates.GasCost
+	}{
+		{"Contracts", profile.Contracts},
+		{"Native calls", profile.NativeCalls},
+		{"Syscalls", profile.Syscalls},
+		{"Opcodes", profile.OpCodes},
+	}
+	for _, group := range groups {
+		if len(group.costs) == 0 {
+			continue
+		}
+		PrintInfoMsg("  %s:", group.name)
+		for _, cost := range group.costs {
+			PrintInfoMsg("    %-48s count:%-8d gas:%d", cost.Name, cost.Count, cost.Gas)
+		}
+	}
+	PrintInfoMsg("  Instructions:")
+	for _, ins := range profile.Instructions {
+		PrintInfoMsg("    %s:%-6d %-12s %-32s count:%-8d gas:%d", ins.Contract, ins.Offset, ins.OpCode, ins.Syscall, ins.Count, ins.Gas)
+	}
+}
+
+func debugContract(ctx *cli.Context) error {
+	var mutable *types.MutableTransaction
+	var err error
+	if ctx.IsSet(utils.GetFlagName(utils.ContractCodeFileFlag)) {
+		codeFile := ctx.String(utils.GetFlagName(utils.ContractCodeFileFlag))
+		codeStr, err := ioutil.ReadFile(codeFile)
+		if err != nil {
+			return fmt.Errorf("read code:%s error:%s", codeFile, err)
+		}
+		code, err := common.HexToBytes(strings.TrimSpace(string(codeStr)))
+		if err != nil {
+			return fmt.Errorf("contrace code convert hex to bytes error:%s", err)
+		}
+		mutable, err = httpcom.NewSmartContractTransaction(0, 0, code)
+		if err != nil {
+			return err
+		}
+	} else if ctx.IsSet(utils.GetFlagName(utils.ContractAddrFlag)) {
+		contractAddr, err := common.AddressFromHexString(ctx.String(utils.GetFlagName(utils.ContractAddrFlag)))
+		if err != nil {
+			return fmt.Errorf("invalid contract address error:%s", err)
+		}
+		params, err := utils.ParseParams(ctx.String(utils.GetFlagName(utils.ContractParamsFlag)))
+		if err != nil {
+			return fmt.Errorf("parseParams error:%s", err)
+		}
+		mutable, err = httpcom.NewNeovmInvokeTransaction(0, 0, contractAddr, params)
+		if err != nil {
+			return err
+		}
+	} else {
+		PrintErrorMsg("Missing %s or %s argument.", utils.ContractAddrFlag.Name, utils.ContractCodeFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	if err := tx.Serialize(&buffer); err != nil {
+		return fmt.Errorf("tx serialize error:%s", err)
+	}
+
+	client, err := utils.NewDebugClient(ctx.Uint(utils.GetFlagName(utils.WsPortFlag)))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	state, err := client.Start(hex.EncodeToString(buffer.Bytes()))
+	if err != nil {
+		return err
+	}
+	printDebugPosition(state)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("(debug) ")
+		if !scanner.Scan() {
+			_, err := client.Quit()
+			return err
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "s", "step", "n", "next", "c", "continue":
+			modes := map[string]string{"s": "step", "n": "next", "c": "continue"}
+			mode := fields[0]
+			if m, ok := modes[mode]; ok {
+				mode = m
+			}
+			state, err = client.Resume(mode)
+			if err != nil {
+				PrintErrorMsg("%s", err)
+				continue
+			}
+			printDebugPosition(state)
+		case "b", "d":
+			if len(fields) != 2 {
+				PrintErrorMsg("Usage: %s <offset> | <contract>:<offset> | syscall:<name>", fields[0])
+				continue
+			}
+			contract, offset, syscall, err := parseBreakpoint(fields[1])
+			if err != nil {
+				PrintErrorMsg("%s", err)
+				continue
+			}
+			if _, err := client.Breakpoint(contract, offset, syscall, fields[0] == "d"); err != nil {
+				PrintErrorMsg("%s", err)
+			}
+		case "bl":
+			bps, err := client.Breakpoint("", -1, "", false)
+			if err != nil {
+				PrintErrorMsg("%s", err)
+				continue
+			}
+			for i, bp := range bps {
+				PrintInfoMsg("  %d: %s", i, bp)
+			}
+		case "bt":
+			for i := len(state.Frames) - 1; i >= 0; i-- {
+				f := state.Frames[i]
+				PrintInfoMsg("  #%d %s:%d %s %s", len(state.Frames)-1-i, f.Contract, f.Offset, f.OpCode, f.Syscall)
+			}
+		case "stack":
+			printDebugItems(state.EvaluationStack)
+		case "alt":
+			printDebugItems(state.AltStack)
+		case "storage":
+			for _, item := range state.Storage {
+				PrintInfoMsg("  %s %s => %s", item.Contract, item.Key, item.Value)
+			}
+		case "notify":
+			for _, n := range state.Notify {
+				data, _ := json.Marshal(n.States)
+				PrintInfoMsg("  %s %s", n.ContractAddress, data)
+			}
+		case "q", "quit":
+			_, err := client.Quit()
+			return err
+		default:
+			PrintErrorMsg("Unknown command:%s", fields[0])
+		}
+	}
+}
+
+//parseBreakpoint parse breakpoint of <offset>, <contract>:<offset> or syscall:<name>
+func parseBreakpoint(str string) (string, int, string, error) {
+	contract := ""
+	index := strings.LastIndex(str, ":")
+	if index >= 0 {
+		if str[:index] == "syscall" {
+			return "", -1, str[index+1:], nil
+		}
+		contract = str[:index]
+	}
+	offset, err := strconv.Atoi(str[index+1:])
+	if err != nil || offset < 0 {
+		return "", -1, "", fmt.Errorf("invalid breakpoint offset:%s", str[index+1:])
+	}
+	return contract, offset, "", nil
+}
+
+func printDebugPosition(state *debugger.State) {
+	if state.Finished {
+		PrintInfoMsg("Execution finished")
+		if state.Error != "" {
+			PrintInfoMsg("  Error:%s", state.Error)
+		} else {
+			data, _ := json.Marshal(state.Result)
+			PrintInfoMsg("  Result:%s", data)
+		}
+		return
+	}
+	if len(state.Frames) == 0 {
+		return
+	}
+	f := state.Frames[len(state.Frames)-1]
+	PrintInfoMsg("Paused(%s) at %s:%d %s %s", state.Reason, f.Contract, f.Offset, f.OpCode, f.Syscall)
+}
+
+func printDebugItems(items []interface{}) {
+	for i, item := range items {
+		data, _ := json.Marshal(item)
+		PrintInfoMsg("  %d: %s", i, data)
+	}
+}
@@ -0,0 +1,271 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/ontio/ontology/cmd/utils"
+	"github.com/ontio/ontology/common"
+	"github.com/urfave/cli"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+var DevnetCommand = cli.Command{
+	Action:    cli.ShowSubcommandHelp,
+	Name:      "devnet",
+	Usage:     "Manage a local multi-node VBFT network",
+	ArgsUsage: "[arguments...]",
+	Description: `Devnet commands generate node keys and a VBFT genesis config, and run several consensus nodes on localhost.
+Every node runs as a child process of the ontology binary with its own directory under devnet dir.`,
+	Subcommands: []cli.Command{
+		{
+			Action:    devnetInit,
+			Name:      "init",
+			Usage:     "Generate node keys, wallets and VBFT genesis config",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.DevnetDirFlag,
+				utils.DevnetNodesFlag,
+				utils.DevnetBasePortFlag,
+				utils.DevnetSeedFlag,
+			},
+		},
+		{
+			Action:    devnetStart,
+			Name:      "start",
+			Usage:     "Start all nodes of devnet",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.DevnetDirFlag,
+				utils.LogLevelFlag,
+			},
+		},
+		{
+			Action:    devnetStop,
+			Name:      "stop",
+			Usage:     "Stop all nodes of devnet",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.DevnetDirFlag,
+			},
+		},
+		{
+			Action:    devnetReset,
+			Name:      "reset",
+			Usage:     "Stop all nodes and remove chain data, keys and genesis config are kept",
+			ArgsUsage: "[sub-command options]",
+			Flags: []cli.Flag{
+				utils.DevnetDirFlag,
+			},
+		},
+	},
+}
+
+func devnetInit(ctx *cli.Context) error {
+	dir, err := filepath.Abs(ctx.String(utils.GetFlagName(utils.DevnetDirFlag)))
+	if err != nil {
+		return fmt.Errorf("get devnet dir error:%s", err)
+	}
+	cfgFile := filepath.Join(dir, utils.DEVNET_CONFIG_FILE)
+	if common.FileExisted(cfgFile) {
+		return fmt.Errorf("devnet already exists in %s, please use reset or remove it first", dir)
+	}
+	seed := ctx.String(utils.GetFlagName(utils.DevnetSeedFlag))
+	if seed == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("generate seed error:%s", err)
+		}
+		seed = hex.EncodeToString(buf)
+	}
+	nodes := uint32(ctx.Uint(utils.GetFlagName(utils.DevnetNodesFlag)))
+	basePort := ctx.Uint(utils.GetFlagName(utils.DevnetBasePortFlag))
+
+	cfg := utils.NewDevnetConfig(dir, seed, nodes, basePort)
+	genesis, err := utils.NewDevnetGenesis(cfg)
+	if err != nil {
+		return err
+	}
+	for _, node := range cfg.Nodes {
+		if err := os.MkdirAll(node.Dir, 0755); err != nil {
+			return fmt.Errorf("create node dir:%s error:%s", node.Dir, err)
+		}
+		walletFile := filepath.Join(node.Dir, utils.DEFAULT_DEVNET_WALLET_FILE)
+		err = utils.NewDevnetWallet(walletFile, utils.DevnetPrivateKey(seed, node.Index), []byte(cfg.Password))
+		if err != nil {
+			return fmt.Errorf("create wallet of node %d error:%s", node.Index, err)
+		}
+	}
+	if err := writeJsonFile(filepath.Join(dir, utils.DEVNET_GENESIS_FILE), genesis); err != nil {
+		return err
+	}
+	if err := writeJsonFile(cfgFile, cfg); err != nil {
+		return err
+	}
+
+	PrintInfoMsg("Devnet initialized in %s, seed:%s", dir, seed)
+	for _, node := range cfg.Nodes {
+		PrintInfoMsg("  node%d address:%s nodeport:%d rpcport:%d", node.Index, node.Address, node.NodePort, node.RpcPort)
+	}
+	return nil
+}
+
+func devnetStart(ctx *cli.Context) error {
+	dir, cfg, err := loadDevnetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable error:%s", err)
+	}
+	genesisFile := filepath.Join(dir, utils.DEVNET_GENESIS_FILE)
+	logLevel := ctx.Uint(utils.GetFlagName(utils.LogLevelFlag))
+	for _, node := range cfg.Nodes {
+		if isProcessAlive(node.Pid) {
+			PrintWarnMsg("node%d is already running, pid:%d", node.Index, node.Pid)
+			continue
+		}
+		args := []string{
+			fmt.Sprintf("--%s=%s", utils.GetFlagName(utils.ConfigFlag), genesisFile),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.LogLevelFlag), logLevel),
+			fmt.Sprintf("--%s=%s", utils.GetFlagName(utils.AccountPassFlag), cfg.Password),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.NetworkIdFlag), cfg.NetworkId),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.NodePortFlag), node.NodePort),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.MaxConnInBoundForSingleIPFlag), 2*len(cfg.Nodes)),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.RPCPortFlag), node.RpcPort),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.RPCLocalProtFlag), node.LocalRpcPort),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.RestfulPortFlag), node.RestPort),
+			fmt.Sprintf("--%s=%d", utils.GetFlagName(utils.WsPortFlag), node.WsPort),
+			fmt.Sprintf("--%s=0", utils.GetFlagName(utils.GasPriceFlag)),
+			"--" + utils.GetFlagName(utils.EnableConsensusFlag),
+			"--" + utils.GetFlagName(utils.RPCLocalEnableFlag),
+			"--" + utils.GetFlagName(utils.RestfulEnableFlag),
+			"--" + utils.GetFlagName(utils.WsEnabledFlag),
+		}
+		logFile, err := os.OpenFile(filepath.Join(node.Dir, utils.DEVNET_NODE_LOG_FILE), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("open log file of node%d error:%s", node.Index, err)
+		}
+		proc := exec.Command(exe, args...)
+		proc.Dir = node.Dir
+		proc.Stdout = logFile
+		proc.Stderr = logFile
+		err = proc.Start()
+		logFile.Close()
+		if err != nil {
+			return fmt.Errorf("start node%d error:%s", node.Index, err)
+		}
+		node.Pid = proc.Process.Pid
+		proc.Process.Release()
+		PrintInfoMsg("node%d started, pid:%d", node.Index, node.Pid)
+	}
+	return writeJsonFile(filepath.Join(dir, utils.DEVNET_CONFIG_FILE), cfg)
+}
+
+func devnetStop(ctx *cli.Context) error {
+	dir, cfg, err := loadDevnetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	stopDevnetNodes(cfg)
+	return writeJsonFile(filepath.Join(dir, utils.DEVNET_CONFIG_FILE), cfg)
+}
+
+func devnetReset(ctx *cli.Context) error {
+	dir, cfg, err := loadDevnetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	stopDevnetNodes(cfg)
+	for _, node := range cfg.Nodes {
+		for _, name := range []string{utils.DEFAULT_DEVNET_DATA_DIR, utils.DEFAULT_DEVNET_LOG_DIR, utils.DEVNET_NODE_LOG_FILE} {
+			if err := os.RemoveAll(filepath.Join(node.Dir, name)); err != nil {
+				return fmt.Errorf("remove %s of node%d error:%s", name, node.Index, err)
+			}
+		}
+	}
+	PrintInfoMsg("Devnet in %s has been reset", dir)
+	return writeJsonFile(filepath.Join(dir, utils.DEVNET_CONFIG_FILE), cfg)
+}
+
+func stopDevnetNodes(cfg *utils.DevnetConfig) {
+	for _, node := range cfg.Nodes {
+		if !isProcessAlive(node.Pid) {
+			node.Pid = 0
+			continue
+		}
+		proc, _ := os.FindProcess(node.Pid)
+		if err := proc.Signal(os.Interrupt); err != nil {
+			proc.Kill()
+		}
+		for i := 0; i < 100 && isProcessAlive(node.Pid); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if isProcessAlive(node.Pid) {
+			PrintWarnMsg("node%d does not exit in time, kill it", node.Index)
+			proc.Kill()
+		}
+		PrintInfoMsg("node%d stopped, pid:%d", node.Index, node.Pid)
+		node.Pid = 0
+	}
+}
+
+func isProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+func loadDevnetConfig(ctx *cli.Context) (string, *utils.DevnetConfig, error) {
+	dir, err := filepath.Abs(ctx.String(utils.GetFlagName(utils.DevnetDirFlag)))
+	if err != nil {
+		return "", nil, fmt.Errorf("get devnet dir error:%s", err)
+	}
+	cfg := &utils.DevnetConfig{}
+	err = utils.GetJsonObjectFromFile(filepath.Join(dir, utils.DEVNET_CONFIG_FILE), cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("load devnet config error:%s, please run devnet init first", err)
+	}
+	return dir, cfg, nil
+}
+
+func writeJsonFile(file string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json.Marshal error:%s", err)
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("write file:%s error:%s", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	cmdcom "github.com/ontio/ontology/cmd/common"
+	"github.com/ontio/ontology/cmd/utils"
+	"github.com/urfave/cli"
+	"strconv"
+)
+
+var GovernanceCommand = cli.Command{
+	Action:    cli.ShowSubcommandHelp,
+	Name:      "governance",
+	Usage:     "Display governance information",
+	ArgsUsage: "[arguments...]",
+	Description: `Governance commands display the peer pool, authorizations, withdrawable ONT/ONG and fee split
+history of the governance contract.`,
+	Subcommands: []cli.Command{
+		{
+			Action:    peerPool,
+			Name:      "peers",
+			Usage:     "Display peers of current governance view with status and stake",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:    authorizeInfo,
+			Name:      "authorization",
+			Usage:     "Display authorizations of account to each peer",
+			ArgsUsage: "<address|label|index>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.WalletFileFlag,
+			},
+		},
+		{
+			Action:    withdrawable,
+			Name:      "withdrawable",
+			Usage:     "Display ONT and ONG of account which can be withdrawn from governance contract",
+			ArgsUsage: "<address|label|index>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.WalletFileFlag,
+			},
+		},
+		{
+			Action:    splitFeeHistory,
+			Name:      "splitfee",
+			Usage:     "Display ONG split to peers and stakers of governance views",
+			ArgsUsage: "<startView> [endView]",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+	},
+}
+
+func peerPool(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	data, err := utils.GetPeerPool()
+	if err != nil {
+		return fmt.Errorf("GetPeerPool error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func authorizeInfo(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing account argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	accAddr, err := cmdcom.ParseAddress(ctx.Args().First(), ctx)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetAuthorizeInfo(accAddr)
+	if err != nil {
+		return fmt.Errorf("GetAuthorizeInfo error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func withdrawable(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing account argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	accAddr, err := cmdcom.ParseAddress(ctx.Args().First(), ctx)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetWithdrawable(accAddr)
+	if err != nil {
+		return fmt.Errorf("GetWithdrawable error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func splitFeeHistory(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing argument,start view expected.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	start, err := strconv.ParseUint(ctx.Args().First(), 10, 32)
+	if err != nil {
+		return fmt.Errorf("arg:%s invalid view", ctx.Args().First())
+	}
+	end := start
+	if ctx.NArg() > 1 {
+		end, err = strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+		if err != nil {
+			return fmt.Errorf("arg:%s invalid view", ctx.Args().Get(1))
+		}
+	}
+	data, err := utils.GetSplitFeeHistory(uint32(start), uint32(end))
+	if err != nil {
+		return fmt.Errorf("GetSplitFeeHistory error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
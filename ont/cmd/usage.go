@@ -101,6 +101,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.LogLevelFlag,
 			utils.DisableEventLogFlag,
 			utils.DataDirFlag,
+			utils.LightClientFlag,
 		},
 	},
 	{
@@ -155,6 +156,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MaxConnInBoundFlag,
 			utils.MaxConnOutBoundFlag,
 			utils.MaxConnInBoundForSingleIPFlag,
+			utils.DisableMsgCompressFlag,
 		},
 	},
 	{
@@ -178,6 +180,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.WsEnabledFlag,
 			utils.WsPortFlag,
+			utils.WsDebugFlag,
 		},
 	},
 	{
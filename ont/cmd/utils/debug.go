@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+	"github.com/ontio/ontology/smartcontract/debugger"
+)
+
+//WsResponse object response of web socket action
+type WsResponse struct {
+	Action string          `json:"Action"`
+	Error  int64           `json:"Error"`
+	Desc   string          `json:"Desc"`
+	Result json.RawMessage `json:"Result"`
+}
+
+//DebugClient is a contract debug session over web socket
+type DebugClient struct {
+	conn *websocket.Conn
+}
+
+func NewDebugClient(port uint) (*DebugClient, error) {
+	addr := fmt.Sprintf("ws://localhost:%d", port)
+	conn, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s error:%s", addr, err)
+	}
+	return &DebugClient{conn: conn}, nil
+}
+
+func (this *DebugClient) Close() error {
+	return this.conn.Close()
+}
+
+//Start begin to debug the transaction, which pause before the first instruction
+func (this *DebugClient) Start(txData string) (*debugger.State, error) {
+	return this.requestState(map[string]interface{}{"Action": "debugstart", "Data": txData})
+}
+
+//Resume continue the execution with mode step, next or continue
+func (this *DebugClient) Resume(mode string) (*debugger.State, error) {
+	return this.requestState(map[string]interface{}{"Action": "debugresume", "Mode": mode})
+}
+
+//Quit abort the execution
+func (this *DebugClient) Quit() (*debugger.State, error) {
+	return this.requestState(map[string]interface{}{"Action": "debugquit"})
+}
+
+//Breakpoint add or remove breakpoint, an empty breakpoint only list all breakpoints
+func (this *DebugClient) Breakpoint(contract string, offset int, syscall string, remove bool) ([]string, error) {
+	req := map[string]interface{}{"Action": "debugbreakpoint", "Remove": remove}
+	if syscall != "" {
+		req["Syscall"] = syscall
+	} else if offset >= 0 {
+		req["Offset"] = offset
+		req["Contract"] = contract
+	}
+	data, err := this.request(req)
+	if err != nil {
+		return nil, err
+	}
+	var bps []string
+	if err := json.Unmarshal(data, &bps); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal breakpoints:%s error:%s", data, err)
+	}
+	return bps, nil
+}
+
+func (this *DebugClient) requestState(req map[string]interface{}) (*debugger.State, error) {
+	data, err := this.request(req)
+	if err != nil {
+		return nil, err
+	}
+	state := &debugger.State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal debug state:%s error:%s", data, err)
+	}
+	return state, nil
+}
+
+func (this *DebugClient) request(req map[string]interface{}) (json.RawMessage, error) {
+	if err := this.conn.WriteJSON(req); err != nil {
+		return nil, fmt.Errorf("send %s error:%s", req["Action"], err)
+	}
+	for {
+		rsp := &WsResponse{}
+		if err := this.conn.ReadJSON(rsp); err != nil {
+			return nil, fmt.Errorf("read %s response error:%s", req["Action"], err)
+		}
+		//skip the pushed messages of other action
+		if rsp.Action != req["Action"] {
+			continue
+		}
+		if rsp.Error != 0 {
+			return nil, fmt.Errorf("%s error:%d %s %s", rsp.Action, rsp.Error, rsp.Desc, rsp.Result)
+		}
+		return rsp.Result, nil
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ontio/ontology-crypto/ec"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	DEFAULT_DEVNET_DIR        = "./devnet"
+	DEFAULT_DEVNET_NODES      = 7
+	DEFAULT_DEVNET_BASE_PORT  = 30000
+	DEFAULT_DEVNET_NETWORK_ID = 299
+	DEFAULT_DEVNET_PASSWORD   = "devnet"
+
+	DEVNET_CONFIG_FILE   = "devnet.json"
+	DEVNET_GENESIS_FILE  = "genesis.json"
+	DEVNET_NODE_LOG_FILE = "node.log"
+	DEVNET_PORT_STEP     = 10
+	DEVNET_INIT_POS      = 10000
+
+	//nodes run in their own dir, so they use default relative paths
+	DEFAULT_DEVNET_WALLET_FILE = config.DEFAULT_WALLET_FILE_NAME
+	DEFAULT_DEVNET_DATA_DIR    = config.DEFAULT_DATA_DIR
+	DEFAULT_DEVNET_LOG_DIR     = log.PATH
+)
+
+//DevnetNode is the key and ports of a node in local devnet
+type DevnetNode struct {
+	Index        uint32
+	Address      string
+	PeerPubkey   string
+	Dir          string
+	NodePort     uint
+	RpcPort      uint
+	LocalRpcPort uint
+	RestPort     uint
+	WsPort       uint
+	Pid          int
+}
+
+//DevnetConfig is saved in devnet dir, used by start, stop and reset
+type DevnetConfig struct {
+	Seed      string
+	NetworkId uint32
+	Password  string
+	Nodes     []*DevnetNode
+}
+
+//DevnetPrivateKey derive the P-256 private key of node index from seed, so that same seed always gives same keys
+func DevnetPrivateKey(seed string, index uint32) keypair.PrivateKey {
+	d := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", seed, index)))
+	return &ec.PrivateKey{
+		Algorithm:  ec.ECDSA,
+		PrivateKey: ec.ConstructPrivateKey(d[:], elliptic.P256()),
+	}
+}
+
+//NewDevnetWallet create wallet file of node and import the node key into it
+func NewDevnetWallet(walletFile string, pri keypair.PrivateKey, passwd []byte) error {
+	wallet, err := account.Open(walletFile)
+	if err != nil {
+		return fmt.Errorf("open wallet error:%s", err)
+	}
+	pub := pri.Public()
+	address := types.AddressFromPubKey(pub)
+	k, err := keypair.EncryptPrivateKey(pri, address.ToBase58(), passwd)
+	if err != nil {
+		return fmt.Errorf("encrypt private key error:%s", err)
+	}
+	accMeta := &account.AccountMetadata{
+		Address: k.Address,
+		KeyType: k.Alg,
+		EncAlg:  k.EncAlg,
+		Hash:    k.Hash,
+		Key:     k.Key,
+		Curve:   k.Param["curve"],
+		Salt:    k.Salt,
+		PubKey:  hex.EncodeToString(keypair.SerializePublicKey(pub)),
+		SigSch:  signature.SHA256withECDSA.Name(),
+	}
+	return wallet.ImportAccount(accMeta)
+}
+
+//NewDevnetConfig generate node keys and ports of a devnet with n nodes under dir
+func NewDevnetConfig(dir, seed string, n uint32, basePort uint) *DevnetConfig {
+	cfg := &DevnetConfig{
+		Seed:      seed,
+		NetworkId: DEFAULT_DEVNET_NETWORK_ID,
+		Password:  DEFAULT_DEVNET_PASSWORD,
+		Nodes:     make([]*DevnetNode, 0, n),
+	}
+	for i := uint32(1); i <= n; i++ {
+		pub := DevnetPrivateKey(seed, i).Public()
+		address := types.AddressFromPubKey(pub)
+		port := basePort + uint(i-1)*DEVNET_PORT_STEP
+		cfg.Nodes = append(cfg.Nodes, &DevnetNode{
+			Index:        i,
+			Address:      address.ToBase58(),
+			PeerPubkey:   hex.EncodeToString(keypair.SerializePublicKey(pub)),
+			Dir:          filepath.Join(dir, fmt.Sprintf("node%d", i)),
+			NodePort:     port,
+			RpcPort:      port + 1,
+			LocalRpcPort: port + 2,
+			RestPort:     port + 3,
+			WsPort:       port + 4,
+		})
+	}
+	return cfg
+}
+
+//NewDevnetGenesis build VBFT genesis config for devnet, all nodes are consensus peers
+func NewDevnetGenesis(cfg *DevnetConfig) (*config.GenesisConfig, error) {
+	n := uint32(len(cfg.Nodes))
+	if n < 7 {
+		return nil, fmt.Errorf("VBFT devnet needs at least 7 nodes")
+	}
+	// the genesis vrf is evaluated by the first node, so it can be verified by its public key
+	vrfValue, vrfProof, err := vrf.Vrf(DevnetPrivateKey(cfg.Seed, 1), []byte(cfg.Seed))
+	if err != nil {
+		return nil, fmt.Errorf("compute vrf error:%s", err)
+	}
+
+	genesis := config.NewGenesisConfig()
+	genesis.ConsensusType = config.CONSENSUS_TYPE_VBFT
+	genesis.SeedList = make([]string, 0, n)
+	genesis.VBFT = &config.VBFTConfig{
+		N:                    n,
+		C:                    (n - 1) / 3,
+		K:                    n,
+		L:                    16 * n,
+		BlockMsgDelay:        10000,
+		HashMsgDelay:         10000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   3000,
+		MinInitStake:         DEVNET_INIT_POS,
+		AdminOntID:           "did:ont:" + cfg.Nodes[0].Address,
+		VrfValue:             hex.EncodeToString(vrfValue),
+		VrfProof:             hex.EncodeToString(vrfProof),
+		Peers:                make([]*config.VBFTPeerStakeInfo, 0, n),
+	}
+	for _, node := range cfg.Nodes {
+		genesis.SeedList = append(genesis.SeedList, fmt.Sprintf("127.0.0.1:%d", node.NodePort))
+		genesis.VBFT.Peers = append(genesis.VBFT.Peers, &config.VBFTPeerStakeInfo{
+			Index:      node.Index,
+			PeerPubkey: node.PeerPubkey,
+			Address:    node.Address,
+			InitPos:    DEVNET_INIT_POS,
+		})
+	}
+	return genesis, nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevnetConfig(t *testing.T) {
+	cfg1 := NewDevnetConfig("/tmp/devnet", "seed", 7, 30000)
+	cfg2 := NewDevnetConfig("/tmp/devnet", "seed", 7, 30000)
+	assert.Equal(t, cfg1, cfg2)
+	assert.Equal(t, 7, len(cfg1.Nodes))
+	assert.Equal(t, uint(30060), cfg1.Nodes[6].NodePort)
+	assert.NotEqual(t, cfg1.Nodes[0].PeerPubkey, cfg1.Nodes[1].PeerPubkey)
+
+	cfg3 := NewDevnetConfig("/tmp/devnet", "other", 7, 30000)
+	assert.NotEqual(t, cfg1.Nodes[0].PeerPubkey, cfg3.Nodes[0].PeerPubkey)
+}
+
+func TestDevnetGenesis(t *testing.T) {
+	_, err := NewDevnetGenesis(NewDevnetConfig("/tmp/devnet", "seed", 4, 30000))
+	assert.NotNil(t, err)
+
+	cfg := NewDevnetConfig("/tmp/devnet", "seed", 7, 30000)
+	genesis, err := NewDevnetGenesis(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, governance.CheckVBFTConfig(genesis.VBFT))
+	assert.Equal(t, 7, len(genesis.SeedList))
+
+	vrfValue, _ := hex.DecodeString(genesis.VBFT.VrfValue)
+	vrfProof, _ := hex.DecodeString(genesis.VBFT.VrfProof)
+	pubBytes, _ := hex.DecodeString(cfg.Nodes[0].PeerPubkey)
+	pub, err := keypair.DeserializePublicKey(pubBytes)
+	assert.Nil(t, err)
+	ok, err := vrf.Verify(pub, []byte(cfg.Seed), vrfValue, vrfProof)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
@@ -68,6 +68,10 @@ var (
 		Usage: "Block data storage `<path>`",
 		Value: config.DEFAULT_DATA_DIR,
 	}
+	LightClientFlag = cli.BoolFlag{
+		Name:  "light",
+		Usage: "Run as light client, only sync and verify block headers. Consensus will be disabled",
+	}
 
 	//Consensus setting
 	EnableConsensusFlag = cli.BoolFlag{
@@ -145,6 +149,10 @@ var (
 		Usage: "Max connection `<number>` in bound for single ip",
 		Value: config.DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
 	}
+	DisableMsgCompressFlag = cli.BoolFlag{
+		Name:  "disable-msg-compress",
+		Usage: "Disable compression of large block, headers and consensus messages to peers",
+	}
 	// RPC settings
 	RPCDisabledFlag = cli.BoolFlag{
 		Name:  "disable-rpc",
@@ -175,6 +183,10 @@ var (
 		Usage: "Ws server listening port `<number>`",
 		Value: config.DEFAULT_WS_PORT,
 	}
+	WsDebugFlag = cli.BoolFlag{
+		Name:  "wsdebug",
+		Usage: "Enable contract debug session on web socket server",
+	}
 
 	//Restful setting
 	RestfulEnableFlag = cli.BoolFlag{
@@ -312,6 +324,10 @@ var (
 		Name:  "prepare,p",
 		Usage: "Prepare invoke contract without commit to ledger",
 	}
+	ContractProfileFlag = cli.BoolFlag{
+		Name:  "profile",
+		Usage: "Print gas breakdown by opcode, syscall, native call and contract of prepare invoke",
+	}
 	ContractReturnTypeFlag = cli.StringFlag{
 		Name:  "return",
 		Usage: "Return `<type>` of contract. bytearray(hexstring), string, integer, boolean",
@@ -480,6 +496,27 @@ var (
 		Usage: "Disable broadcast tx from network in tx pool",
 	}
 
+	//Devnet setting
+	DevnetDirFlag = cli.StringFlag{
+		Name:  "dir",
+		Usage: "Devnet `<path>` which keeps genesis config, wallets and data of nodes",
+		Value: DEFAULT_DEVNET_DIR,
+	}
+	DevnetNodesFlag = cli.UintFlag{
+		Name:  "nodes",
+		Usage: "Number of consensus nodes `<number>` in devnet, at least 7",
+		Value: DEFAULT_DEVNET_NODES,
+	}
+	DevnetBasePortFlag = cli.UintFlag{
+		Name:  "base-port",
+		Usage: "First listening port `<number>` of devnet, every node uses 10 ports from it",
+		Value: DEFAULT_DEVNET_BASE_PORT,
+	}
+	DevnetSeedFlag = cli.StringFlag{
+		Name:  "seed",
+		Usage: "Seed `<string>` to derive node keys, same seed gives same keys. If not specified, use a random seed",
+	}
+
 	NonOptionFlag = cli.StringFlag{
 		Name:  "option",
 		Usage: "this command does not need option, please run directly",
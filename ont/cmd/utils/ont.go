@@ -462,7 +462,16 @@ func SendRawTransactionData(txData string) (string, error) {
 }
 
 func PrepareSendRawTransaction(txData string) (*cstates.PreExecResult, error) {
-	data, ontErr := sendRpcRequest("sendrawtransaction", []interface{}{txData, 1})
+	return prepareSendRawTransaction([]interface{}{txData, 1})
+}
+
+//ProfileSendRawTransaction pre-execute transaction with the gas breakdown of neovm execution
+func ProfileSendRawTransaction(txData string) (*cstates.PreExecResult, error) {
+	return prepareSendRawTransaction([]interface{}{txData, 1, 1})
+}
+
+func prepareSendRawTransaction(params []interface{}) (*cstates.PreExecResult, error) {
+	data, ontErr := sendRpcRequest("sendrawtransaction", params)
 	if ontErr != nil {
 		return nil, ontErr.Error
 	}
@@ -528,6 +537,50 @@ func GetBlock(hashOrHeight interface{}) ([]byte, error) {
 	return nil, ontErr.Error
 }
 
+func GetPeerPool() ([]byte, error) {
+	data, ontErr := sendRpcRequest("getpeerpool", []interface{}{})
+	if ontErr != nil {
+		return nil, ontErr.Error
+	}
+	return data, nil
+}
+
+func GetAuthorizeInfo(address string) ([]byte, error) {
+	data, ontErr := sendRpcRequest("getauthorizeinfo", []interface{}{address})
+	if ontErr == nil {
+		return data, nil
+	}
+	switch ontErr.ErrorCode {
+	case ERROR_INVALID_PARAMS:
+		return nil, fmt.Errorf("invalid address:%s", address)
+	}
+	return nil, ontErr.Error
+}
+
+func GetWithdrawable(address string) ([]byte, error) {
+	data, ontErr := sendRpcRequest("getwithdrawable", []interface{}{address})
+	if ontErr == nil {
+		return data, nil
+	}
+	switch ontErr.ErrorCode {
+	case ERROR_INVALID_PARAMS:
+		return nil, fmt.Errorf("invalid address:%s", address)
+	}
+	return nil, ontErr.Error
+}
+
+func GetSplitFeeHistory(start, end uint32) ([]byte, error) {
+	data, ontErr := sendRpcRequest("getsplitfeehistory", []interface{}{start, end})
+	if ontErr == nil {
+		return data, nil
+	}
+	switch ontErr.ErrorCode {
+	case ERROR_INVALID_PARAMS:
+		return nil, fmt.Errorf("invalid view range:[%d, %d]", start, end)
+	}
+	return nil, ontErr.Error
+}
+
 func GetNetworkId() (uint32, error) {
 	data, ontErr := sendRpcRequest("getnetworkid", []interface{}{})
 	if ontErr != nil {
@@ -723,6 +776,7 @@ func InvokeSmartContract(signer *account.Account, tx *types.MutableTransaction)
 func PrepareInvokeNeoVMContract(
 	contractAddress common.Address,
 	params []interface{},
+	profile bool,
 ) (*cstates.PreExecResult, error) {
 	mutable, err := httpcom.NewNeovmInvokeTransaction(0, 0, contractAddress, params)
 	if err != nil {
@@ -740,10 +794,13 @@ func PrepareInvokeNeoVMContract(
 		return nil, fmt.Errorf("tx serialize error:%s", err)
 	}
 	txData := hex.EncodeToString(buffer.Bytes())
+	if profile {
+		return ProfileSendRawTransaction(txData)
+	}
 	return PrepareSendRawTransaction(txData)
 }
 
-func PrepareInvokeCodeNeoVMContract(code []byte) (*cstates.PreExecResult, error) {
+func PrepareInvokeCodeNeoVMContract(code []byte, profile bool) (*cstates.PreExecResult, error) {
 	mutable, err := httpcom.NewSmartContractTransaction(0, 0, code)
 	if err != nil {
 		return nil, err
@@ -758,6 +815,9 @@ func PrepareInvokeCodeNeoVMContract(code []byte) (*cstates.PreExecResult, error)
 		return nil, fmt.Errorf("tx serialize error:%s", err)
 	}
 	txData := hex.EncodeToString(buffer.Bytes())
+	if profile {
+		return ProfileSendRawTransaction(txData)
+	}
 	return PrepareSendRawTransaction(txData)
 }
 
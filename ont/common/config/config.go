@@ -95,6 +95,13 @@ var NETWORK_NAME = map[uint32]string{
 	NETWORK_ID_SOLO_NET:    NETWORK_NAME_SOLO_NET,
 }
 
+//NATIVE_UPGRADE_HEIGHT is the height since which the upgraded native contract features are enabled,
+//networks not listed enable them from genesis
+var NATIVE_UPGRADE_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET:    constants.NATIVE_UPGRADE_HEIGHT_MAINNET, //Network main
+	NETWORK_ID_POLARIS_NET: constants.NATIVE_UPGRADE_HEIGHT_POLARIS, //Network polaris
+}
+
 func GetNetworkMagic(id uint32) uint32 {
 	nid, ok := NETWORK_MAGIC[id]
 	if ok {
@@ -111,6 +118,11 @@ func GetNetworkName(id uint32) string {
 	return fmt.Sprintf("%d", id)
 }
 
+//GetNativeUpgradeHeight return the native contract upgrade height of the network node running on
+func GetNativeUpgradeHeight() uint32 {
+	return NATIVE_UPGRADE_HEIGHT[DefConfig.P2PNode.NetworkId]
+}
+
 var PolarisConfig = &GenesisConfig{
 	SeedList: []string{
 		"polaris1.ont.io:20338",
@@ -471,13 +483,14 @@ type SOLOConfig struct {
 }
 
 type CommonConfig struct {
-	LogLevel       uint
-	NodeType       string
-	EnableEventLog bool
-	SystemFee      map[string]int64
-	GasLimit       uint64
-	GasPrice       uint64
-	DataDir        string
+	LogLevel          uint
+	NodeType          string
+	EnableEventLog    bool
+	SystemFee         map[string]int64
+	GasLimit          uint64
+	GasPrice          uint64
+	DataDir           string
+	EnableLightClient bool
 }
 
 type ConsensusConfig struct {
@@ -508,6 +521,7 @@ type P2PNodeConfig struct {
 	MaxConnInBound            uint
 	MaxConnOutBound           uint
 	MaxConnInBoundForSingleIP uint
+	EnableMsgCompress         bool
 }
 
 type RpcConfig struct {
@@ -528,6 +542,7 @@ type WebSocketConfig struct {
 	HttpWsPort   uint
 	HttpCertPath string
 	HttpKeyPath  string
+	EnableDebug  bool
 }
 
 type OntologyConfig struct {
@@ -572,6 +587,7 @@ func NewOntologyConfig() *OntologyConfig {
 			MaxConnInBound:            DEFAULT_MAX_CONN_IN_BOUND,
 			MaxConnOutBound:           DEFAULT_MAX_CONN_OUT_BOUND,
 			MaxConnInBoundForSingleIP: DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
+			EnableMsgCompress:         true,
 		},
 		Rpc: &RpcConfig{
 			EnableHttpJsonRpc: true,
@@ -78,3 +78,11 @@ const (
 	NETWORK_MAGIC_MAINNET = 0x8c77ab60
 	NETWORK_MAGIC_POLARIS = 0x2d8829df
 )
+
+// native contract upgrade height, the features added to native contracts are enabled since it.
+// It must be ahead of the current height of the network when released, so that blocks before it
+// are replayed as the old nodes did
+const (
+	NATIVE_UPGRADE_HEIGHT_MAINNET = 8000000
+	NATIVE_UPGRADE_HEIGHT_POLARIS = 6000000
+)
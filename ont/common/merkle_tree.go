@@ -19,6 +19,7 @@ package common
  */
 import (
 	"crypto/sha256"
+	"fmt"
 )
 
 // param hashes will be used as workspace
@@ -57,3 +58,53 @@ func ComputeMerkleRoot(hashes []Uint256) Uint256 {
 
 	return hashes[0]
 }
+
+// MerkleProof return the sibling hashes on the path from hashes[index] to the root
+// computed by ComputeMerkleRoot, ordered from leaf to root
+func MerkleProof(hashes []Uint256, index uint32) ([]Uint256, error) {
+	if int(index) >= len(hashes) {
+		return nil, fmt.Errorf("index %d out of range, total %d", index, len(hashes))
+	}
+	level := make([]Uint256, len(hashes))
+	copy(level, hashes)
+	var proof []Uint256
+	for len(level) != 1 {
+		sibling := index ^ 1
+		if int(sibling) >= len(level) {
+			sibling = index
+		}
+		proof = append(proof, level[sibling])
+
+		next := make([]Uint256, (len(level)+1)/2)
+		for i := range next {
+			left := level[2*i]
+			right := left
+			if 2*i+1 < len(level) {
+				right = level[2*i+1]
+			}
+			next[i] = hashMerkleChildren(left, right)
+		}
+		level = next
+		index /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof check the proof generated by MerkleProof against root
+func VerifyMerkleProof(leaf Uint256, index uint32, proof []Uint256, root Uint256) bool {
+	hash := leaf
+	for _, sibling := range proof {
+		if index%2 == 0 {
+			hash = hashMerkleChildren(hash, sibling)
+		} else {
+			hash = hashMerkleChildren(sibling, hash)
+		}
+		index /= 2
+	}
+	return index == 0 && hash == root
+}
+
+func hashMerkleChildren(left, right Uint256) Uint256 {
+	temp := sha256.Sum256(append(left[:], right[:]...))
+	return Uint256(sha256.Sum256(temp[:]))
+}
@@ -82,6 +82,29 @@ func TestComputeMerkleRoot(t *testing.T) {
 	}
 }
 
+func TestMerkleProof(t *testing.T) {
+	for n := 1; n < 40; n++ {
+		data := make([]Uint256, n)
+		for i := range data {
+			data[i] = Uint256(sha256.Sum256([]byte(fmt.Sprint(i))))
+		}
+		leaves := make([]Uint256, n)
+		copy(leaves, data)
+		root := ComputeMerkleRoot(data)
+
+		for i := 0; i < n; i++ {
+			proof, err := MerkleProof(leaves, uint32(i))
+			assert.Nil(t, err)
+			assert.True(t, VerifyMerkleProof(leaves[i], uint32(i), proof, root))
+			if n > 1 {
+				assert.False(t, VerifyMerkleProof(leaves[i], uint32((i+1)%n), proof, root))
+			}
+		}
+	}
+	_, err := MerkleProof(nil, 0)
+	assert.NotNil(t, err)
+}
+
 func doubleSha256(s []Uint256) Uint256 {
 	b := new(bytes.Buffer)
 	for _, d := range s {
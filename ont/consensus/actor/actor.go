@@ -64,6 +64,11 @@ func (self *TxPoolActor) VerifyBlock(txs []*types.Transaction, height uint32) er
 	return nil
 }
 
+//AppendTx submit tx to txnpool, tx will be broadcasted after verified
+func (self *TxPoolActor) AppendTx(tx *types.Transaction) {
+	self.Pool.Tell(&txpool.TxReq{Tx: tx, Sender: txpool.HttpSender})
+}
+
 type P2PActor struct {
 	P2P *actor.PID
 }
@@ -28,3 +28,13 @@ type TimeOut struct{}
 type BlockCompleted struct {
 	Block *types.Block
 }
+
+//GetConsensusMetricsReq request consensus timeline of the last Count blocks
+type GetConsensusMetricsReq struct {
+	Count uint32
+}
+
+//GetConsensusMetricsRsp response of consensus metrics
+type GetConsensusMetricsRsp struct {
+	Metrics interface{}
+}
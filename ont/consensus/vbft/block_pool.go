@@ -53,6 +53,9 @@ type CandidateInfo struct {
 
 	commitDone bool
 
+	// peers reported for equivocation in this round
+	reported map[uint32]bool
+
 	// server sealed block for this round
 	SealedBlock *Block
 
@@ -717,6 +720,9 @@ func (pool *BlockPool) findConsensusEmptyProposal(blockNum uint32) (*blockPropos
 	if maxCnt > 0 {
 		for _, p := range c.Proposals {
 			if p.Block.getProposer() == maxEndorsedProposer {
+				if pool.server != nil && pool.server.metrics != nil {
+					pool.server.metrics.onEmptyProposal(blockNum, maxEndorsedProposer)
+				}
 				return p, nil
 			}
 		}
@@ -48,6 +48,14 @@ func (self *ChainStore) GetChainedBlockNum() uint32 {
 	return self.chainedBlockNum
 }
 
+//GetStorageItem return the storage item in the state after block blkNum, which must be the latest block of ledger
+func (self *ChainStore) GetStorageItem(blkNum uint32, contract common.Address, key []byte) ([]byte, error) {
+	if height := self.db.GetCurrentBlockHeight(); height != blkNum {
+		return nil, fmt.Errorf("state of block %d is not available, ledger height %d", blkNum, height)
+	}
+	return self.db.GetStorageItem(contract, key)
+}
+
 func (self *ChainStore) ReloadFromLedger() {
 	height := self.db.GetCurrentBlockHeight()
 	if height > self.chainedBlockNum {
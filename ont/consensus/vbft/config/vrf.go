@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/vrf"
+)
+
+type vrfData struct {
+	BlockNum uint32 `json:"block_num"`
+	PrevVrf  []byte `json:"prev_vrf"`
+}
+
+// VrfData returns the VRF input of block blkNum, it is chained with the VRF value of the previous block
+func VrfData(blkNum uint32, prevVrf []byte) ([]byte, error) {
+	return json.Marshal(&vrfData{
+		BlockNum: blkNum,
+		PrevVrf:  prevVrf,
+	})
+}
+
+// VerifyVrf verifies newVrf is the VRF value of block blkNum computed by the owner of pk
+func VerifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byte) error {
+	data, err := VrfData(blkNum, prevVrf)
+	if err != nil {
+		return fmt.Errorf("verifyVrf failed to marshal vrfData: %s", err)
+	}
+
+	result, err := vrf.Verify(pk, data, newVrf, proof)
+	if err != nil {
+		return fmt.Errorf("verifyVrf failed: %s", err)
+	}
+	if !result {
+		return fmt.Errorf("verifyVrf failed")
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/ontio/ontology/smartcontract/service/native/scheduler"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//systemTxHashes return the hashes of the system transactions may be inserted by consensus at blkNum, in order
+func systemTxHashes(blkNum uint32) ([]common.Uint256, error) {
+	govTx, err := gover.SystemTxHash(nutils.GovernanceContractAddress, gover.COMMIT_DPOS, blkNum)
+	if err != nil {
+		return nil, err
+	}
+	schedulerTx, err := gover.SystemTxHash(nutils.SchedulerContractAddress, scheduler.EXECUTE_SCHEDULED, blkNum)
+	if err != nil {
+		return nil, err
+	}
+	return []common.Uint256{govTx, schedulerTx}, nil
+}
+
+func (candidate *CandidateInfo) setReported(peerIdx uint32) {
+	if candidate.reported == nil {
+		candidate.reported = make(map[uint32]bool)
+	}
+	candidate.reported[peerIdx] = true
+}
+
+//
+// build double proposal evidence if the proposer of msg has proposed another block at the same height
+//
+func (pool *BlockPool) getProposalEvidence(msg *blockProposalMsg) *gover.ReportEquivocationParam {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	blkNum := msg.GetBlockNum()
+	proposer := msg.Block.getProposer()
+	candidate := pool.candidateBlocks[blkNum]
+	if candidate == nil || candidate.reported[proposer] {
+		return nil
+	}
+
+	h2 := msg.Block.Block.Header
+	sysTxs, err := systemTxHashes(blkNum)
+	if err != nil {
+		log.Errorf("failed to get system txs of block %d: %s", blkNum, err)
+		return nil
+	}
+	for _, p := range candidate.Proposals {
+		if p.Block.getProposer() != proposer {
+			continue
+		}
+		h1 := p.Block.Block.Header
+		if h1.Hash() == h2.Hash() || gover.IsProposalPair(h1, h2, sysTxs) {
+			continue
+		}
+		candidate.setReported(proposer)
+		return &gover.ReportEquivocationParam{
+			EvidenceType: gover.DOUBLE_PROPOSAL,
+			Headers:      []*types.Header{h1, h2},
+			Sigs:         [][]byte{h1.SigData[0], h2.SigData[0]},
+		}
+	}
+	return nil
+}
+
+//
+// build double endorsement evidence if the endorser has signed more blocks of other proposers
+// than an honest node could sign at the same height
+//
+func (pool *BlockPool) getEndorseEvidence(blkNum uint32, endorser uint32, pubkey keypair.PublicKey) *gover.ReportEquivocationParam {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	candidate := pool.candidateBlocks[blkNum]
+	if candidate == nil || candidate.reported[endorser] {
+		return nil
+	}
+
+	// cheap check before verifying signatures
+	sigCount := len(candidate.EndorseSigs[endorser])
+	for _, c := range candidate.CommitMsgs {
+		if c.Committer == endorser {
+			sigCount++
+		}
+		if _, present := c.EndorsersSig[endorser]; present {
+			sigCount++
+		}
+	}
+	if sigCount <= gover.MAX_HONEST_SIGNATURES {
+		return nil
+	}
+
+	evidence := &gover.ReportEquivocationParam{
+		EvidenceType: gover.DOUBLE_ENDORSEMENT,
+		Headers:      make([]*types.Header, 0),
+		Sigs:         make([][]byte, 0),
+	}
+	signed := make(map[common.Uint256]bool)
+	addSig := func(proposer uint32, forEmpty bool, sig []byte) {
+		if proposer == endorser {
+			return
+		}
+		for _, p := range candidate.Proposals {
+			if p.Block.getProposer() != proposer {
+				continue
+			}
+			blk := p.Block.Block
+			if forEmpty {
+				blk = p.Block.EmptyBlock
+			}
+			if blk == nil {
+				continue
+			}
+			hash := blk.Hash()
+			if signed[hash] {
+				continue
+			}
+			if err := signature.Verify(pubkey, hash[:], sig); err == nil {
+				signed[hash] = true
+				evidence.Headers = append(evidence.Headers, blk.Header)
+				evidence.Sigs = append(evidence.Sigs, sig)
+			}
+		}
+	}
+
+	for _, eSig := range candidate.EndorseSigs[endorser] {
+		addSig(eSig.EndorsedProposer, eSig.ForEmpty, eSig.Signature)
+	}
+	for _, c := range candidate.CommitMsgs {
+		if c.Committer == endorser {
+			addSig(c.BlockProposer, c.CommitForEmpty, c.CommitterSig)
+		}
+		if sig, present := c.EndorsersSig[endorser]; present {
+			addSig(c.BlockProposer, c.CommitForEmpty, sig)
+		}
+	}
+	if len(evidence.Headers) <= gover.MAX_HONEST_SIGNATURES {
+		return nil
+	}
+	candidate.setReported(endorser)
+	return evidence
+}
+
+func (self *Server) checkProposalEquivocation(msg *blockProposalMsg) {
+	proposer := msg.Block.getProposer()
+	if proposer == self.Index {
+		return
+	}
+	if evidence := self.blockPool.getProposalEvidence(msg); evidence != nil {
+		self.reportEquivocation(proposer, evidence)
+	}
+}
+
+func (self *Server) checkEndorseEquivocation(blkNum uint32, endorser uint32) {
+	if endorser == self.Index {
+		return
+	}
+	pubkey := self.peerPool.GetPeerPubKey(endorser)
+	if pubkey == nil {
+		return
+	}
+	if evidence := self.blockPool.getEndorseEvidence(blkNum, endorser, pubkey); evidence != nil {
+		self.reportEquivocation(endorser, evidence)
+	}
+}
+
+//reportEquivocation submit evidence to governance contract, which will put the peer into black list
+func (self *Server) reportEquivocation(peerIdx uint32, evidence *gover.ReportEquivocationParam) {
+	pubkey := self.peerPool.GetPeerPubKey(peerIdx)
+	if pubkey == nil {
+		log.Errorf("server %d failed to report equivocation: unknown peer %d", self.Index, peerIdx)
+		return
+	}
+	evidence.PeerPubkey = vconfig.PubkeyID(pubkey)
+
+	tx, err := self.createEquivocationTransaction(evidence)
+	if err != nil {
+		log.Errorf("server %d failed to report equivocation of peer %d: %s", self.Index, peerIdx, err)
+		return
+	}
+	self.poolActor.AppendTx(tx)
+	txHash := tx.Hash()
+	log.Warnf("server %d reported equivocation of peer %d, evidence type %d, blk %d, tx %s",
+		self.Index, peerIdx, evidence.EvidenceType, evidence.Headers[0].Height, txHash.ToHexString())
+}
+
+//createEquivocationTransaction invoke governance native contract report_equivocation, paid by this node
+func (self *Server) createEquivocationTransaction(evidence *gover.ReportEquivocationParam) (*types.Transaction, error) {
+	buf := new(bytes.Buffer)
+	if err := evidence.Serialize(buf); err != nil {
+		return nil, fmt.Errorf("serialize evidence: %s", err)
+	}
+	mutable := utils.BuildNativeTransaction(nutils.GovernanceContractAddress, gover.REPORT_EQUIVOCATION, buf.Bytes())
+	mutable.Nonce = evidence.Headers[0].Height
+	mutable.GasPrice = config.DefConfig.Common.GasPrice
+	mutable.GasLimit = config.DefConfig.Common.GasLimit
+	mutable.Payer = self.account.Address
+
+	txHash := mutable.Hash()
+	sig, err := signature.Sign(self.account, txHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("sign tx: %s", err)
+	}
+	mutable.Sigs = []types.Sig{{
+		PubKeys: []keypair.PublicKey{self.account.PublicKey},
+		M:       1,
+		SigData: [][]byte{sig},
+	}}
+	return mutable.IntoImmutable()
+}
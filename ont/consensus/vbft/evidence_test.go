@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvidencePool() *BlockPool {
+	return &BlockPool{
+		candidateBlocks: make(map[uint32]*CandidateInfo),
+	}
+}
+
+func constructSignedBlock(acc *account.Account, proposer uint32, txRoot common.Uint256, timestamp uint32, nonce uint64) *types.Block {
+	payload, _ := json.Marshal(&vconfig.VbftBlockInfo{Proposer: proposer})
+	header := &types.Header{
+		TransactionsRoot: txRoot,
+		Timestamp:        timestamp,
+		Height:           uint32(20),
+		ConsensusData:    nonce,
+		ConsensusPayload: payload,
+	}
+	hash := header.Hash()
+	sig, _ := signature.Sign(acc, hash[:])
+	header.SigData = [][]byte{sig}
+	return &types.Block{Header: header}
+}
+
+func constructEvidenceProposal(acc *account.Account, proposer uint32, timestamp uint32) *blockProposalMsg {
+	return &blockProposalMsg{
+		Block: &Block{
+			Block:      constructSignedBlock(acc, proposer, common.Uint256{1}, timestamp, 1),
+			EmptyBlock: constructSignedBlock(acc, proposer, common.Uint256{}, timestamp, 2),
+			Info:       &vconfig.VbftBlockInfo{Proposer: proposer},
+		},
+	}
+}
+
+func TestProposalEvidence(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	pool := newEvidencePool()
+
+	p1 := constructEvidenceProposal(acc, 1, 1000)
+	assert.Nil(t, pool.newBlockProposal(p1))
+
+	// block and empty block of the same proposal is not evidence
+	pair := &blockProposalMsg{
+		Block: &Block{
+			Block: p1.Block.EmptyBlock,
+			Info:  p1.Block.Info,
+		},
+	}
+	assert.Equal(t, errDupProposal, pool.newBlockProposal(pair))
+	assert.Nil(t, pool.getProposalEvidence(pair))
+
+	// two blocks with user transactions of the same metadata are evidence
+	other := &blockProposalMsg{
+		Block: &Block{
+			Block: constructSignedBlock(acc, 1, common.Uint256{2}, 1000, 3),
+			Info:  p1.Block.Info,
+		},
+	}
+	assert.NotNil(t, pool.getProposalEvidence(other))
+	pool.candidateBlocks[20].reported = nil
+
+	p2 := constructEvidenceProposal(acc, 1, 1001)
+	assert.Equal(t, errDupProposal, pool.newBlockProposal(p2))
+	evidence := pool.getProposalEvidence(p2)
+	assert.NotNil(t, evidence)
+	assert.Equal(t, gover.DOUBLE_PROPOSAL, evidence.EvidenceType)
+	assert.Equal(t, 2, len(evidence.Headers))
+	for i, header := range evidence.Headers {
+		hash := header.Hash()
+		assert.Nil(t, signature.Verify(acc.PublicKey, hash[:], evidence.Sigs[i]))
+	}
+
+	// only reported once
+	assert.Nil(t, pool.getProposalEvidence(p2))
+}
+
+func TestEndorseEvidence(t *testing.T) {
+	endorser := account.NewAccount("SHA256withECDSA")
+	pool := newEvidencePool()
+
+	for proposer := uint32(1); proposer <= gover.MAX_HONEST_SIGNATURES+1; proposer++ {
+		p := constructEvidenceProposal(account.NewAccount("SHA256withECDSA"), proposer, 1000)
+		assert.Nil(t, pool.newBlockProposal(p))
+
+		hash := p.Block.Block.Hash()
+		sig, _ := signature.Sign(endorser, hash[:])
+		assert.Nil(t, pool.newBlockEndorsement(&blockEndorseMsg{
+			Endorser:          5,
+			EndorsedProposer:  proposer,
+			BlockNum:          20,
+			EndorsedBlockHash: hash,
+			EndorserSig:       sig,
+		}))
+
+		evidence := pool.getEndorseEvidence(20, 5, endorser.PublicKey)
+		if proposer <= gover.MAX_HONEST_SIGNATURES {
+			assert.Nil(t, evidence)
+			continue
+		}
+		assert.NotNil(t, evidence)
+		assert.Equal(t, gover.DOUBLE_ENDORSEMENT, evidence.EvidenceType)
+		assert.Equal(t, gover.MAX_HONEST_SIGNATURES+1, len(evidence.Headers))
+
+		buf := new(bytes.Buffer)
+		evidence.PeerPubkey = vconfig.PubkeyID(endorser.PublicKey)
+		assert.Nil(t, evidence.Serialize(buf))
+		param := new(gover.ReportEquivocationParam)
+		assert.Nil(t, param.Deserialize(buf))
+		assert.Equal(t, evidence.PeerPubkey, param.PeerPubkey)
+		assert.Equal(t, evidence.Sigs, param.Sigs)
+		for i, header := range param.Headers {
+			assert.Equal(t, evidence.Headers[i].Hash(), header.Hash())
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	MAX_METRICS_BLOCK_NUM = 256 // max count of block rounds kept in metrics
+)
+
+var timerEventNames = map[TimerEventType]string{
+	EventProposeBlockTimeout:      "ProposeBlockTimeout",
+	EventProposalBackoff:          "ProposalBackoff",
+	EventRandomBackoff:            "RandomBackoff",
+	EventPropose2ndBlockTimeout:   "Propose2ndBlockTimeout",
+	EventEndorseBlockTimeout:      "EndorseBlockTimeout",
+	EventEndorseEmptyBlockTimeout: "EndorseEmptyBlockTimeout",
+	EventCommitBlockTimeout:       "CommitBlockTimeout",
+}
+
+// BlockTimeline records the consensus progress of one block round.
+// All latencies are milliseconds since the round started.
+type BlockTimeline struct {
+	BlockNum       uint32
+	StartTime      int64            // unix time in milliseconds the round started
+	Proposer       uint32           // proposer of the sealed block
+	Proposals      map[uint32]int64 // proposer => latency of its proposal
+	Endorsements   map[uint32]int64 // endorser => latency of its endorsement
+	Commits        map[uint32]int64 // committer => latency of its commitment
+	SealLatency    int64            // latency of block sealed, 0 if not sealed yet
+	Timeouts       []string         // timeouts fired in the round, each one moves consensus to the next stage
+	EmptyEndorsed  bool             // endorsed on empty block as fallback
+	EmptyProposal  int64            // latency of finding the proposal endorsed for empty block, 0 if not found
+	EmptyBlock     bool             // sealed block is the empty block
+	CatchConsensus uint32           // times of catching up consensus from msg pool
+}
+
+// PeerMetrics is the consensus msg statistics of one peer
+type PeerMetrics struct {
+	Index          uint32
+	Connected      bool
+	CommittedBlock uint32            // committed block number in latest heartbeat
+	LastUpdateTime int64             // unix time in milliseconds of latest heartbeat
+	MsgCount       map[string]uint64 // msg type => received count
+}
+
+// ConsensusMetrics is the consensus timeline of recent blocks and the peers statistics
+type ConsensusMetrics struct {
+	Index  uint32
+	Blocks []*BlockTimeline
+	Peers  []*PeerMetrics
+}
+
+type roundMetrics struct {
+	lock      sync.Mutex
+	timelines map[uint32]*BlockTimeline
+	starts    map[uint32]time.Time
+}
+
+func newRoundMetrics() *roundMetrics {
+	return &roundMetrics{
+		timelines: make(map[uint32]*BlockTimeline),
+		starts:    make(map[uint32]time.Time),
+	}
+}
+
+// getTimeline should be called with lock held
+func (m *roundMetrics) getTimeline(blkNum uint32) (*BlockTimeline, time.Time) {
+	tl, present := m.timelines[blkNum]
+	if !present {
+		now := time.Now()
+		tl = &BlockTimeline{
+			BlockNum:     blkNum,
+			StartTime:    now.UnixNano() / int64(time.Millisecond),
+			Proposals:    make(map[uint32]int64),
+			Endorsements: make(map[uint32]int64),
+			Commits:      make(map[uint32]int64),
+		}
+		m.timelines[blkNum] = tl
+		m.starts[blkNum] = now
+		if blkNum > MAX_METRICS_BLOCK_NUM {
+			for n := range m.timelines {
+				if n+MAX_METRICS_BLOCK_NUM < blkNum {
+					delete(m.timelines, n)
+					delete(m.starts, n)
+				}
+			}
+		}
+	}
+	return tl, m.starts[blkNum]
+}
+
+func latency(start time.Time) int64 {
+	return int64(time.Since(start) / time.Millisecond)
+}
+
+func (m *roundMetrics) onRoundStart(blkNum uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.getTimeline(blkNum)
+}
+
+func (m *roundMetrics) onProposal(blkNum, proposer uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, start := m.getTimeline(blkNum)
+	if _, present := tl.Proposals[proposer]; !present {
+		tl.Proposals[proposer] = latency(start)
+	}
+}
+
+func (m *roundMetrics) onEndorse(blkNum, endorser uint32, forEmpty bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, start := m.getTimeline(blkNum)
+	if _, present := tl.Endorsements[endorser]; !present {
+		tl.Endorsements[endorser] = latency(start)
+	}
+	if forEmpty {
+		tl.EmptyEndorsed = true
+	}
+}
+
+func (m *roundMetrics) onEmptyProposal(blkNum, proposer uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, start := m.getTimeline(blkNum)
+	if _, present := tl.Proposals[proposer]; !present {
+		tl.Proposals[proposer] = latency(start)
+	}
+	if tl.EmptyProposal == 0 {
+		tl.EmptyProposal = latency(start)
+	}
+	tl.EmptyEndorsed = true
+}
+
+func (m *roundMetrics) onCommit(blkNum, committer uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, start := m.getTimeline(blkNum)
+	if _, present := tl.Commits[committer]; !present {
+		tl.Commits[committer] = latency(start)
+	}
+}
+
+func (m *roundMetrics) onSealed(blkNum, proposer uint32, empty bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, start := m.getTimeline(blkNum)
+	tl.Proposer = proposer
+	tl.EmptyBlock = empty
+	tl.SealLatency = latency(start)
+}
+
+func (m *roundMetrics) onTimeout(blkNum uint32, evtType TimerEventType) {
+	name, present := timerEventNames[evtType]
+	if !present {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, _ := m.getTimeline(blkNum)
+	tl.Timeouts = append(tl.Timeouts, name)
+}
+
+func (m *roundMetrics) onCatchConsensus(blkNum uint32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tl, _ := m.getTimeline(blkNum)
+	tl.CatchConsensus++
+}
+
+// getTimelines returns copies of the latest count block timelines, ordered by block num
+func (m *roundMetrics) getTimelines(count int) []*BlockTimeline {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	blkNums := make([]uint32, 0, len(m.timelines))
+	for n := range m.timelines {
+		blkNums = append(blkNums, n)
+	}
+	sort.Slice(blkNums, func(i, j int) bool { return blkNums[i] < blkNums[j] })
+	if count > 0 && len(blkNums) > count {
+		blkNums = blkNums[len(blkNums)-count:]
+	}
+
+	timelines := make([]*BlockTimeline, 0, len(blkNums))
+	for _, n := range blkNums {
+		tl := *m.timelines[n]
+		tl.Proposals = copyLatencies(tl.Proposals)
+		tl.Endorsements = copyLatencies(tl.Endorsements)
+		tl.Commits = copyLatencies(tl.Commits)
+		tl.Timeouts = append([]string{}, tl.Timeouts...)
+		timelines = append(timelines, &tl)
+	}
+	return timelines
+}
+
+func copyLatencies(m map[uint32]int64) map[uint32]int64 {
+	c := make(map[uint32]int64, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+var msgTypeNames = map[MsgType]string{
+	BlockProposalMessage:      "proposal",
+	BlockEndorseMessage:       "endorse",
+	BlockCommitMessage:        "commit",
+	PeerHandshakeMessage:      "handshake",
+	PeerHeartbeatMessage:      "heartbeat",
+	BlockInfoFetchMessage:     "blockinfofetch",
+	BlockInfoFetchRespMessage: "blockinfofetchresp",
+	ProposalFetchMessage:      "proposalfetch",
+	BlockFetchMessage:         "blockfetch",
+	BlockFetchRespMessage:     "blockfetchresp",
+}
+
+func (self *Server) getConsensusMetrics(count int) *ConsensusMetrics {
+	return &ConsensusMetrics{
+		Index:  self.Index,
+		Blocks: self.metrics.getTimelines(count),
+		Peers:  self.peerPool.getPeerMetrics(),
+	}
+}
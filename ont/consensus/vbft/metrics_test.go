@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundMetrics(t *testing.T) {
+	m := newRoundMetrics()
+	m.onRoundStart(10)
+	m.onProposal(10, 1)
+	m.onProposal(10, 1)
+	m.onEndorse(10, 2, false)
+	m.onEndorse(10, 3, true)
+	m.onCommit(10, 2)
+	m.onTimeout(10, EventProposeBlockTimeout)
+	m.onTimeout(10, EventTxPool)
+	m.onSealed(10, 1, true)
+	m.onRoundStart(11)
+	m.onEmptyProposal(11, 2)
+
+	timelines := m.getTimelines(0)
+	assert.Equal(t, 2, len(timelines))
+	tl := timelines[0]
+	assert.Equal(t, uint32(10), tl.BlockNum)
+	assert.Equal(t, uint32(1), tl.Proposer)
+	assert.Equal(t, 1, len(tl.Proposals))
+	assert.Equal(t, 2, len(tl.Endorsements))
+	assert.Equal(t, 1, len(tl.Commits))
+	assert.Equal(t, []string{"ProposeBlockTimeout"}, tl.Timeouts)
+	assert.True(t, tl.EmptyEndorsed)
+	assert.True(t, tl.EmptyBlock)
+
+	timelines = m.getTimelines(1)
+	assert.Equal(t, 1, len(timelines))
+	assert.Equal(t, uint32(11), timelines[0].BlockNum)
+	assert.True(t, timelines[0].EmptyEndorsed)
+	assert.Equal(t, 1, len(timelines[0].Proposals))
+
+	m.onRoundStart(11 + MAX_METRICS_BLOCK_NUM)
+	timelines = m.getTimelines(0)
+	assert.Equal(t, uint32(11), timelines[0].BlockNum)
+}
+
+func TestPeerMetrics(t *testing.T) {
+	pool := NewPeerPool(3, nil)
+	pool.peers[1] = &Peer{Index: 1, connected: true}
+	pool.peerMsgReceived(1, BlockProposalMessage)
+	pool.peerMsgReceived(1, BlockProposalMessage)
+	pool.peerMsgReceived(1, PeerHeartbeatMessage)
+
+	metrics := pool.getPeerMetrics()
+	assert.Equal(t, 1, len(metrics))
+	assert.True(t, metrics[0].Connected)
+	assert.Equal(t, uint64(2), metrics[0].MsgCount["proposal"])
+	assert.Equal(t, uint64(1), metrics[0].MsgCount["heartbeat"])
+}
@@ -20,6 +20,7 @@ package vbft
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -47,17 +48,19 @@ type PeerPool struct {
 
 	peers                  map[uint32]*Peer
 	peerConnectionWaitings map[uint32]chan struct{}
+	msgCount               map[uint32]map[MsgType]uint64 // peer index to received msg count
 }
 
 func NewPeerPool(maxSize int, server *Server) *PeerPool {
 	return &PeerPool{
-		maxSize: maxSize,
-		server:  server,
-		configs: make(map[uint32]*vconfig.PeerConfig),
-		IDMap:   make(map[string]uint32),
-		P2pMap:  make(map[uint32]uint64),
-		peers:   make(map[uint32]*Peer),
+		maxSize:                maxSize,
+		server:                 server,
+		configs:                make(map[uint32]*vconfig.PeerConfig),
+		IDMap:                  make(map[string]uint32),
+		P2pMap:                 make(map[uint32]uint64),
+		peers:                  make(map[uint32]*Peer),
 		peerConnectionWaitings: make(map[uint32]chan struct{}),
+		msgCount:               make(map[uint32]map[MsgType]uint64),
 	}
 }
 
@@ -69,6 +72,7 @@ func (pool *PeerPool) clean() {
 	pool.IDMap = make(map[string]uint32)
 	pool.P2pMap = make(map[uint32]uint64)
 	pool.peers = make(map[uint32]*Peer)
+	pool.msgCount = make(map[uint32]map[MsgType]uint64)
 }
 
 // FIXME: should rename to isPeerConnected
@@ -291,3 +295,39 @@ func (pool *PeerPool) RemovePeerIndex(nodeId string) {
 
 	delete(pool.IDMap, nodeId)
 }
+
+func (pool *PeerPool) peerMsgReceived(peerIdx uint32, msgType MsgType) {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	counts, present := pool.msgCount[peerIdx]
+	if !present {
+		counts = make(map[MsgType]uint64)
+		pool.msgCount[peerIdx] = counts
+	}
+	counts[msgType]++
+}
+
+func (pool *PeerPool) getPeerMetrics() []*PeerMetrics {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	metrics := make([]*PeerMetrics, 0, len(pool.peers))
+	for idx, p := range pool.peers {
+		m := &PeerMetrics{
+			Index:          idx,
+			Connected:      p.connected,
+			LastUpdateTime: p.LastUpdateTime.UnixNano() / int64(time.Millisecond),
+			MsgCount:       make(map[string]uint64),
+		}
+		if p.LatestInfo != nil {
+			m.CommittedBlock = p.LatestInfo.CommittedBlockNumber
+		}
+		for msgType, cnt := range pool.msgCount[idx] {
+			m.MsgCount[msgTypeNames[msgType]] = cnt
+		}
+		metrics = append(metrics, m)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Index < metrics[j].Index })
+	return metrics
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	actorTypes "github.com/ontio/ontology/consensus/actor"
 	"github.com/ontio/ontology/consensus/vbft/config"
@@ -43,6 +44,7 @@ import (
 	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
 	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
 	ninit "github.com/ontio/ontology/smartcontract/service/native/init"
+	"github.com/ontio/ontology/smartcontract/service/native/scheduler"
 	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
 	"github.com/ontio/ontology/validator/increment"
 )
@@ -118,6 +120,7 @@ type Server struct {
 	syncer     *Syncer
 	stateMgr   *StateMgr
 	timer      *EventTimer
+	metrics    *roundMetrics // consensus timeline of recent rounds
 
 	msgRecvC   map[uint32]chan *p2pMsgPayload
 	msgC       chan ConsensusMsg
@@ -137,6 +140,7 @@ func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, e
 		p2p:                &actorTypes.P2PActor{P2P: p2p},
 		ledger:             ledger.DefLedger,
 		incrValidator:      increment.NewIncrementValidator(10),
+		metrics:            newRoundMetrics(),
 	}
 	server.stateMgr = newStateMgr(server)
 
@@ -179,6 +183,13 @@ func (self *Server) Receive(context actor.Context) {
 		self.handleBlockPersistCompleted(msg.Block)
 	case *p2pmsg.ConsensusPayload:
 		self.NewConsensusPayload(msg)
+	case *actorTypes.GetConsensusMetricsReq:
+		if context.Sender() != nil {
+			resp := &actorTypes.GetConsensusMetricsRsp{
+				Metrics: self.getConsensusMetrics(int(msg.Count)),
+			}
+			context.Sender().Request(resp, context.Self())
+		}
 
 	default:
 		log.Info("vbft actor: Unknown msg ", msg, "type", reflect.TypeOf(msg))
@@ -585,6 +596,7 @@ func (self *Server) run(peerPubKey keypair.PublicKey) error {
 			if err != nil {
 				log.Errorf("server %d failed to deserialize vbft msg (len %d): %s", self.Index, len(msgData), err)
 			} else {
+				self.peerPool.peerMsgReceived(fromPeer, msg.Type())
 				pk := self.peerPool.GetPeerPubKey(fromPeer)
 				if pk == nil {
 					log.Errorf("server %d failed to get peer %d pubkey", self.Index, fromPeer)
@@ -648,6 +660,7 @@ func (self *Server) updateParticipantConfig() error {
 
 func (self *Server) startNewRound() error {
 	blkNum := self.GetCurrentBlockNo()
+	self.metrics.onRoundStart(blkNum)
 
 	if err := self.updateParticipantConfig(); err != nil {
 		log.Errorf("startNewRound error:%s", err)
@@ -1098,7 +1111,16 @@ func (self *Server) processProposalMsg(msg *blockProposalMsg) {
 	}
 
 	txs := msg.Block.Block.Transactions
-	if len(txs) > 0 && self.nonSystxs(txs, msgBlkNum) {
+	sysCount, err := self.sysTxCount(txs, msgBlkNum)
+	if err != nil {
+		log.Errorf("server %d failed to verify system txs of block %d proposal from %d: %s",
+			self.Index, msgBlkNum, msg.Block.getProposer(), err)
+		self.msgPool.DropMsg(msg)
+		return
+	}
+	// system transactions are not signed, only user transactions are verified by txnpool
+	txs = txs[sysCount:]
+	if len(txs) > 0 {
 		height := uint32(msgBlkNum) - 1
 		start, end := self.incrValidator.BlockRange()
 
@@ -1161,11 +1183,12 @@ func (self *Server) processMsgEvent() error {
 				// add proposal to block-pool
 				if err := self.blockPool.newBlockProposal(pMsg); err != nil {
 					if err == errDupProposal {
-						// TODO: faulty proposer detected
+						self.checkProposalEquivocation(pMsg)
 					}
 					log.Errorf("failed to add block proposal (%d): %s", msgBlkNum, err)
 					return nil
 				}
+				self.metrics.onProposal(msgBlkNum, pMsg.Block.getProposer())
 
 				if self.isProposer(msgBlkNum, pMsg.Block.getProposer()) {
 					// check if agreed on prev-blockhash
@@ -1223,6 +1246,8 @@ func (self *Server) processMsgEvent() error {
 					log.Errorf("failed to add endorsement (%d): %s", msgBlkNum, err)
 					return nil
 				}
+				self.metrics.onEndorse(msgBlkNum, pMsg.Endorser, pMsg.EndorseForEmpty)
+				self.checkEndorseEquivocation(msgBlkNum, pMsg.Endorser)
 				log.Infof("server %d received endorse from %d, for proposer %d, block %d, empty: %t",
 					self.Index, pMsg.Endorser, pMsg.EndorsedProposer, msgBlkNum, pMsg.EndorseForEmpty)
 
@@ -1287,6 +1312,8 @@ func (self *Server) processMsgEvent() error {
 					log.Errorf("failed to add commit msg (%d): %s", msgBlkNum, err)
 					return nil
 				}
+				self.metrics.onCommit(msgBlkNum, pMsg.Committer)
+				self.checkEndorseEquivocation(msgBlkNum, pMsg.Committer)
 
 				log.Infof("server %d received commit from %d, for proposer %d, block %d, empty: %t",
 					self.Index, pMsg.Committer, pMsg.BlockProposer, msgBlkNum, pMsg.CommitForEmpty)
@@ -1588,6 +1615,9 @@ func (self *Server) timerLoop() {
 }
 
 func (self *Server) processTimerEvent(evt *TimerEvent) error {
+	if evt.blockNum == self.GetCurrentBlockNo() {
+		self.metrics.onTimeout(evt.blockNum, evt.evtType)
+	}
 	switch evt.evtType {
 	case EventProposalBackoff:
 		// 1. if endorsed, return
@@ -1996,6 +2026,7 @@ func (self *Server) sealBlock(block *Block, empty bool) error {
 	if err := self.blockPool.setBlockSealed(block, empty); err != nil {
 		return fmt.Errorf("failed to seal proposal: %s", err)
 	}
+	self.metrics.onSealed(sealedBlkNum, block.getProposer(), empty)
 
 	// TODO: also persistent the block endorsers and committer msgs
 
@@ -2064,6 +2095,14 @@ func (self *Server) creategovernaceTransaction(blkNum uint32) (*types.Transactio
 	return tx, err
 }
 
+//createSchedulerTransaction invoke scheduler native contract executeScheduled
+func createSchedulerTransaction(blkNum uint32) (*types.Transaction, error) {
+	mutable := utils.BuildNativeTransaction(nutils.SchedulerContractAddress, scheduler.EXECUTE_SCHEDULED, []byte{})
+	mutable.Nonce = blkNum
+	tx, err := mutable.IntoImmutable()
+	return tx, err
+}
+
 //checkNeedUpdateChainConfig use blockcount
 func (self *Server) checkNeedUpdateChainConfig(blockNum uint32) bool {
 	prevBlk, _ := self.blockPool.getSealedBlock(blockNum - 1)
@@ -2101,18 +2140,46 @@ func (self *Server) validHeight(blkNum uint32) uint32 {
 	return validHeight
 }
 
-func (self *Server) nonSystxs(sysTxs []*types.Transaction, blkNum uint32) bool {
-	if self.checkNeedUpdateChainConfig(blkNum) && len(sysTxs) == 1 {
-		invoke := sysTxs[0].Payload.(*payload.InvokeCode)
-		if invoke == nil {
-			log.Errorf("nonSystxs invoke is nil,blocknum:%d", blkNum)
-			return true
+//sysTxCount return the number of system transactions at the beginning of txs
+func (self *Server) sysTxCount(txs []*types.Transaction, blkNum uint32) (int, error) {
+	due, err := hasScheduledCallbacks(self.chainStore, blkNum)
+	if err != nil {
+		return 0, err
+	}
+	return countSysTxs(txs, blkNum, self.checkNeedUpdateChainConfig(blkNum), due)
+}
+
+//countSysTxs return the number of system transactions at the beginning of txs. The commit dpos
+//transaction may be included when chain config needs update, the scheduler transaction must be
+//included if and only if there are callbacks due at the block. Before the native upgrade height, the
+//commit dpos transaction is only counted when it is the only transaction of block
+func countSysTxs(txs []*types.Transaction, blkNum uint32, needUpdateConfig, due bool) (int, error) {
+	if blkNum < config.GetNativeUpgradeHeight() {
+		if needUpdateConfig && len(txs) == 1 && isInvokeCode(txs[0], ninit.COMMIT_DPOS_BYTES) {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	count := 0
+	if needUpdateConfig && len(txs) > 0 && isInvokeCode(txs[0], ninit.COMMIT_DPOS_BYTES) {
+		count++
+	}
+	if due {
+		tx, err := createSchedulerTransaction(blkNum)
+		if err != nil {
+			return 0, err
 		}
-		if bytes.Compare(invoke.Code, ninit.COMMIT_DPOS_BYTES) == 0 {
-			return false
+		if count == len(txs) || txs[count].Hash() != tx.Hash() || len(txs[count].Sigs) != 0 {
+			return 0, fmt.Errorf("scheduler transaction missing")
 		}
+		count++
 	}
-	return true
+	return count, nil
+}
+
+func isInvokeCode(tx *types.Transaction, code []byte) bool {
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	return ok && bytes.Compare(invoke.Code, code) == 0
 }
 
 func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
@@ -2145,6 +2212,18 @@ func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
 		forEmpty = true
 		cfg = chainconfig
 	}
+	//add transaction invoke scheduler native executeScheduled contract
+	due, err := hasScheduledCallbacks(self.chainStore, blkNum)
+	if err != nil {
+		return fmt.Errorf("check scheduled callbacks failed: %s", err)
+	}
+	if due {
+		tx, err := createSchedulerTransaction(blkNum)
+		if err != nil {
+			return fmt.Errorf("construct scheduler transaction error: %v", err)
+		}
+		sysTxs = append(sysTxs, tx)
+	}
 	if self.nonConsensusNode() {
 		return fmt.Errorf("%d quit consensus node", self.Index)
 	}
@@ -2314,6 +2393,7 @@ func (self *Server) catchConsensus(blkNum uint32) error {
 	if !self.isEndorser(blkNum, self.Index) && !self.isCommitter(blkNum, self.Index) {
 		return nil
 	}
+	self.metrics.onCatchConsensus(blkNum)
 
 	proposals := make(map[uint32]*blockProposalMsg)
 	pMsgs := self.msgPool.GetProposalMsgs(blkNum)
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+func TestCountSysTxs(t *testing.T) {
+	blkNum := config.GetNativeUpgradeHeight() + 10
+	mutable := utils.BuildNativeTransaction(nutils.GovernanceContractAddress, gover.COMMIT_DPOS, []byte{})
+	mutable.Nonce = blkNum
+	commitDpos, err := mutable.IntoImmutable()
+	if err != nil {
+		t.Fatalf("build commit dpos tx failed: %v", err)
+	}
+	scheduled, err := createSchedulerTransaction(blkNum)
+	if err != nil {
+		t.Fatalf("build scheduler tx failed: %v", err)
+	}
+	//scheduler transaction of other block
+	otherScheduled, err := createSchedulerTransaction(blkNum - 1)
+	if err != nil {
+		t.Fatalf("build scheduler tx failed: %v", err)
+	}
+	userTx, err := utils.NewInvokeTransaction([]byte{0x51}).IntoImmutable()
+	if err != nil {
+		t.Fatalf("build user tx failed: %v", err)
+	}
+
+	cases := []struct {
+		txs              []*types.Transaction
+		needUpdateConfig bool
+		due              bool
+		count            int
+		fail             bool
+	}{
+		{txs: []*types.Transaction{userTx}, count: 0},
+		{txs: []*types.Transaction{commitDpos, userTx}, needUpdateConfig: true, count: 1},
+		{txs: []*types.Transaction{commitDpos, userTx}, count: 0},
+		{txs: []*types.Transaction{scheduled, userTx}, due: true, count: 1},
+		{txs: []*types.Transaction{commitDpos, scheduled}, needUpdateConfig: true, due: true, count: 2},
+		{txs: []*types.Transaction{scheduled, commitDpos}, needUpdateConfig: true, due: true, fail: true},
+		{txs: []*types.Transaction{userTx, scheduled}, due: true, fail: true},
+		{txs: []*types.Transaction{otherScheduled}, due: true, fail: true},
+		{txs: []*types.Transaction{}, due: true, fail: true},
+	}
+	for i, c := range cases {
+		count, err := countSysTxs(c.txs, blkNum, c.needUpdateConfig, c.due)
+		if c.fail {
+			if err == nil {
+				t.Errorf("case %d: countSysTxs should fail", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: countSysTxs failed: %v", i, err)
+		} else if count != c.count {
+			t.Errorf("case %d: countSysTxs got %d, expect %d", i, count, c.count)
+		}
+	}
+
+	//before the native upgrade, commit dpos is only counted alone and there is no scheduler transaction
+	if blkNum := config.GetNativeUpgradeHeight(); blkNum > 0 {
+		blkNum--
+		if count, err := countSysTxs([]*types.Transaction{commitDpos}, blkNum, true, false); err != nil || count != 1 {
+			t.Errorf("countSysTxs before upgrade got %d, %v, expect 1", count, err)
+		}
+		if count, err := countSysTxs([]*types.Transaction{commitDpos, userTx}, blkNum, true, false); err != nil || count != 0 {
+			t.Errorf("countSysTxs before upgrade got %d, %v, expect 0", count, err)
+		}
+		if count, err := countSysTxs([]*types.Transaction{userTx}, blkNum, false, true); err != nil || count != 0 {
+			t.Errorf("countSysTxs before upgrade got %d, %v, expect 0", count, err)
+		}
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/ontio/ontology/core/states"
 	scommon "github.com/ontio/ontology/core/store/common"
 	gov "github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/ontio/ontology/smartcontract/service/native/scheduler"
 	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
 )
 
@@ -91,16 +92,8 @@ func getParticipantSelectionSeed(block *Block) vconfig.VRFValue {
 	return vconfig.VRFValue(f)
 }
 
-type vrfData struct {
-	BlockNum uint32 `json:"block_num"`
-	PrevVrf  []byte `json:"prev_vrf"`
-}
-
 func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
-	data, err := json.Marshal(&vrfData{
-		BlockNum: blkNum,
-		PrevVrf:  prevVrf,
-	})
+	data, err := vconfig.VrfData(blkNum, prevVrf)
 	if err != nil {
 		return nil, nil, fmt.Errorf("computeVrf failed to marshal vrfData: %s", err)
 	}
@@ -109,22 +102,7 @@ func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, [
 }
 
 func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byte) error {
-	data, err := json.Marshal(&vrfData{
-		BlockNum: blkNum,
-		PrevVrf:  prevVrf,
-	})
-	if err != nil {
-		return fmt.Errorf("verifyVrf failed to marshal vrfData: %s", err)
-	}
-
-	result, err := vrf.Verify(pk, data, newVrf, proof)
-	if err != nil {
-		return fmt.Errorf("verifyVrf failed: %s", err)
-	}
-	if !result {
-		return fmt.Errorf("verifyVrf failed")
-	}
-	return nil
+	return vconfig.VerifyVrf(pk, blkNum, prevVrf, newVrf, proof)
 }
 func GetVbftConfigInfo() (*config.VBFTConfig, error) {
 	//get governance view
@@ -256,6 +234,19 @@ func GetGovernanceView() (*gov.GovernanceView, error) {
 	return governanceView, nil
 }
 
+//hasScheduledCallbacks check whether there are callbacks of scheduler contract due at block blkNum,
+//they are registered before the block, so it is decided by the state of parent block
+func hasScheduledCallbacks(store *ChainStore, blkNum uint32) (bool, error) {
+	if blkNum < config.GetNativeUpgradeHeight() {
+		return false, nil
+	}
+	data, err := store.GetStorageItem(blkNum-1, nutils.SchedulerContractAddress, scheduler.GenHeightKey(blkNum))
+	if err != nil && err != scommon.ErrNotFound {
+		return false, err
+	}
+	return data != nil, nil
+}
+
 func getChainConfig(blkNum uint32) (*vconfig.ChainConfig, error) {
 	config, err := GetVbftConfigInfo()
 	if err != nil {
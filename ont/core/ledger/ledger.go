@@ -29,6 +29,7 @@ import (
 	"github.com/ontio/ontology/core/store/ledgerstore"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
 	cstate "github.com/ontio/ontology/smartcontract/states"
 )
 
@@ -156,6 +157,10 @@ func (self *Ledger) GetContractState(contractHash common.Address) (*payload.Depl
 	return self.ldgStore.GetContractState(contractHash)
 }
 
+func (self *Ledger) GetContractHistory(contractHash common.Address) (*cstate.ContractHistory, error) {
+	return self.ldgStore.GetContractHistory(contractHash)
+}
+
 func (self *Ledger) GetMerkleProof(proofHeight, rootHeight uint32) ([]common.Uint256, error) {
 	return self.ldgStore.GetMerkleProof(proofHeight, rootHeight)
 }
@@ -164,6 +169,14 @@ func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecRe
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) PreExecuteContractWithDebugger(tx *types.Transaction, debugger neovm.Debugger) (*cstate.PreExecResult, error) {
+	ldgStore, ok := self.ldgStore.(*ledgerstore.LedgerStoreImp)
+	if !ok {
+		return nil, fmt.Errorf("ledger store does not support debugging")
+	}
+	return ldgStore.PreExecuteContractWithDebugger(tx, debugger)
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
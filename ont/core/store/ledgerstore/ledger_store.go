@@ -169,38 +169,17 @@ func (this *LedgerStoreImp) InitLedgerStoreWithGenesisBlock(genesisBlock *types.
 	//load vbft peerInfo
 	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
 	if consensusType == "vbft" {
-		blk, err := this.GetBlockByHeight(this.currBlockHeight)
+		headerPeerInfo, err := this.loadVbftPeerInfo(this.GetCurrentHeaderHeight())
 		if err != nil {
 			return err
 		}
-		blkInfo, err := vconfig.VbftBlock(blk.Header)
+		blockPeerInfo, err := this.loadVbftPeerInfo(this.GetCurrentBlockHeight())
 		if err != nil {
 			return err
 		}
-		var cfg *vconfig.ChainConfig
-		if blkInfo.NewChainConfig != nil {
-			cfg = blkInfo.NewChainConfig
-		} else {
-			cfgBlock, err := this.GetBlockByHeight(blkInfo.LastConfigBlockNum)
-			if err != nil {
-				return err
-			}
-			Info, err := vconfig.VbftBlock(cfgBlock.Header)
-			if err != nil {
-				return err
-			}
-			if Info.NewChainConfig == nil {
-				return fmt.Errorf("getNewChainConfig error block num:%d", blkInfo.LastConfigBlockNum)
-			}
-			cfg = Info.NewChainConfig
-		}
 		this.lock.Lock()
-		this.vbftPeerInfoheader = make(map[string]uint32)
-		this.vbftPeerInfoblock = make(map[string]uint32)
-		for _, p := range cfg.Peers {
-			this.vbftPeerInfoheader[p.ID] = p.Index
-			this.vbftPeerInfoblock[p.ID] = p.Index
-		}
+		this.vbftPeerInfoheader = headerPeerInfo
+		this.vbftPeerInfoblock = blockPeerInfo
 		this.lock.Unlock()
 	}
 	// check and fix imcompatible states
@@ -208,6 +187,40 @@ func (this *LedgerStoreImp) InitLedgerStoreWithGenesisBlock(genesisBlock *types.
 	return err
 }
 
+//loadVbftPeerInfo return the vbft peers of the chain config in effect at height
+func (this *LedgerStoreImp) loadVbftPeerInfo(height uint32) (map[string]uint32, error) {
+	header, err := this.GetHeaderByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	blkInfo, err := vconfig.VbftBlock(header)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *vconfig.ChainConfig
+	if blkInfo.NewChainConfig != nil {
+		cfg = blkInfo.NewChainConfig
+	} else {
+		cfgHeader, err := this.GetHeaderByHeight(blkInfo.LastConfigBlockNum)
+		if err != nil {
+			return nil, err
+		}
+		Info, err := vconfig.VbftBlock(cfgHeader)
+		if err != nil {
+			return nil, err
+		}
+		if Info.NewChainConfig == nil {
+			return nil, fmt.Errorf("getNewChainConfig error block num:%d", blkInfo.LastConfigBlockNum)
+		}
+		cfg = Info.NewChainConfig
+	}
+	peerInfo := make(map[string]uint32)
+	for _, p := range cfg.Peers {
+		peerInfo[p.ID] = p.Index
+	}
+	return peerInfo, nil
+}
+
 func (this *LedgerStoreImp) hasAlreadyInitGenesisBlock() (bool, error) {
 	version, err := this.blockStore.GetVersion()
 	if err != nil && err != scom.ErrNotFound {
@@ -268,6 +281,19 @@ func (this *LedgerStoreImp) initHeaderIndexList() error {
 		}
 		this.headerIndex[height] = blockHash
 	}
+	if config.DefConfig.Common.EnableLightClient {
+		//light client only saves headers, which are beyond current block height
+		for height := currBlockHeight + 1; ; height++ {
+			blockHash, err := this.blockStore.GetBlockHash(height)
+			if err == scom.ErrNotFound {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("LoadBlockHash height %d error %s", height, err)
+			}
+			this.headerIndex[height] = blockHash
+		}
+	}
 	return nil
 }
 
@@ -425,9 +451,15 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header, vbftPeerInfo map[
 	if consensusType == "vbft" {
 		//check bookkeeppers
 		m := len(header.Bookkeepers) - (len(header.Bookkeepers)-1)/3
+		lightClient := config.DefConfig.Common.EnableLightClient
+		if lightClient {
+			//light client can not check the header by executing block, so 2/3 of the peers must sign it
+			m = len(vbftPeerInfo) - (len(vbftPeerInfo)-1)/3
+		}
 		if len(header.Bookkeepers) < m {
 			return vbftPeerInfo, fmt.Errorf("header Bookkeepers %d less than 2/3 len vbftPeerInfo%d", len(header.Bookkeepers), len(vbftPeerInfo))
 		}
+		signers := make(map[string]bool)
 		for _, bookkeeper := range header.Bookkeepers {
 			pubkey := vconfig.PubkeyID(bookkeeper)
 			_, present := vbftPeerInfo[pubkey]
@@ -435,6 +467,10 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header, vbftPeerInfo map[
 				log.Errorf("invalid pubkey :%v,height:%d", pubkey, header.Height)
 				return vbftPeerInfo, fmt.Errorf("invalid pubkey :%v", pubkey)
 			}
+			if lightClient && signers[pubkey] {
+				return vbftPeerInfo, fmt.Errorf("duplicate bookkeeper :%v", pubkey)
+			}
+			signers[pubkey] = true
 		}
 		hash := header.Hash()
 		err = signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData)
@@ -484,11 +520,31 @@ func (this *LedgerStoreImp) AddHeader(header *types.Header) error {
 	if err != nil {
 		return fmt.Errorf("verifyHeader error %s", err)
 	}
+	if config.DefConfig.Common.EnableLightClient {
+		err = this.saveHeader(header)
+		if err != nil {
+			return fmt.Errorf("saveHeader error %s", err)
+		}
+		this.setHeaderIndex(header.Height, header.Hash())
+		return nil
+	}
 	this.addHeaderCache(header)
 	this.setHeaderIndex(header.Height, header.Hash())
 	return nil
 }
 
+//saveHeader persist header without block body. Only used by light client, so current block height is kept unchanged
+func (this *LedgerStoreImp) saveHeader(header *types.Header) error {
+	blockHash := header.Hash()
+	this.blockStore.NewBatch()
+	this.blockStore.SaveBlockHash(header.Height, blockHash)
+	err := this.blockStore.SaveHeader(&types.Block{Header: header}, 0)
+	if err != nil {
+		return fmt.Errorf("SaveHeader height %d hash %s error %s", header.Height, blockHash.ToHexString(), err)
+	}
+	return this.blockStore.CommitTo()
+}
+
 //AddHeaders bath add header.
 func (this *LedgerStoreImp) AddHeaders(headers []*types.Header) error {
 	sort.Slice(headers, func(i, j int) bool {
@@ -813,6 +869,11 @@ func (this *LedgerStoreImp) GetMerkleProof(proofHeight, rootHeight uint32) ([]co
 	return this.stateStore.GetMerkleProof(proofHeight, rootHeight)
 }
 
+//GetContractHistory return version history of contract. Wrap function of StateStore.GetContractHistory
+func (this *LedgerStoreImp) GetContractHistory(contractHash common.Address) (*sstate.ContractHistory, error) {
+	return this.stateStore.GetContractHistory(contractHash)
+}
+
 //GetContractState return contract by contract address. Wrap function of StateStore.GetContractState
 func (this *LedgerStoreImp) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
 	return this.stateStore.GetContractState(contractHash)
@@ -835,6 +896,11 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]*event.Execu
 
 //PreExecuteContract return the result of smart contract execution without commit to store
 func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.PreExecResult, error) {
+	return this.PreExecuteContractWithDebugger(tx, nil)
+}
+
+//PreExecuteContractWithDebugger pre-execute the transaction with the neovm execution observed by debugger
+func (this *LedgerStoreImp) PreExecuteContractWithDebugger(tx *types.Transaction, debugger neovm.Debugger) (*sstate.PreExecResult, error) {
 	height := this.GetCurrentBlockHeight()
 	stf := &sstate.PreExecResult{State: event.CONTRACT_STATE_FAIL, Gas: neovm.MIN_TRANSACTION_GAS, Result: nil}
 
@@ -856,11 +922,12 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.P
 		invoke := tx.Payload.(*payload.InvokeCode)
 
 		sc := smartcontract.SmartContract{
-			Config:  config,
-			Store:   this,
-			CacheDB: cache,
-			Gas:     math.MaxUint64 - calcGasByCodeLen(len(invoke.Code), preGas[neovm.UINT_INVOKE_CODE_LEN_NAME]),
-			PreExec: true,
+			Config:   config,
+			Store:    this,
+			CacheDB:  cache,
+			Gas:      math.MaxUint64 - calcGasByCodeLen(len(invoke.Code), preGas[neovm.UINT_INVOKE_CODE_LEN_NAME]),
+			PreExec:  true,
+			Debugger: debugger,
 		}
 
 		//start the smart contract executive function
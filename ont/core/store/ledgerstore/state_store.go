@@ -34,6 +34,7 @@ import (
 	"github.com/ontio/ontology/merkle"
 	"github.com/ontio/ontology/smartcontract/service/native/ontid"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	sstate "github.com/ontio/ontology/smartcontract/states"
 )
 
 var (
@@ -279,6 +280,23 @@ func (self *StateStore) getBookkeeperKey() ([]byte, error) {
 	return key, nil
 }
 
+//GetContractHistory return version history of upgraded contract, empty if contract never upgraded
+func (self *StateStore) GetContractHistory(contractHash common.Address) (*sstate.ContractHistory, error) {
+	key := append([]byte{byte(scom.ST_CONTRACT)}, sstate.ContractHistoryKey(contractHash)...)
+	value, err := self.store.Get(key)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, err
+	}
+	history := new(sstate.ContractHistory)
+	if len(value) == 0 {
+		return history, nil
+	}
+	if err := history.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
 func (self *StateStore) getContractStateKey(contractHash common.Address) ([]byte, error) {
 	data := contractHash[:]
 	key := make([]byte, 1+len(data))
@@ -58,10 +58,11 @@ func (self *StateStore) HandleDeployTransaction(store store.LedgerStore, overlay
 	if tx.GasPrice != 0 {
 		// init smart contract configuration info
 		config := &smartcontract.Config{
-			Time:      block.Header.Timestamp,
-			Height:    block.Header.Height,
-			Tx:        tx,
-			BlockHash: block.Hash(),
+			Time:             block.Header.Timestamp,
+			Height:           block.Header.Height,
+			Tx:               tx,
+			BlockHash:        block.Hash(),
+			ConsensusPayload: block.Header.ConsensusPayload,
 		}
 		createGasPrice, ok := neovm.GAS_TABLE.Load(neovm.CONTRACT_CREATE_NAME)
 		if !ok {
@@ -121,16 +122,18 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 	tx *types.Transaction, block *types.Block, notify *event.ExecuteNotify) error {
 	invoke := tx.Payload.(*payload.InvokeCode)
 	code := invoke.Code
-	sysTransFlag := bytes.Compare(code, ninit.COMMIT_DPOS_BYTES) == 0 || block.Header.Height == 0
+	sysTransFlag := bytes.Compare(code, ninit.COMMIT_DPOS_BYTES) == 0 || ninit.IsExecuteScheduledTx(tx, block.Header.Height) ||
+		block.Header.Height == 0
 
 	isCharge := !sysTransFlag && tx.GasPrice != 0
 
 	// init smart contract configuration info
 	config := &smartcontract.Config{
-		Time:      block.Header.Timestamp,
-		Height:    block.Header.Height,
-		Tx:        tx,
-		BlockHash: block.Hash(),
+		Time:             block.Header.Timestamp,
+		Height:           block.Header.Height,
+		Tx:               tx,
+		BlockHash:        block.Hash(),
+		ConsensusPayload: block.Header.ConsensusPayload,
 	}
 
 	var (
@@ -311,6 +314,7 @@ func refreshGlobalParam(config *smartcontract.Config, cache *storage.CacheDB, st
 		Gas:     math.MaxUint64,
 	}
 
+	//getGlobalParam reads at the block height, so scheduled params take effect once their height arrives
 	service, _ := sc.NewNativeService()
 	result, err := service.NativeCall(utils.ParamContractAddress, "getGlobalParam", bf.Bytes())
 	if err != nil {
@@ -322,6 +322,24 @@ func (p *MemDB) Delete(key []byte) {
 	p.Put(key, nil)
 }
 
+// Remove removes the entry of the given key, so that Get reports it unknown
+// instead of deleted. The key/value buffer is not reclaimed.
+//
+// It is safe to modify the contents of the arguments after Remove returns.
+func (p *MemDB) Remove(key []byte) {
+	node, exact := p.findGE(key, true)
+	if !exact {
+		return
+	}
+	h := p.nodeData[node+nHeight]
+	for i, n := range p.prevNode[:h] {
+		m := n + nNext + i
+		p.nodeData[m] = p.nodeData[p.nodeData[m]+nNext+i]
+	}
+	p.kvSize -= p.nodeData[node+nKey] + p.nodeData[node+nVal]
+	p.n--
+}
+
 // Get gets the value for the given key. It returns unkown == true if the
 // MemDB does not contain the key. It returns nil, false if MemDB has deleted the key
 //
@@ -35,3 +35,32 @@ func TestIter(t *testing.T) {
 	assert.Equal(t, iter.Last(), true)
 	assert.Equal(t, len(iter.Value()), 0)
 }
+
+func TestRemove(t *testing.T) {
+	db := NewMemDB(0, 0)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		db.Put([]byte(key), []byte(key))
+	}
+	db.Delete([]byte("c"))
+	db.Remove([]byte("b"))
+	db.Remove([]byte("c"))
+	db.Remove([]byte("e"))
+	_, unknown := db.Get([]byte("b"))
+	assert.True(t, unknown)
+	_, unknown = db.Get([]byte("c"))
+	assert.True(t, unknown)
+	val, unknown := db.Get([]byte("d"))
+	assert.False(t, unknown)
+	assert.Equal(t, []byte("d"), val)
+	assert.Equal(t, 2, db.Len())
+
+	var keys []string
+	db.ForEach(func(key, val []byte) {
+		keys = append(keys, string(key))
+	})
+	assert.Equal(t, []string{"a", "d"}, keys)
+
+	db.Put([]byte("b"), []byte("bb"))
+	val, _ = db.Get([]byte("b"))
+	assert.Equal(t, []byte("bb"), val)
+}
@@ -19,7 +19,11 @@
 package actor
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/common/log"
 	cactor "github.com/ontio/ontology/consensus/actor"
 )
 
@@ -44,3 +48,21 @@ func ConsensusSrvHalt() error {
 	}
 	return nil
 }
+
+//get consensus metrics of the last count blocks from consensus actor
+func GetConsensusMetrics(count uint32) (interface{}, error) {
+	if consensusSrvPid == nil {
+		return nil, errors.New("consensus not started")
+	}
+	future := consensusSrvPid.RequestFuture(&cactor.GetConsensusMetricsReq{Count: count}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	r, ok := result.(*cactor.GetConsensusMetricsRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return r.Metrics, nil
+}
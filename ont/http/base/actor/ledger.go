@@ -24,6 +24,8 @@ import (
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/profiler"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
 	cstate "github.com/ontio/ontology/smartcontract/states"
 )
 
@@ -78,6 +80,12 @@ func GetContractStateFromStore(hash common.Address) (*payload.DeployCode, error)
 	return ledger.DefLedger.GetContractState(hash)
 }
 
+//GetContractHistoryFromStore from ledger
+func GetContractHistoryFromStore(hash common.Address) (*cstate.ContractHistory, error) {
+	hash = updateNativeSCAddr(hash)
+	return ledger.DefLedger.GetContractHistory(hash)
+}
+
 //GetTxnWithHeightByTxHash from ledger
 func GetTxnWithHeightByTxHash(hash common.Uint256) (uint32, *types.Transaction, error) {
 	tx, height, err := ledger.DefLedger.GetTransactionWithHeight(hash)
@@ -89,6 +97,22 @@ func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return ledger.DefLedger.PreExecuteContract(tx)
 }
 
+//PreExecuteContractWithDebugger from ledger
+func PreExecuteContractWithDebugger(tx *types.Transaction, debugger neovm.Debugger) (*cstate.PreExecResult, error) {
+	return ledger.DefLedger.PreExecuteContractWithDebugger(tx, debugger)
+}
+
+//PreExecuteContractWithProfile from ledger, with the gas breakdown of neovm execution
+func PreExecuteContractWithProfile(tx *types.Transaction) (*cstate.PreExecResult, error) {
+	p := profiler.NewProfiler()
+	result, err := ledger.DefLedger.PreExecuteContractWithDebugger(tx, p)
+	if err != nil {
+		return nil, err
+	}
+	result.Profile = p.Profile()
+	return result, nil
+}
+
 //GetEventNotifyByTxHash from ledger
 func GetEventNotifyByTxHash(txHash common.Uint256) (*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByTx(txHash)
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/ontio/ontology-eventbus/actor"
+	comm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	ac "github.com/ontio/ontology/p2pserver/actor/server"
 	"github.com/ontio/ontology/p2pserver/common"
@@ -204,3 +205,40 @@ func GetNodeType() (uint64, error) {
 	}
 	return r.NodeType, nil
 }
+
+//GetNeighborMsgStats from netSever actor
+func GetNeighborMsgStats() ([]*common.PeerMsgStats, error) {
+	if netServerPid == nil {
+		return []*common.PeerMsgStats{}, nil
+	}
+	future := netServerPid.RequestFuture(&ac.GetNeighborMsgStatsReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	r, ok := result.(*ac.GetNeighborMsgStatsRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return r.Stats, nil
+}
+
+//GetVerifiedTx from netSever actor, the tx is verified by merkle proof against local header
+func GetVerifiedTx(txHash comm.Uint256) (*common.AppendTxProof, error) {
+	if netServerPid == nil {
+		return nil, errors.New("net server not started")
+	}
+	future := netServerPid.RequestFuture(&ac.GetTxProofReq{TxHash: txHash},
+		(common.TX_PROOF_TIMEOUT+REQ_TIMEOUT)*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	r, ok := result.(*ac.GetTxProofRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return r.Proof, r.Error
+}
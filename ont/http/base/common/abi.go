@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/core/payload"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/core/types"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/abi"
+	"github.com/ontio/ontology/smartcontract/service/native/contract_abi"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/vm/neovm"
+)
+
+//DecodedEvent is a notify event decoded by the ABI of contract
+type DecodedEvent struct {
+	Name   string
+	Params []*DecodedParam
+}
+
+//DecodedParam is a typed value decoded by ABI. Addresses are in base58, integers are decimal strings,
+//strings are UTF-8. Values of other types or failed to decode are kept in the raw form
+type DecodedParam struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+//GetContractAbi return the ABI JSON of contract registered on chain, nil if not registered
+func GetContractAbi(address common.Address) (json.RawMessage, error) {
+	value, err := bactor.GetStorageItem(utils.AbiContractAddress, contract_abi.AbiKey(address))
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return json.RawMessage(value), nil
+}
+
+//GetNeovmAbi return the ABI of contract registered locally in abi.DefNeovmAbiRegistry, or on chain. nil if not found
+func GetNeovmAbi(address common.Address) *abi.NeovmContractAbi {
+	if contractAbi := abi.DefNeovmAbiRegistry.GetNeovmAbi(address.ToHexString()); contractAbi != nil {
+		return contractAbi
+	}
+	data, err := GetContractAbi(address)
+	if err != nil || data == nil {
+		return nil
+	}
+	contractAbi := &abi.NeovmContractAbi{}
+	if err := json.Unmarshal(data, contractAbi); err != nil {
+		log.Debugf("GetNeovmAbi unmarshal abi of %s error:%s", address.ToHexString(), err)
+		return nil
+	}
+	return contractAbi
+}
+
+//DecodeExecuteNotify return a copy of notify with events decoded by the ABIs of contracts
+func DecodeExecuteNotify(notify ExecuteNotify) ExecuteNotify {
+	notify.Notify = decodeNotifyEvents(notify.Notify)
+	return notify
+}
+
+//DecodePreExecuteResult return a copy of result with events and return value decoded by the ABIs of contracts.
+//The return value is decoded only when the transaction invokes a contract method by APPCALL
+func DecodePreExecuteResult(tx *types.Transaction, result PreExecuteResult) PreExecuteResult {
+	result.Notify = decodeNotifyEvents(result.Notify)
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return result
+	}
+	address, method, ok := parseInvokeMethod(invoke.Code)
+	if !ok {
+		return result
+	}
+	contractAbi := GetNeovmAbi(address)
+	if contractAbi == nil {
+		return result
+	}
+	if funcAbi := contractAbi.GetFunc(method); funcAbi != nil {
+		result.DecodedResult = &DecodedParam{
+			Name:  funcAbi.Name,
+			Type:  funcAbi.ReturnType,
+			Value: decodeValue(funcAbi.ReturnType, result.Result),
+		}
+	}
+	return result
+}
+
+func decodeNotifyEvents(events []NotifyEventInfo) []NotifyEventInfo {
+	decoded := make([]NotifyEventInfo, 0, len(events))
+	abis := make(map[string]*abi.NeovmContractAbi)
+	for _, evt := range events {
+		contractAbi, ok := abis[evt.ContractAddress]
+		if !ok {
+			if address, err := common.AddressFromHexString(evt.ContractAddress); err == nil {
+				contractAbi = GetNeovmAbi(address)
+			}
+			abis[evt.ContractAddress] = contractAbi
+		}
+		if contractAbi != nil {
+			evt.Decoded = decodeEvent(contractAbi, evt.States)
+		}
+		decoded = append(decoded, evt)
+	}
+	return decoded
+}
+
+//decodeEvent decode the states of neovm notify, whose first item is the event name
+func decodeEvent(contractAbi *abi.NeovmContractAbi, states interface{}) *DecodedEvent {
+	items, ok := states.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	name, ok := decodeValue(abi.NEOVM_PARAM_TYPE_STRING, items[0]).(string)
+	if !ok {
+		return nil
+	}
+	evtAbi := contractAbi.GetEvent(name)
+	if evtAbi == nil {
+		return nil
+	}
+	evt := &DecodedEvent{Name: evtAbi.Name}
+	for i, param := range evtAbi.Parameters {
+		if i+1 >= len(items) {
+			break
+		}
+		evt.Params = append(evt.Params, &DecodedParam{
+			Name:  param.Name,
+			Type:  param.Type,
+			Value: decodeValue(param.Type, items[i+1]),
+		})
+	}
+	return evt
+}
+
+//decodeValue decode the hex string converted from neovm stack item by ABI type
+func decodeValue(typ string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		return value
+	}
+	switch strings.ToLower(typ) {
+	case "address", "hash160":
+		address, err := common.AddressParseFromBytes(data)
+		if err != nil {
+			return value
+		}
+		return address.ToBase58()
+	case "hash256":
+		hash, err := common.Uint256ParseFromBytes(data)
+		if err != nil {
+			return value
+		}
+		return hash.ToHexString()
+	case abi.NEOVM_PARAM_TYPE_INTEGER:
+		return common.BigIntFromNeoBytes(data).String()
+	case abi.NEOVM_PARAM_TYPE_BOOL:
+		for _, b := range data {
+			if b != 0 {
+				return true
+			}
+		}
+		return false
+	case abi.NEOVM_PARAM_TYPE_STRING:
+		if !utf8.Valid(data) {
+			return value
+		}
+		return string(data)
+	default:
+		return value
+	}
+}
+
+//parseInvokeMethod return the contract and method of invoke code ending with APPCALL, like the
+//code built by BuildNeoVMInvokeCode. The method is the last data pushed before APPCALL
+func parseInvokeMethod(code []byte) (common.Address, string, bool) {
+	var last []byte
+	for i := 0; i < len(code); {
+		op := neovm.OpCode(code[i])
+		n := 0
+		switch {
+		case op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75:
+			n = int(op)
+			i++
+		case op == neovm.PUSHDATA1 && i+1 < len(code):
+			n = int(code[i+1])
+			i += 2
+		case op == neovm.PUSHDATA2 && i+2 < len(code):
+			n = int(code[i+1]) | int(code[i+2])<<8
+			i += 3
+		case op == neovm.APPCALL:
+			if i+1+common.ADDR_LEN != len(code) || last == nil {
+				return common.ADDRESS_EMPTY, "", false
+			}
+			address, err := common.AddressParseFromBytes(code[i+1:])
+			if err != nil {
+				return common.ADDRESS_EMPTY, "", false
+			}
+			return address, string(last), true
+		case op >= neovm.JMP && op <= neovm.CALL, op == neovm.SYSCALL, op == neovm.TAILCALL, op == neovm.PUSHDATA4:
+			return common.ADDRESS_EMPTY, "", false
+		default:
+			last = nil
+			i++
+			continue
+		}
+		if i+n > len(code) {
+			return common.ADDRESS_EMPTY, "", false
+		}
+		last = code[i : i+n]
+		i += n
+	}
+	return common.ADDRESS_EMPTY, "", false
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/service/native/auth"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+type RoleHolderRsp struct {
+	OntID        string
+	ExpireHeight uint32
+	Expired      bool
+}
+
+type roleQuery struct {
+	ContractAddr []byte
+	Role         []byte
+}
+
+//preExecuteNative pre-execute the read-only method of native contract and return the raw result
+func preExecuteNative(contract common.Address, method string, param interface{}) ([]byte, error) {
+	mutable, err := NewNativeInvokeTransaction(0, 0, contract, 0, method,
+		[]interface{}{param})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if result.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	return hex.DecodeString(result.Result.(string))
+}
+
+//GetContractRoles return all roles defined for contract in auth contract
+func GetContractRoles(contract common.Address) ([]string, error) {
+	data, err := preExecuteNative(utils.AuthContractAddress, "getRoles", contract[:])
+	if err != nil {
+		return nil, err
+	}
+	roles := new(auth.RoleList)
+	if err := roles.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("deserialize role list error:%s", err)
+	}
+	rsp := make([]string, 0, len(roles.Roles))
+	for _, r := range roles.Roles {
+		rsp = append(rsp, string(r))
+	}
+	return rsp, nil
+}
+
+//GetRoleFuncs return function names the role of contract is allowed to call
+func GetRoleFuncs(contract common.Address, role string) ([]string, error) {
+	data, err := preExecuteNative(utils.AuthContractAddress, "getRoleFuncs", &roleQuery{ContractAddr: contract[:], Role: []byte(role)})
+	if err != nil {
+		return nil, err
+	}
+	funcs := new(auth.FuncList)
+	if err := funcs.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("deserialize function list error:%s", err)
+	}
+	if funcs.FuncNames == nil {
+		return []string{}, nil
+	}
+	return funcs.FuncNames, nil
+}
+
+//GetRoleHolders return ONT IDs assigned to the role of contract
+func GetRoleHolders(contract common.Address, role string) ([]*RoleHolderRsp, error) {
+	data, err := preExecuteNative(utils.AuthContractAddress, "getRoleHolders", &roleQuery{ContractAddr: contract[:], Role: []byte(role)})
+	if err != nil {
+		return nil, err
+	}
+	holders := new(auth.RoleHolders)
+	if err := holders.Deserialize(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("deserialize role holders error:%s", err)
+	}
+	//next block is the first one the expire height applies to
+	height := bactor.GetCurrentBlockHeight()
+	rsp := make([]*RoleHolderRsp, 0, len(holders.Holders))
+	for _, h := range holders.Holders {
+		rsp = append(rsp, &RoleHolderRsp{
+			OntID:        string(h.OntID),
+			ExpireHeight: h.ExpireHeight,
+			Expired:      h.ExpireHeight != 0 && height+1 >= h.ExpireHeight,
+		})
+	}
+	return rsp, nil
+}
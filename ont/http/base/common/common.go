@@ -34,8 +34,11 @@ import (
 	cutils "github.com/ontio/ontology/core/utils"
 	ontErrors "github.com/ontio/ontology/errors"
 	bactor "github.com/ontio/ontology/http/base/actor"
+	p2pcomm "github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native/credential"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	cstate "github.com/ontio/ontology/smartcontract/states"
 	"github.com/ontio/ontology/vm/neovm"
@@ -50,6 +53,15 @@ type BalanceOfRsp struct {
 	Ong string `json:"ong"`
 }
 
+type CredentialStatus struct {
+	ClaimId      string
+	Issuer       string
+	Subject      string
+	Status       string
+	CommitHeight uint32
+	RevokeHeight uint32
+}
+
 type MerkleProof struct {
 	Type             string
 	TransactionsRoot string
@@ -59,6 +71,15 @@ type MerkleProof struct {
 	TargetHashes     []string
 }
 
+type VerifiedTx struct {
+	Transaction      *Transactions
+	BlockHeight      uint32
+	TransactionsRoot string
+	TxIndex          uint32
+	TargetHashes     []string
+	UnverifiedNotify *ExecuteNotify //events reported by the peer, not proven by the block header
+}
+
 type LogEventArgs struct {
 	TxHash          string
 	ContractAddress string
@@ -73,15 +94,29 @@ type ExecuteNotify struct {
 }
 
 type PreExecuteResult struct {
-	State  byte
-	Gas    uint64
-	Result interface{}
-	Notify []NotifyEventInfo
+	State         byte
+	Gas           uint64
+	Result        interface{}
+	Notify        []NotifyEventInfo
+	Profile       *cstate.Profile `json:",omitempty"`
+	DecodedResult *DecodedParam   `json:",omitempty"`
 }
 
 type NotifyEventInfo struct {
 	ContractAddress string
 	States          interface{}
+	Decoded         *DecodedEvent `json:",omitempty"`
+}
+
+type ContractVersionRsp struct {
+	Height      uint32
+	TxHash      string
+	CodeHash    string
+	Name        string
+	Version     string
+	Author      string
+	Email       string
+	Description string
 }
 
 type TxAttributeInfo struct {
@@ -153,6 +188,8 @@ type NodeInfo struct {
 	Height      uint32   // The node latest block height
 	TxnCnt      []uint32 // The transactions in pool
 	//RxTxnCnt uint64 // The transaction received by this node
+	MsgStats  map[string]*p2pcomm.MsgStat // Msg statistics of all nbr peers by msg type
+	PeerStats []*p2pcomm.PeerMsgStats     // Msg statistics of each nbr peer by msg type
 }
 
 type ConsensusInfo struct {
@@ -180,7 +217,7 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 	evts := []NotifyEventInfo{}
 	var contractAddrs = make(map[string]bool)
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, NotifyEventInfo{ContractAddress: v.ContractAddress.ToHexString(), States: v.States})
 		contractAddrs[v.ContractAddress.ToHexString()] = true
 	}
 	txhash := obj.TxHash.ToHexString()
@@ -190,9 +227,9 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
 	evts := []NotifyEventInfo{}
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, NotifyEventInfo{ContractAddress: v.ContractAddress.ToHexString(), States: v.States})
 	}
-	return PreExecuteResult{obj.State, obj.Gas, obj.Result, evts}
+	return PreExecuteResult{State: obj.State, Gas: obj.Gas, Result: obj.Result, Notify: evts, Profile: obj.Profile}
 }
 
 func TransArryByteToHexString(ptx *types.Transaction) *Transactions {
@@ -384,6 +421,142 @@ func GetContractAllowance(cVersion byte, contractAddr, fromAddr, toAddr common.A
 	return allowance.Uint64(), nil
 }
 
+//GetContractHistory return the versions of contract from the oldest to the current one.
+//Contract never upgraded has only the current version
+func GetContractHistory(address common.Address) ([]*ContractVersionRsp, error) {
+	contract, err := bactor.GetContractStateFromStore(address)
+	if err != nil {
+		return nil, err
+	}
+	history, err := bactor.GetContractHistoryFromStore(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Versions) == 0 {
+		history.Versions = append(history.Versions, &cstate.ContractVersion{
+			CodeHash:    contract.Address(),
+			Name:        contract.Name,
+			Version:     contract.Version,
+			Author:      contract.Author,
+			Email:       contract.Email,
+			Description: contract.Description,
+		})
+	}
+	rsp := make([]*ContractVersionRsp, 0, len(history.Versions))
+	for _, v := range history.Versions {
+		info := &ContractVersionRsp{
+			Height:      v.Height,
+			CodeHash:    v.CodeHash.ToHexString(),
+			Name:        v.Name,
+			Version:     v.Version,
+			Author:      v.Author,
+			Email:       v.Email,
+			Description: v.Description,
+		}
+		if v.TxHash != common.UINT256_EMPTY {
+			info.TxHash = v.TxHash.ToHexString()
+		}
+		rsp = append(rsp, info)
+	}
+	return rsp, nil
+}
+
+//GetDIDDocument return the DID document of the ONT ID at height, 0 for the latest
+func GetDIDDocument(did string, height uint32) (*ontid.Document, error) {
+	if err := ontid.VerifyDID(did); err != nil {
+		return nil, err
+	}
+	type idStateParam struct {
+		Id     []byte
+		Height uint32
+	}
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.OntIDContractAddress, 0, "getIDState",
+		[]interface{}{&idStateParam{
+			Id:     []byte(did),
+			Height: height,
+		}})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if result.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	data, err := hex.DecodeString(result.Result.(string))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return ontid.NewDocument(did, data)
+}
+
+//GetCredentialStatus return the status of the credential of issuer in credential registry
+func GetCredentialStatus(issuer string, claimId []byte) (*CredentialStatus, error) {
+	type statusParam struct {
+		Issuer  []byte
+		ClaimId []byte
+	}
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.CredentialContractAddress, 0, credential.GET_STATUS,
+		[]interface{}{&statusParam{
+			Issuer:  []byte(issuer),
+			ClaimId: claimId,
+		}})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if result.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	data, err := hex.DecodeString(result.Result.(string))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	status := &CredentialStatus{
+		ClaimId: common.ToHexString(claimId),
+		Issuer:  issuer,
+		Status:  "not exist",
+	}
+	if len(data) == 0 {
+		return status, nil
+	}
+	record := new(credential.Record)
+	if err := record.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, fmt.Errorf("deserialize credential record error:%s", err)
+	}
+	status.Issuer = string(record.Issuer)
+	status.Subject = string(record.Subject)
+	status.CommitHeight = record.CommitHeight
+	status.RevokeHeight = record.RevokeHeight
+	switch record.Status {
+	case credential.STATUS_COMMITTED:
+		status.Status = "committed"
+	case credential.STATUS_REVOKED:
+		status.Status = "revoked"
+	case credential.STATUS_REMOVED:
+		status.Status = "removed"
+	default:
+		return nil, fmt.Errorf("unknown credential status:%d", record.Status)
+	}
+	return status, nil
+}
+
 func GetGasPrice() (map[string]interface{}, error) {
 	start := bactor.GetCurrentBlockHeight()
 	var gasPrice uint64 = 0
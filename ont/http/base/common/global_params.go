@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ontio/ontology/smartcontract/service/native/global_params"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+var valueTypeName = map[global_params.ValueType]string{
+	global_params.VALUE_TYPE_STRING: "string",
+	global_params.VALUE_TYPE_UINT64: "uint64",
+	global_params.VALUE_TYPE_BOOL:   "bool",
+}
+
+type ParamRecordRsp struct {
+	Height uint32
+	Value  string
+}
+
+type ParamSpecRsp struct {
+	Key  string
+	Type string
+	Min  uint64
+	Max  uint64
+}
+
+type paramQuery struct {
+	Height uint32
+	Names  []string
+}
+
+//GetGlobalParams return value of global params effective at height
+func GetGlobalParams(names []string, height uint32) (map[string]string, error) {
+	data, err := preExecuteNative(utils.ParamContractAddress, global_params.GET_GLOBAL_PARAM_AT_NAME,
+		&paramQuery{Height: height, Names: names})
+	if err != nil {
+		return nil, err
+	}
+	params := global_params.Params{}
+	if err := params.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, fmt.Errorf("deserialize params error:%s", err)
+	}
+	rsp := make(map[string]string, len(params))
+	for _, param := range params {
+		rsp[param.Key] = param.Value
+	}
+	return rsp, nil
+}
+
+//GetParamHistory return all records of global param, including the scheduled ones
+func GetParamHistory(name string) ([]*ParamRecordRsp, error) {
+	data, err := preExecuteNative(utils.ParamContractAddress, global_params.GET_PARAM_HISTORY_NAME, name)
+	if err != nil {
+		return nil, err
+	}
+	history := global_params.ParamHistory{}
+	if err := history.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, fmt.Errorf("deserialize param history error:%s", err)
+	}
+	rsp := make([]*ParamRecordRsp, 0, len(history))
+	for _, record := range history {
+		rsp = append(rsp, &ParamRecordRsp{Height: record.Height, Value: record.Value})
+	}
+	return rsp, nil
+}
+
+//GetParamSpec return the spec of global param, nil if it is not declared
+func GetParamSpec(name string) (*ParamSpecRsp, error) {
+	data, err := preExecuteNative(utils.ParamContractAddress, global_params.GET_PARAM_SPEC_NAME, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	spec := new(global_params.ParamSpec)
+	if err := spec.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, fmt.Errorf("deserialize param spec error:%s", err)
+	}
+	return &ParamSpecRsp{
+		Key:  spec.Key,
+		Type: valueTypeName[spec.Type],
+		Min:  spec.Min,
+		Max:  spec.Max,
+	}, nil
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/constants"
+	scom "github.com/ontio/ontology/core/store/common"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	"github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const MAX_SPLIT_FEE_VIEWS uint32 = 100
+
+var peerStatusName = map[governance.Status]string{
+	governance.RegisterCandidateStatus: "registered",
+	governance.CandidateStatus:         "candidate",
+	governance.ConsensusStatus:         "consensus",
+	governance.QuitConsensusStatus:     "quit consensus",
+	governance.QuitingStatus:           "quiting",
+	governance.BlackStatus:             "black",
+}
+
+type PeerInfo struct {
+	Index        uint32
+	PeerPubkey   string
+	Address      string
+	Status       string
+	InitPos      uint64
+	TotalPos     uint64
+	MaxAuthorize uint64
+	PeerCost     uint64
+	NextPeerCost uint64
+}
+
+type PeerPoolRsp struct {
+	View  uint32
+	Peers []*PeerInfo
+}
+
+type AuthorizeInfoRsp struct {
+	PeerPubkey           string
+	ConsensusPos         uint64
+	CandidatePos         uint64
+	NewPos               uint64
+	WithdrawConsensusPos uint64
+	WithdrawCandidatePos uint64
+	WithdrawUnfreezePos  uint64
+}
+
+type WithdrawableRsp struct {
+	Address    string
+	Stake      uint64
+	Ont        uint64
+	FrozenOnt  uint64
+	UnboundOng uint64
+	SplitFee   uint64
+}
+
+type SplitFeeRecordRsp struct {
+	View   uint32
+	Height uint32
+	Amount uint64
+}
+
+//read raw value of governance contract storage, return nil if key not exist
+func getGovernanceStorage(key []byte) ([]byte, error) {
+	value, err := bactor.GetStorageItem(utils.GovernanceContractAddress, key)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, err
+	}
+	return value, nil
+}
+
+func GetGovernanceView() (uint32, error) {
+	value, err := getGovernanceStorage([]byte(governance.GOVERNANCE_VIEW))
+	if err != nil {
+		return 0, err
+	}
+	governanceView := new(governance.GovernanceView)
+	if err := governanceView.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return 0, fmt.Errorf("deserialize governanceView error:%s", err)
+	}
+	return governanceView.View, nil
+}
+
+func getPeerPoolMap(view uint32) (*governance.PeerPoolMap, error) {
+	viewBytes, err := governance.GetUint32Bytes(view)
+	if err != nil {
+		return nil, err
+	}
+	value, err := getGovernanceStorage(append([]byte(governance.PEER_POOL), viewBytes...))
+	if err != nil {
+		return nil, err
+	}
+	peerPoolMap := &governance.PeerPoolMap{
+		PeerPoolMap: make(map[string]*governance.PeerPoolItem),
+	}
+	if err := peerPoolMap.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return nil, fmt.Errorf("deserialize peerPoolMap error:%s", err)
+	}
+	return peerPoolMap, nil
+}
+
+func getPeerAttributes(peerPubkey string) (*governance.PeerAttributes, error) {
+	pubkey, err := hex.DecodeString(peerPubkey)
+	if err != nil {
+		return nil, err
+	}
+	value, err := getGovernanceStorage(append([]byte(governance.PEER_ATTRIBUTES), pubkey...))
+	if err != nil {
+		return nil, err
+	}
+	peerAttributes := &governance.PeerAttributes{
+		PeerPubkey: peerPubkey,
+		T2PeerCost: 100,
+		T1PeerCost: 100,
+		TPeerCost:  100,
+	}
+	if value != nil {
+		if err := peerAttributes.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize peerAttributes error:%s", err)
+		}
+	}
+	return peerAttributes, nil
+}
+
+//GetPeerPool return peers of current governance view with their status and stake
+func GetPeerPool() (*PeerPoolRsp, error) {
+	view, err := GetGovernanceView()
+	if err != nil {
+		return nil, err
+	}
+	peerPoolMap, err := getPeerPoolMap(view)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &PeerPoolRsp{
+		View:  view,
+		Peers: make([]*PeerInfo, 0, len(peerPoolMap.PeerPoolMap)),
+	}
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		peerAttributes, err := getPeerAttributes(peerPoolItem.PeerPubkey)
+		if err != nil {
+			return nil, err
+		}
+		rsp.Peers = append(rsp.Peers, &PeerInfo{
+			Index:        peerPoolItem.Index,
+			PeerPubkey:   peerPoolItem.PeerPubkey,
+			Address:      peerPoolItem.Address.ToBase58(),
+			Status:       peerStatusName[peerPoolItem.Status],
+			InitPos:      peerPoolItem.InitPos,
+			TotalPos:     peerPoolItem.TotalPos,
+			MaxAuthorize: peerAttributes.MaxAuthorize,
+			PeerCost:     peerAttributes.TPeerCost,
+			NextPeerCost: peerAttributes.T1PeerCost,
+		})
+	}
+	sort.Slice(rsp.Peers, func(i, j int) bool {
+		return rsp.Peers[i].Index < rsp.Peers[j].Index
+	})
+	return rsp, nil
+}
+
+//GetAuthorizeInfos return authorizations of address to peers in current peer pool
+func GetAuthorizeInfos(address common.Address) ([]*AuthorizeInfoRsp, error) {
+	view, err := GetGovernanceView()
+	if err != nil {
+		return nil, err
+	}
+	peerPoolMap, err := getPeerPoolMap(view)
+	if err != nil {
+		return nil, err
+	}
+	rsp := make([]*AuthorizeInfoRsp, 0)
+	for peerPubkey := range peerPoolMap.PeerPoolMap {
+		pubkey, err := hex.DecodeString(peerPubkey)
+		if err != nil {
+			return nil, err
+		}
+		key := append([]byte{}, governance.AUTHORIZE_INFO_POOL...)
+		key = append(append(key, pubkey...), address[:]...)
+		value, err := getGovernanceStorage(key)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		authorizeInfo := new(governance.AuthorizeInfo)
+		if err := authorizeInfo.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize authorizeInfo error:%s", err)
+		}
+		rsp = append(rsp, &AuthorizeInfoRsp{
+			PeerPubkey:           authorizeInfo.PeerPubkey,
+			ConsensusPos:         authorizeInfo.ConsensusPos,
+			CandidatePos:         authorizeInfo.CandidatePos,
+			NewPos:               authorizeInfo.NewPos,
+			WithdrawConsensusPos: authorizeInfo.WithdrawConsensusPos,
+			WithdrawCandidatePos: authorizeInfo.WithdrawCandidatePos,
+			WithdrawUnfreezePos:  authorizeInfo.WithdrawUnfreezePos,
+		})
+	}
+	sort.Slice(rsp, func(i, j int) bool {
+		return rsp[i].PeerPubkey < rsp[j].PeerPubkey
+	})
+	return rsp, nil
+}
+
+//GetWithdrawable return ONT and ONG of address which can be withdrawn from governance contract
+func GetWithdrawable(address common.Address) (*WithdrawableRsp, error) {
+	authorizeInfos, err := GetAuthorizeInfos(address)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &WithdrawableRsp{
+		Address: address.ToBase58(),
+	}
+	for _, authorizeInfo := range authorizeInfos {
+		rsp.Ont += authorizeInfo.WithdrawUnfreezePos
+		rsp.FrozenOnt += authorizeInfo.WithdrawConsensusPos + authorizeInfo.WithdrawCandidatePos
+	}
+
+	value, err := getGovernanceStorage(append([]byte(governance.TOTAL_STAKE), address[:]...))
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		totalStake := new(governance.TotalStake)
+		if err := totalStake.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize totalStake error:%s", err)
+		}
+		rsp.Stake = totalStake.Stake
+		rsp.UnboundOng = utils.CalcUnbindOng(totalStake.Stake, totalStake.TimeOffset,
+			uint32(time.Now().Unix())-constants.GENESIS_BLOCK_TIMESTAMP)
+	}
+
+	value, err = getGovernanceStorage(append([]byte(governance.SPLIT_FEE_ADDRESS), address[:]...))
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		splitFeeAddress := new(governance.SplitFeeAddress)
+		if err := splitFeeAddress.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize splitFeeAddress error:%s", err)
+		}
+		rsp.SplitFee = splitFeeAddress.Amount
+	}
+	return rsp, nil
+}
+
+//GetSplitFeeHistory return ong split of governance views in [start, end], views before the native upgrade are not recorded
+func GetSplitFeeHistory(start, end uint32) ([]*SplitFeeRecordRsp, error) {
+	if start > end || end-start >= MAX_SPLIT_FEE_VIEWS {
+		return nil, fmt.Errorf("invalid view range [%d, %d]", start, end)
+	}
+	rsp := make([]*SplitFeeRecordRsp, 0)
+	for view := start; view <= end; view++ {
+		viewBytes, err := governance.GetUint32Bytes(view)
+		if err != nil {
+			return nil, err
+		}
+		value, err := getGovernanceStorage(append([]byte(governance.SPLIT_FEE_HISTORY), viewBytes...))
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		splitFeeRecord := new(governance.SplitFeeRecord)
+		if err := splitFeeRecord.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize splitFeeRecord error:%s", err)
+		}
+		rsp = append(rsp, &SplitFeeRecordRsp{
+			View:   splitFeeRecord.View,
+			Height: splitFeeRecord.Height,
+			Amount: splitFeeRecord.Amount,
+		})
+	}
+	return rsp, nil
+}
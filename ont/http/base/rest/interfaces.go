@@ -29,6 +29,7 @@ import (
 	bactor "github.com/ontio/ontology/http/base/actor"
 	bcomn "github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	"strconv"
 )
@@ -268,14 +269,22 @@ func SendRawTransaction(cmd map[string]interface{}) map[string]interface{} {
 	log.Debugf("SendRawTransaction recv %s", hash.ToHexString())
 	if txn.TxType == types.Invoke || txn.TxType == types.Deploy {
 		if preExec, ok := cmd["PreExec"].(string); ok && preExec == "1" {
-			rst, err := bactor.PreExecuteContract(txn)
+			preExecute := bactor.PreExecuteContract
+			if profile, ok := cmd["Profile"].(string); ok && profile == "1" {
+				preExecute = bactor.PreExecuteContractWithProfile
+			}
+			rst, err := preExecute(txn)
 			if err != nil {
 				log.Infof("PreExec: ", err)
 				resp = ResponsePack(berr.SMARTCODE_ERROR)
 				resp["Result"] = err.Error()
 				return resp
 			}
-			resp["Result"] = bcomn.ConvertPreExecuteResult(rst)
+			result := bcomn.ConvertPreExecuteResult(rst)
+			if decode, ok := cmd["Decode"].(string); ok && decode == "1" {
+				result = bcomn.DecodePreExecuteResult(txn, result)
+			}
+			resp["Result"] = result
 			return resp
 		}
 	}
@@ -317,6 +326,9 @@ func GetSmartCodeEventTxsByHeight(cmd map[string]interface{}) map[string]interfa
 	eInfos := make([]*bcomn.ExecuteNotify, 0, len(eventInfos))
 	for _, eventInfo := range eventInfos {
 		_, notify := bcomn.GetExecuteNotify(eventInfo)
+		if decode, ok := cmd["Decode"].(string); ok && decode == "1" {
+			notify = bcomn.DecodeExecuteNotify(notify)
+		}
 		eInfos = append(eInfos, &notify)
 	}
 	resp["Result"] = eInfos
@@ -350,6 +362,9 @@ func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{
 		return ResponsePack(berr.INVALID_TRANSACTION)
 	}
 	_, notify := bcomn.GetExecuteNotify(eventInfo)
+	if decode, ok := cmd["Decode"].(string); ok && decode == "1" {
+		notify = bcomn.DecodeExecuteNotify(notify)
+	}
 	resp["Result"] = notify
 	return resp
 }
@@ -551,6 +566,130 @@ func GetGrantOng(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//get DID document of ONT ID, optionally at a block height
+func GetDIDDocument(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	did, ok := cmd["Did"].(string)
+	if !ok || ontid.VerifyDID(did) != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	var height uint32
+	if param, ok := cmd["Height"].(string); ok && len(param) > 0 {
+		h, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		height = uint32(h)
+	}
+	doc, err := bcomn.GetDIDDocument(did, height)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	if doc == nil {
+		return ResponsePack(berr.UNKNOWN_ONTID)
+	}
+	resp["Result"] = doc
+	return resp
+}
+
+//get credential status of issuer in credential registry
+func GetCredentialStatus(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	issuer, ok := cmd["Issuer"].(string)
+	if !ok || ontid.VerifyDID(issuer) != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	str, ok := cmd["Hash"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	claimId, err := common.HexToBytes(str)
+	if err != nil || len(claimId) == 0 {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	rsp, err := bcomn.GetCredentialStatus(issuer, claimId)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = rsp
+	return resp
+}
+
+//get peers of current governance view
+func GetPeerPool(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	rsp, err := bcomn.GetPeerPool()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = rsp
+	return resp
+}
+
+//get authorizations of address to each peer
+func GetAuthorizeInfo(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	addrStr, ok := cmd["Addr"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	address, err := bcomn.GetAddress(addrStr)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	rsp, err := bcomn.GetAuthorizeInfos(address)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = rsp
+	return resp
+}
+
+//get ont and ong of address which can be withdrawn from governance contract
+func GetWithdrawable(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	addrStr, ok := cmd["Addr"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	address, err := bcomn.GetAddress(addrStr)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	rsp, err := bcomn.GetWithdrawable(address)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = rsp
+	return resp
+}
+
+//get fee split of governance views, end view is start view if not given
+func GetSplitFeeHistory(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	param, ok := cmd["View"].(string)
+	if !ok || len(param) == 0 {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	start, err := strconv.ParseUint(param, 10, 32)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	end := start
+	if param, ok := cmd["End"].(string); ok && len(param) > 0 {
+		end, err = strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+	}
+	rsp, err := bcomn.GetSplitFeeHistory(uint32(start), uint32(end))
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	resp["Result"] = rsp
+	return resp
+}
+
 //get memory pool transaction count
 func GetMemPoolTxCount(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -21,6 +21,7 @@ package rpc
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
@@ -31,6 +32,8 @@ import (
 	bactor "github.com/ontio/ontology/http/base/actor"
 	bcomn "github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 )
 
@@ -261,6 +264,8 @@ func GetStorage(params []interface{}) map[string]interface{} {
 //send raw transaction
 // A JSON example for sendrawtransaction method as following:
 //   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
+//   pre-execute with gas profile: "params": ["raw transactioin in hex", 1, 1]
+//   pre-execute with events and result decoded by ABI: "params": ["raw transactioin in hex", 1, 0, 1]
 func SendRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -283,12 +288,24 @@ func SendRawTransaction(params []interface{}) map[string]interface{} {
 			if len(params) > 1 {
 				preExec, ok := params[1].(float64)
 				if ok && preExec == 1 {
-					result, err := bactor.PreExecuteContract(txn)
+					preExecute := bactor.PreExecuteContract
+					if len(params) > 2 {
+						if profile, ok := params[2].(float64); ok && profile == 1 {
+							preExecute = bactor.PreExecuteContractWithProfile
+						}
+					}
+					result, err := preExecute(txn)
 					if err != nil {
 						log.Infof("PreExec: ", err)
 						return responsePack(berr.SMARTCODE_ERROR, err.Error())
 					}
-					return responseSuccess(bcomn.ConvertPreExecuteResult(result))
+					rsp := bcomn.ConvertPreExecuteResult(result)
+					if len(params) > 3 {
+						if decode, ok := params[3].(float64); ok && decode == 1 {
+							rsp = bcomn.DecodePreExecuteResult(txn, rsp)
+						}
+					}
+					return responseSuccess(rsp)
 				}
 			}
 		}
@@ -352,6 +369,50 @@ func GetContractState(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(w.Bytes()))
 }
 
+//get version history of contract, the address is kept after upgrade
+// A JSON example for getcontracthistory method as following:
+//   {"jsonrpc": "2.0", "method": "getcontracthistory", "params": ["contract address"], "id": 0}
+func GetContractHistory(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetContractHistory(address)
+	if err != nil {
+		return responsePack(berr.UNKNOWN_CONTRACT, "unknow contract")
+	}
+	return responseSuccess(rsp)
+}
+
+//get ABI of contract registered on chain, null if not registered
+// A JSON example for getcontractabi method as following:
+//   {"jsonrpc": "2.0", "method": "getcontractabi", "params": ["contract address"], "id": 0}
+func GetContractAbi(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	abi, err := bcomn.GetContractAbi(address)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(abi)
+}
+
 //get smartconstract event
 func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	if !config.DefConfig.Common.EnableEventLog {
@@ -360,6 +421,13 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
+	//params[1] is ignored unless it is numeric, as before decoding is supported
+	decode := false
+	if len(params) > 1 {
+		if flag, ok := params[1].(float64); ok {
+			decode = flag == 1
+		}
+	}
 
 	switch (params[0]).(type) {
 	// block height
@@ -375,6 +443,9 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 		eInfos := make([]*bcomn.ExecuteNotify, 0, len(eventInfos))
 		for _, eventInfo := range eventInfos {
 			_, notify := bcomn.GetExecuteNotify(eventInfo)
+			if decode {
+				notify = bcomn.DecodeExecuteNotify(notify)
+			}
 			eInfos = append(eInfos, &notify)
 		}
 		return responseSuccess(eInfos)
@@ -393,6 +464,9 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 			return responsePack(berr.INTERNAL_ERROR, "")
 		}
 		_, notify := bcomn.GetExecuteNotify(eventInfo)
+		if decode {
+			notify = bcomn.DecodeExecuteNotify(notify)
+		}
 		return responseSuccess(notify)
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -516,6 +590,49 @@ func GetMerkleProof(params []interface{}) map[string]interface{} {
 		curHeader.BlockRoot.ToHexString(), curHeight, hashes})
 }
 
+//get transaction with merkle proof from peers, verified against local block header.
+//Only the transaction is proven, the events are returned as UnverifiedNotify
+func GetVerifiedTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	proof, err := bactor.GetVerifiedTx(hash)
+	if err != nil {
+		return responsePack(berr.UNKNOWN_TRANSACTION, err.Error())
+	}
+	header, err := bactor.GetHeaderByHeight(proof.Height)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	var hashes []string
+	for _, v := range proof.Proof {
+		hashes = append(hashes, v.ToHexString())
+	}
+	rsp := bcomn.VerifiedTx{
+		Transaction:      bcomn.TransArryByteToHexString(proof.Tx),
+		BlockHeight:      proof.Height,
+		TransactionsRoot: header.TransactionsRoot.ToHexString(),
+		TxIndex:          proof.Index,
+		TargetHashes:     hashes,
+	}
+	if len(proof.Notify) > 0 {
+		notify := &event.ExecuteNotify{}
+		if err := json.Unmarshal(proof.Notify, notify); err == nil {
+			_, n := bcomn.GetExecuteNotify(notify)
+			rsp.UnverifiedNotify = &n
+		}
+	}
+	return responseSuccess(rsp)
+}
+
 //get block transactions by height
 func GetBlockTxsByHeight(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -538,6 +655,212 @@ func GetBlockTxsByHeight(params []interface{}) map[string]interface{} {
 	}
 }
 
+//get DID document of ONT ID, optionally at a block height
+// A JSON example for getdiddocument method as following:
+//   {"jsonrpc": "2.0", "method": "getdiddocument", "params": ["did:ont:xxx", 100], "id": 0}
+func GetDIDDocument(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	did, ok := params[0].(string)
+	if !ok || ontid.VerifyDID(did) != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var height uint32
+	if len(params) > 1 {
+		h, ok := params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		height = uint32(h)
+	}
+	doc, err := bcomn.GetDIDDocument(did, height)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	if doc == nil {
+		return responsePack(berr.UNKNOWN_ONTID, "")
+	}
+	return responseSuccess(doc)
+}
+
+//get credential status of issuer in credential registry
+// A JSON example for getcredentialstatus method as following:
+//   {"jsonrpc": "2.0", "method": "getcredentialstatus", "params": ["did:ont:issuer", "credential hash in hex"], "id": 0}
+func GetCredentialStatus(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	issuer, ok := params[0].(string)
+	if !ok || ontid.VerifyDID(issuer) != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	claimId, err := hex.DecodeString(str)
+	if err != nil || len(claimId) == 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetCredentialStatus(issuer, claimId)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get peers of current governance view
+// A JSON example for getpeerpool method as following:
+//   {"jsonrpc": "2.0", "method": "getpeerpool", "params": [], "id": 0}
+func GetPeerPool(params []interface{}) map[string]interface{} {
+	rsp, err := bcomn.GetPeerPool()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get authorizations of address to each peer
+// A JSON example for getauthorizeinfo method as following:
+//   {"jsonrpc": "2.0", "method": "getauthorizeinfo", "params": ["address in base58"], "id": 0}
+func GetAuthorizeInfo(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addrBase58, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetAuthorizeInfos(address)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get ont and ong of address which can be withdrawn from governance contract
+// A JSON example for getwithdrawable method as following:
+//   {"jsonrpc": "2.0", "method": "getwithdrawable", "params": ["address in base58"], "id": 0}
+func GetWithdrawable(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addrBase58, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetWithdrawable(address)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get fee split of governance views, end view is start view if not given
+// A JSON example for getsplitfeehistory method as following:
+//   {"jsonrpc": "2.0", "method": "getsplitfeehistory", "params": [10, 20], "id": 0}
+func GetSplitFeeHistory(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	start, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	end := start
+	if len(params) > 1 {
+		end, ok = params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	rsp, err := bcomn.GetSplitFeeHistory(uint32(start), uint32(end))
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get roles of contract in auth contract
+// A JSON example for getcontractroles method as following:
+//   {"jsonrpc": "2.0", "method": "getcontractroles", "params": ["contract address"], "id": 0}
+func GetContractRoles(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetContractRoles(contract)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+func parseRoleParams(params []interface{}) (common.Address, string, bool) {
+	if len(params) < 2 {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	role, ok := params[1].(string)
+	if !ok || role == "" {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	return contract, role, true
+}
+
+//get function names of role in auth contract
+// A JSON example for getrolefuncs method as following:
+//   {"jsonrpc": "2.0", "method": "getrolefuncs", "params": ["contract address", "role"], "id": 0}
+func GetRoleFuncs(params []interface{}) map[string]interface{} {
+	contract, role, ok := parseRoleParams(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetRoleFuncs(contract, role)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get ONT IDs assigned to role in auth contract
+// A JSON example for getroleholders method as following:
+//   {"jsonrpc": "2.0", "method": "getroleholders", "params": ["contract address", "role"], "id": 0}
+func GetRoleHolders(params []interface{}) map[string]interface{} {
+	contract, role, ok := parseRoleParams(params)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetRoleHolders(contract, role)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
 //get gas price in block
 func GetGasPrice(params []interface{}) map[string]interface{} {
 	result, err := bcomn.GetGasPrice()
@@ -587,3 +910,73 @@ func GetGrantOng(params []interface{}) map[string]interface{} {
 	}
 	return responseSuccess(rsp)
 }
+
+//get global params effective at height, the latest height if it is omitted
+// A JSON example for getglobalparams method as following:
+//   {"jsonrpc": "2.0", "method": "getglobalparams", "params": [["gasPrice"], 10000], "id": 0}
+func GetGlobalParams(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	list, ok := params[0].([]interface{})
+	if !ok || len(list) == 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	names := make([]string, 0, len(list))
+	for _, v := range list {
+		name, ok := v.(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		names = append(names, name)
+	}
+	height := bactor.GetCurrentBlockHeight()
+	if len(params) > 1 {
+		h, ok := params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		height = uint32(h)
+	}
+	rsp, err := bcomn.GetGlobalParams(names, height)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get change records of global param, including the scheduled ones
+// A JSON example for getparamhistory method as following:
+//   {"jsonrpc": "2.0", "method": "getparamhistory", "params": ["gasPrice"], "id": 0}
+func GetParamHistory(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	name, ok := params[0].(string)
+	if !ok || name == "" {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetParamHistory(name)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get type and range of global param
+// A JSON example for getparamspec method as following:
+//   {"jsonrpc": "2.0", "method": "getparamspec", "params": ["gasPrice"], "id": 0}
+func GetParamSpec(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	name, ok := params[0].(string)
+	if !ok || name == "" {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetParamSpec(name)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
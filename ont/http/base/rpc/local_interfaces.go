@@ -26,6 +26,7 @@ import (
 	bactor "github.com/ontio/ontology/http/base/actor"
 	"github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
+	p2pcomm "github.com/ontio/ontology/p2pserver/common"
 )
 
 const (
@@ -79,6 +80,21 @@ func GetNodeState(params []interface{}) map[string]interface{} {
 	if err != nil {
 		return responsePack(berr.INTERNAL_ERROR, false)
 	}
+	peerStats, err := bactor.GetNeighborMsgStats()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	msgStats := make(map[string]*p2pcomm.MsgStat)
+	for _, ps := range peerStats {
+		for cmdType, stat := range ps.Stats {
+			if s, ok := msgStats[cmdType]; ok {
+				s.Add(stat)
+			} else {
+				s := *stat
+				msgStats[cmdType] = &s
+			}
+		}
+	}
 	n := common.NodeInfo{
 		NodeState:   uint(state),
 		NodeTime:    t,
@@ -89,6 +105,8 @@ func GetNodeState(params []interface{}) map[string]interface{} {
 		Relay:       relay,
 		Height:      height,
 		TxnCnt:      txnCnt,
+		MsgStats:    msgStats,
+		PeerStats:   peerStats,
 	}
 	return responseSuccess(n)
 }
@@ -107,6 +125,23 @@ func StopConsensus(params []interface{}) map[string]interface{} {
 	return responsePack(berr.SUCCESS, true)
 }
 
+//get consensus timeline and peer statistics of the last N blocks, all kept blocks if N absent
+func GetConsensusMetrics(params []interface{}) map[string]interface{} {
+	var count uint32
+	if len(params) > 0 {
+		n, ok := params[0].(float64)
+		if !ok || n < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		count = uint32(n)
+	}
+	metrics, err := bactor.GetConsensusMetrics(count)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(metrics)
+}
+
 func SetDebugInfo(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, "")
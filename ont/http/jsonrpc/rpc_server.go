@@ -47,6 +47,8 @@ func StartRPCServer() error {
 	rpc.HandleFunc("getnetworkid", rpc.GetNetworkId)
 
 	rpc.HandleFunc("getcontractstate", rpc.GetContractState)
+	rpc.HandleFunc("getcontracthistory", rpc.GetContractHistory)
+	rpc.HandleFunc("getcontractabi", rpc.GetContractAbi)
 	rpc.HandleFunc("getmempooltxcount", rpc.GetMemPoolTxCount)
 	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
 	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
@@ -55,10 +57,23 @@ func StartRPCServer() error {
 	rpc.HandleFunc("getbalance", rpc.GetBalance)
 	rpc.HandleFunc("getallowance", rpc.GetAllowance)
 	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
+	rpc.HandleFunc("getverifiedtransaction", rpc.GetVerifiedTransaction)
 	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
 	rpc.HandleFunc("getgasprice", rpc.GetGasPrice)
 	rpc.HandleFunc("getunboundong", rpc.GetUnboundOng)
 	rpc.HandleFunc("getgrantong", rpc.GetGrantOng)
+	rpc.HandleFunc("getdiddocument", rpc.GetDIDDocument)
+	rpc.HandleFunc("getcredentialstatus", rpc.GetCredentialStatus)
+	rpc.HandleFunc("getpeerpool", rpc.GetPeerPool)
+	rpc.HandleFunc("getauthorizeinfo", rpc.GetAuthorizeInfo)
+	rpc.HandleFunc("getwithdrawable", rpc.GetWithdrawable)
+	rpc.HandleFunc("getsplitfeehistory", rpc.GetSplitFeeHistory)
+	rpc.HandleFunc("getcontractroles", rpc.GetContractRoles)
+	rpc.HandleFunc("getrolefuncs", rpc.GetRoleFuncs)
+	rpc.HandleFunc("getroleholders", rpc.GetRoleHolders)
+	rpc.HandleFunc("getglobalparams", rpc.GetGlobalParams)
+	rpc.HandleFunc("getparamhistory", rpc.GetParamHistory)
+	rpc.HandleFunc("getparamspec", rpc.GetParamSpec)
 
 	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), nil)
 	if err != nil {
@@ -42,6 +42,7 @@ func StartLocalServer() error {
 	rpc.HandleFunc("getnodestate", rpc.GetNodeState)
 	rpc.HandleFunc("startconsensus", rpc.StartConsensus)
 	rpc.HandleFunc("stopconsensus", rpc.StopConsensus)
+	rpc.HandleFunc("getconsensusmetrics", rpc.GetConsensusMetrics)
 	rpc.HandleFunc("setdebuginfo", rpc.SetDebugInfo)
 
 	// TODO: only listen to local host
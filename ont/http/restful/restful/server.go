@@ -73,6 +73,12 @@ const (
 	GET_MEMPOOL_TXSTATE   = "/api/v1/mempool/txstate/:hash"
 	GET_VERSION           = "/api/v1/version"
 	GET_NETWORKID         = "/api/v1/networkid"
+	GET_DID_DOCUMENT      = "/api/v1/diddocument/:did"
+	GET_CREDENTIAL_STATUS = "/api/v1/credential/status/:issuer/:hash"
+	GET_PEER_POOL         = "/api/v1/governance/peerpool"
+	GET_AUTHORIZE_INFO    = "/api/v1/governance/authorizeinfo/:addr"
+	GET_WITHDRAWABLE      = "/api/v1/governance/withdrawable/:addr"
+	GET_SPLIT_FEE_HISTORY = "/api/v1/governance/splitfee/:view"
 
 	POST_RAW_TX = "/api/v1/transaction"
 )
@@ -149,6 +155,12 @@ func (this *restServer) registryMethod() {
 		GET_MEMPOOL_TXSTATE:   {name: "getmempooltxstate", handler: rest.GetMemPoolTxState},
 		GET_VERSION:           {name: "getversion", handler: rest.GetNodeVersion},
 		GET_NETWORKID:         {name: "getnetworkid", handler: rest.GetNetworkId},
+		GET_DID_DOCUMENT:      {name: "getdiddocument", handler: rest.GetDIDDocument},
+		GET_CREDENTIAL_STATUS: {name: "getcredentialstatus", handler: rest.GetCredentialStatus},
+		GET_PEER_POOL:         {name: "getpeerpool", handler: rest.GetPeerPool},
+		GET_AUTHORIZE_INFO:    {name: "getauthorizeinfo", handler: rest.GetAuthorizeInfo},
+		GET_WITHDRAWABLE:      {name: "getwithdrawable", handler: rest.GetWithdrawable},
+		GET_SPLIT_FEE_HISTORY: {name: "getsplitfeehistory", handler: rest.GetSplitFeeHistory},
 	}
 
 	postMethodMap := map[string]Action{
@@ -191,6 +203,16 @@ func (this *restServer) getPath(url string) string {
 		return GET_GRANTONG
 	} else if strings.Contains(url, strings.TrimRight(GET_MEMPOOL_TXSTATE, ":hash")) {
 		return GET_MEMPOOL_TXSTATE
+	} else if strings.Contains(url, strings.TrimRight(GET_DID_DOCUMENT, ":did")) {
+		return GET_DID_DOCUMENT
+	} else if strings.Contains(url, strings.TrimRight(GET_CREDENTIAL_STATUS, ":issuer/:hash")) {
+		return GET_CREDENTIAL_STATUS
+	} else if strings.Contains(url, strings.TrimRight(GET_AUTHORIZE_INFO, ":addr")) {
+		return GET_AUTHORIZE_INFO
+	} else if strings.Contains(url, strings.TrimRight(GET_WITHDRAWABLE, ":addr")) {
+		return GET_WITHDRAWABLE
+	} else if strings.Contains(url, strings.TrimRight(GET_SPLIT_FEE_HISTORY, ":view")) {
+		return GET_SPLIT_FEE_HISTORY
 	}
 	return url
 }
@@ -213,13 +235,14 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 	case GET_CONTRACT_STATE:
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
 	case POST_RAW_TX:
-		req["PreExec"] = r.FormValue("preExec")
+		req["PreExec"], req["Profile"] = r.FormValue("preExec"), r.FormValue("profile")
+		req["Decode"] = r.FormValue("decode")
 	case GET_STORAGE:
 		req["Hash"], req["Key"] = getParam(r, "hash"), getParam(r, "key")
 	case GET_SMTCOCE_EVT_TXS:
-		req["Height"] = getParam(r, "height")
+		req["Height"], req["Decode"] = getParam(r, "height"), r.FormValue("decode")
 	case GET_SMTCOCE_EVTS:
-		req["Hash"] = getParam(r, "hash")
+		req["Hash"], req["Decode"] = getParam(r, "hash"), r.FormValue("decode")
 	case GET_BLK_HGT_BY_TXHASH:
 		req["Hash"] = getParam(r, "hash")
 	case GET_BALANCE:
@@ -235,6 +258,16 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Addr"] = getParam(r, "addr")
 	case GET_MEMPOOL_TXSTATE:
 		req["Hash"] = getParam(r, "hash")
+	case GET_DID_DOCUMENT:
+		req["Did"], req["Height"] = getParam(r, "did"), r.FormValue("height")
+	case GET_CREDENTIAL_STATUS:
+		req["Issuer"], req["Hash"] = getParam(r, "issuer"), getParam(r, "hash")
+	case GET_AUTHORIZE_INFO:
+		req["Addr"] = getParam(r, "addr")
+	case GET_WITHDRAWABLE:
+		req["Addr"] = getParam(r, "addr")
+	case GET_SPLIT_FEE_HISTORY:
+		req["View"], req["End"] = getParam(r, "view"), r.FormValue("end")
 	default:
 	}
 	return req
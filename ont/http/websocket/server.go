@@ -80,24 +80,37 @@ func pushSmartCodeEvent(v interface{}) {
 		switch object := rs.Result.(type) {
 		case *event.LogEventArgs:
 			contractAddrs, evts := bcomn.GetLogEvent(object)
-			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Error, rs.Action, evts)
+			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Error, rs.Action, evts, nil)
 		case *event.ExecuteNotify:
 			contractAddrs, notify := bcomn.GetExecuteNotify(object)
-			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Error, rs.Action, notify)
+			var decoded interface{}
+			if ws.NeedDecodedEvent(contractAddrs, rs.TxHash.ToHexString()) {
+				decoded = bcomn.DecodeExecuteNotify(notify)
+			}
+			pushEvent(contractAddrs, rs.TxHash.ToHexString(), rs.Error, rs.Action, notify, decoded)
 		default:
 		}
 	}()
 }
 
-func pushEvent(contractAddrs map[string]bool, txHash string, errcode int64, action string, result interface{}) {
+//pushEvent push result to the sessions, decoded is the result decoded by ABI, nil if not available
+func pushEvent(contractAddrs map[string]bool, txHash string, errcode int64, action string, result, decoded interface{}) {
 	if ws != nil {
 		resp := rest.ResponsePack(Err.SUCCESS)
 		resp["Result"] = result
 		resp["Error"] = errcode
 		resp["Action"] = action
 		resp["Desc"] = Err.ErrMap[resp["Error"].(int64)]
-		ws.PushTxResult(contractAddrs, txHash, resp)
-		ws.BroadcastToSubscribers(contractAddrs, websocket.WSTOPIC_EVENT, resp)
+		var decodedResp map[string]interface{}
+		if decoded != nil {
+			decodedResp = make(map[string]interface{}, len(resp))
+			for k, v := range resp {
+				decodedResp[k] = v
+			}
+			decodedResp["Result"] = decoded
+		}
+		ws.PushTxResult(contractAddrs, txHash, resp, decodedResp)
+		ws.BroadcastToSubscribers(contractAddrs, websocket.WSTOPIC_EVENT, resp, decodedResp)
 	}
 }
 
@@ -111,11 +124,11 @@ func pushBlock(v interface{}) {
 		w := bytes.NewBuffer(nil)
 		block.Serialize(w)
 		resp["Result"] = common.ToHexString(w.Bytes())
-		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_RAW_BLOCK, resp)
+		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_RAW_BLOCK, resp, nil)
 
 		resp["Action"] = "sendjsonblock"
 		resp["Result"] = bcomn.GetBlockInfo(&block)
-		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_JSON_BLOCK, resp)
+		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_JSON_BLOCK, resp, nil)
 	}
 }
 func pushBlockTransactions(v interface{}) {
@@ -126,6 +139,6 @@ func pushBlockTransactions(v interface{}) {
 	if block, ok := v.(types.Block); ok {
 		resp["Result"] = bcomn.GetBlockTransactions(&block)
 		resp["Action"] = "sendblocktxhashs"
-		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_TXHASHS, resp)
+		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_TXHASHS, resp, nil)
 	}
 }
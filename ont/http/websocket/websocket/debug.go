@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	bactor "github.com/ontio/ontology/http/base/actor"
+	bcomn "github.com/ontio/ontology/http/base/common"
+	Err "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/http/base/rest"
+	"github.com/ontio/ontology/smartcontract/debugger"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+)
+
+var debugModes = map[string]debugger.Mode{
+	"step":     debugger.MODE_STEP,
+	"next":     debugger.MODE_NEXT,
+	"continue": debugger.MODE_CONTINUE,
+}
+
+//start a debug session of the invoke transaction, the execution pause before the first instruction
+func (self *WsServer) debugStart(cmd map[string]interface{}) map[string]interface{} {
+	str, ok := cmd["Data"].(string)
+	if !ok {
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
+	bys, err := common.HexToBytes(str)
+	if err != nil {
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
+	txn, err := types.TransactionFromRawBytes(bys)
+	if err != nil || txn.TxType != types.Invoke {
+		return rest.ResponsePack(Err.INVALID_TRANSACTION)
+	}
+	sessionId, _ := cmd["SessionId"].(string)
+	self.quitDebug(sessionId)
+
+	session := debugger.NewSession()
+	state := session.Start(func(debugger neovm.Debugger) (interface{}, error) {
+		result, err := bactor.PreExecuteContractWithDebugger(txn, debugger)
+		if err != nil {
+			return nil, err
+		}
+		return bcomn.ConvertPreExecuteResult(result), nil
+	})
+	self.Lock()
+	self.DebugMap[sessionId] = session
+	self.Unlock()
+
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Result"] = state
+	return resp
+}
+
+//resume the paused execution with Mode step, next or continue
+func (self *WsServer) debugResume(cmd map[string]interface{}) map[string]interface{} {
+	name, _ := cmd["Mode"].(string)
+	mode, ok := debugModes[name]
+	if !ok {
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
+	session := self.getDebug(cmd)
+	if session == nil {
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
+	state, err := session.Resume(mode)
+	if err != nil {
+		resp := rest.ResponsePack(Err.SMARTCODE_ERROR)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Result"] = state
+	return resp
+}
+
+//add or remove a breakpoint on Offset of Contract or on Syscall, return all breakpoints
+func (self *WsServer) debugBreakpoint(cmd map[string]interface{}) map[string]interface{} {
+	session := self.getDebug(cmd)
+	if session == nil {
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
+	var bp debugger.Breakpoint
+	offset, hasOffset := cmd["Offset"].(float64)
+	bp.Syscall, _ = cmd["Syscall"].(string)
+	if hasOffset || bp.Syscall != "" {
+		if hasOffset {
+			if offset < 0 {
+				return rest.ResponsePack(Err.INVALID_PARAMS)
+			}
+			bp.Offset = int(offset)
+		}
+		if contract, ok := cmd["Contract"].(string); ok && contract != "" {
+			addr, err := common.AddressFromHexString(contract)
+			if err != nil {
+				return rest.ResponsePack(Err.INVALID_PARAMS)
+			}
+			bp.Contract = addr
+		}
+		if remove, _ := cmd["Remove"].(bool); remove {
+			session.RemoveBreakpoint(bp)
+		} else {
+			session.AddBreakpoint(bp)
+		}
+	}
+	var bps []string
+	for _, bp := range session.Breakpoints() {
+		bps = append(bps, bp.String())
+	}
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Result"] = bps
+	return resp
+}
+
+//return the latest state of debug session
+func (self *WsServer) debugState(cmd map[string]interface{}) map[string]interface{} {
+	session := self.getDebug(cmd)
+	if session == nil {
+		return rest.ResponsePack(Err.INVALID_PARAMS)
+	}
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Result"] = session.State()
+	return resp
+}
+
+//abort the debug session
+func (self *WsServer) debugQuit(cmd map[string]interface{}) map[string]interface{} {
+	sessionId, _ := cmd["SessionId"].(string)
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Result"] = self.quitDebug(sessionId)
+	return resp
+}
+
+func (self *WsServer) getDebug(cmd map[string]interface{}) *debugger.Session {
+	sessionId, _ := cmd["SessionId"].(string)
+	self.RLock()
+	defer self.RUnlock()
+	return self.DebugMap[sessionId]
+}
+
+func (self *WsServer) quitDebug(sessionId string) *debugger.State {
+	self.Lock()
+	session := self.DebugMap[sessionId]
+	delete(self.DebugMap, sessionId)
+	self.Unlock()
+	if session == nil {
+		return nil
+	}
+	return session.Quit()
+}
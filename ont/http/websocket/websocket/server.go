@@ -36,6 +36,7 @@ import (
 	Err "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/http/base/rest"
 	"github.com/ontio/ontology/http/websocket/session"
+	"github.com/ontio/ontology/smartcontract/debugger"
 )
 
 const (
@@ -58,16 +59,18 @@ type subscribe struct {
 	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
 	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
 	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+	DecodeEvent           bool     `json:"DecodeEvent"`
 }
 type WsServer struct {
 	sync.RWMutex
 	Upgrader     websocket.Upgrader
 	listener     net.Listener
 	server       *http.Server
-	SessionList  *session.SessionList // websocket sesseionlist
-	ActionMap    map[string]Handler   //handler functions
-	TxHashMap    map[string]string    //key: txHash   value:sessionid
-	SubscribeMap map[string]subscribe //key: sessionId   value:subscribeInfo
+	SessionList  *session.SessionList         // websocket sesseionlist
+	ActionMap    map[string]Handler           //handler functions
+	TxHashMap    map[string]string            //key: txHash   value:sessionid
+	SubscribeMap map[string]subscribe         //key: sessionId   value:subscribeInfo
+	DebugMap     map[string]*debugger.Session //key: sessionId   value:debug session
 }
 
 //init websocket server
@@ -77,6 +80,7 @@ func InitWsServer() *WsServer {
 		SessionList:  session.NewSessionList(),
 		TxHashMap:    make(map[string]string),
 		SubscribeMap: make(map[string]subscribe),
+		DebugMap:     make(map[string]*debugger.Session),
 	}
 	return ws
 }
@@ -157,6 +161,9 @@ func (self *WsServer) registryMethod() {
 		if b, ok := cmd["SubscribeBlockTxHashs"].(bool); ok {
 			sub.SubscribeBlockTxHashs = b
 		}
+		if b, ok := cmd["DecodeEvent"].(bool); ok {
+			sub.DecodeEvent = b
+		}
 		if ctsf, ok := cmd["ConstractsFilter"].([]interface{}); ok {
 			sub.ConstractsFilter = []string{}
 			for _, v := range ctsf {
@@ -206,6 +213,13 @@ func (self *WsServer) registryMethod() {
 
 		"getsessioncount": {handler: getsessioncount},
 	}
+	if cfg.DefConfig.Ws.EnableDebug {
+		actionMap["debugstart"] = Handler{handler: self.debugStart}
+		actionMap["debugresume"] = Handler{handler: self.debugResume}
+		actionMap["debugbreakpoint"] = Handler{handler: self.debugBreakpoint}
+		actionMap["debugstate"] = Handler{handler: self.debugState}
+		actionMap["debugquit"] = Handler{handler: self.debugQuit}
+	}
 	self.ActionMap = actionMap
 }
 
@@ -267,6 +281,7 @@ func (self *WsServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 	defer func() {
 		self.deleteTxHashes(nsSession.GetSessionId())
 		self.deleteSubscribe(nsSession.GetSessionId())
+		self.quitDebug(nsSession.GetSessionId())
 		self.SessionList.CloseSession(nsSession)
 		if err := recover(); err != nil {
 			log.Fatal("websocket recover:", err)
@@ -388,7 +403,33 @@ func marshalResp(resp map[string]interface{}) []byte {
 	return data
 }
 
-func (self *WsServer) PushTxResult(contractAddrs map[string]bool, txHashStr string, resp map[string]interface{}) {
+//NeedDecodedEvent return whether the session sending the transaction or any session receiving the event
+//of contracts subscribes with DecodeEvent, so the event is decoded by ABI only when needed
+func (self *WsServer) NeedDecodedEvent(contractAddrs map[string]bool, txHashStr string) bool {
+	self.RLock()
+	defer self.RUnlock()
+	if sessionId, ok := self.TxHashMap[txHashStr]; ok && self.SubscribeMap[sessionId].DecodeEvent {
+		return true
+	}
+	for _, v := range self.SubscribeMap {
+		if !v.SubscribeEvent || !v.DecodeEvent {
+			continue
+		}
+		if len(v.ConstractsFilter) == 0 {
+			return true
+		}
+		for _, addr := range v.ConstractsFilter {
+			if contractAddrs[addr] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//PushTxResult push the event of transaction to the session sending it. decodedResp is the event decoded by ABI,
+//sent instead of resp if the session subscribes with DecodeEvent, nil if not available
+func (self *WsServer) PushTxResult(contractAddrs map[string]bool, txHashStr string, resp, decodedResp map[string]interface{}) {
 	self.Lock()
 	sessionId := self.TxHashMap[txHashStr]
 	delete(self.TxHashMap, txHashStr)
@@ -410,14 +451,24 @@ func (self *WsServer) PushTxResult(contractAddrs map[string]bool, txHashStr stri
 
 	s := self.SessionList.GetSessionById(sessionId)
 	if s != nil {
+		if sub.DecodeEvent && decodedResp != nil {
+			resp = decodedResp
+		}
 		s.Send(marshalResp(resp))
 	}
 }
-func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub int, resp map[string]interface{}) {
+
+//BroadcastToSubscribers send resp to the sessions subscribing topic sub. decodedResp is the event decoded by ABI,
+//sent instead of resp to the sessions subscribing with DecodeEvent, nil if not available
+func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub int, resp, decodedResp map[string]interface{}) {
 	// broadcast SubscribeMap
 	self.Lock()
 	defer self.Unlock()
 	data := marshalResp(resp)
+	decodedData := data
+	if decodedResp != nil {
+		decodedData = marshalResp(decodedResp)
+	}
 	for sid, v := range self.SubscribeMap {
 		s := self.SessionList.GetSessionById(sid)
 		if s == nil {
@@ -430,13 +481,17 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 		} else if sub == WSTOPIC_TXHASHS && v.SubscribeBlockTxHashs {
 			s.Send(data)
 		} else if sub == WSTOPIC_EVENT && v.SubscribeEvent {
+			evtData := data
+			if v.DecodeEvent {
+				evtData = decodedData
+			}
 			if len(v.ConstractsFilter) == 0 {
-				s.Send(data)
+				s.Send(evtData)
 				continue
 			}
 			for _, addr := range v.ConstractsFilter {
 				if contractAddrs[addr] {
-					s.Send(data)
+					s.Send(evtData)
 					break
 				}
 			}
@@ -84,6 +84,10 @@ func (this *P2PActor) Receive(ctx actor.Context) {
 		this.handleGetRelayStateReq(ctx, msg)
 	case *GetNodeTypeReq:
 		this.handleGetNodeTypeReq(ctx, msg)
+	case *GetNeighborMsgStatsReq:
+		this.handleGetNeighborMsgStatsReq(ctx, msg)
+	case *GetTxProofReq:
+		this.handleGetTxProofReq(ctx, msg)
 	case *TransmitConsensusMsgReq:
 		this.handleTransmitConsensusMsgReq(ctx, msg)
 	case *common.AppendPeerID:
@@ -94,6 +98,8 @@ func (this *P2PActor) Receive(ctx actor.Context) {
 		this.server.OnHeaderReceive(msg.FromID, msg.Headers)
 	case *common.AppendBlock:
 		this.server.OnBlockReceive(msg.FromID, msg.BlockSize, msg.Block)
+	case *common.AppendTxProof:
+		this.server.OnTxProofReceive(msg)
 	default:
 		err := this.server.Xmit(ctx.Message())
 		if nil != err {
@@ -235,6 +241,32 @@ func (this *P2PActor) handleGetNodeTypeReq(ctx actor.Context, req *GetNodeTypeRe
 	}
 }
 
+//nbr peer`s msg statistics handler
+func (this *P2PActor) handleGetNeighborMsgStatsReq(ctx actor.Context, req *GetNeighborMsgStatsReq) {
+	stats := this.server.GetNeighborMsgStats()
+	if ctx.Sender() != nil {
+		resp := &GetNeighborMsgStatsRsp{
+			Stats: stats,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
+
+//tx proof handler, waits for peers response out of the actor goroutine
+func (this *P2PActor) handleGetTxProofReq(ctx actor.Context, req *GetTxProofReq) {
+	sender, self := ctx.Sender(), ctx.Self()
+	go func() {
+		proof, err := this.server.GetVerifiedTx(req.TxHash)
+		if sender != nil {
+			resp := &GetTxProofRsp{
+				Proof: proof,
+				Error: err,
+			}
+			sender.Request(resp, self)
+		}
+	}()
+}
+
 func (this *P2PActor) handleTransmitConsensusMsgReq(ctx actor.Context, req *TransmitConsensusMsgReq) {
 	peer := this.server.GetNetWork().GetPeer(req.Target)
 	if peer != nil {
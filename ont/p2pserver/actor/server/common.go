@@ -19,6 +19,7 @@
 package server
 
 import (
+	"github.com/ontio/ontology/common"
 	types "github.com/ontio/ontology/p2pserver/common"
 	ptypes "github.com/ontio/ontology/p2pserver/message/types"
 )
@@ -128,6 +129,26 @@ type GetNeighborAddrsRsp struct {
 	Addrs []types.PeerAddr
 }
 
+//get msg statistics of all nbr peers request
+type GetNeighborMsgStatsReq struct {
+}
+
+//response of msg statistics of all nbr peers
+type GetNeighborMsgStatsRsp struct {
+	Stats []*types.PeerMsgStats
+}
+
+//get tx with verified merkle proof from nbr peers request
+type GetTxProofReq struct {
+	TxHash common.Uint256
+}
+
+//response of tx with verified merkle proof
+type GetTxProofRsp struct {
+	Proof *types.AppendTxProof
+	Error error
+}
+
 type TransmitConsensusMsgReq struct {
 	Target uint64
 	Msg    ptypes.Message
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/types"
@@ -339,6 +340,10 @@ func (this *BlockSyncMgr) checkTimeout() {
 
 func (this *BlockSyncMgr) sync() {
 	this.syncHeader()
+	//light client only syncs header
+	if config.DefConfig.Common.EnableLightClient {
+		return
+	}
 	this.syncBlock()
 }
 
@@ -358,7 +363,7 @@ func (this *BlockSyncMgr) syncHeader() {
 
 	curHeaderHeight := this.ledger.GetCurrentHeaderHeight()
 	//Waiting for block catch up header
-	if curHeaderHeight-curBlockHeight >= SYNC_MAX_HEADER_FORWARD_SIZE {
+	if !config.DefConfig.Common.EnableLightClient && curHeaderHeight-curBlockHeight >= SYNC_MAX_HEADER_FORWARD_SIZE {
 		return
 	}
 	NextHeaderId := curHeaderHeight + 1
@@ -483,6 +488,9 @@ func (this *BlockSyncMgr) OnHeaderReceive(fromID uint64, headers []*types.Header
 
 // OnBlockReceive receive block from net
 func (this *BlockSyncMgr) OnBlockReceive(fromID uint64, blockSize uint32, block *types.Block) {
+	if config.DefConfig.Common.EnableLightClient {
+		return
+	}
 	height := block.Header.Height
 	blockHash := block.Hash()
 	log.Trace("[p2p]OnBlockReceive Height:%d", height)
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	comm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/core/types"
 )
 
@@ -53,6 +54,9 @@ const (
 	MAX_REQ_BLK_ONCE = 16               //req blk count once from one peer when sync blk
 	MAX_MSG_LEN      = 30 * 1024 * 1024 //the maximum message length
 	MAX_PAYLOAD_LEN  = MAX_MSG_LEN - MSG_HDR_LEN
+	MIN_COMPRESS_LEN = 1024            //payload shorter than this is sent uncompressed
+	COMPRESS_OFFSET  = MSG_CMD_LEN - 1 //compress mark offset in msg hdr cmd field
+	COMPRESS_MARK    = 0x01            //mark of compressed payload
 )
 
 //msg type const
@@ -87,6 +91,7 @@ const (
 //cap flag
 const (
 	HTTP_INFO_FLAG = 0 //peer`s http info bit in cap field
+	COMPRESS_FLAG  = 1 //peer`s msg compress bit in cap field
 )
 
 //actor const
@@ -94,6 +99,12 @@ const (
 	ACTOR_TIMEOUT = 5 //actor request timeout in secs
 )
 
+//light client const
+const (
+	TX_PROOF_REQ_PEERS = 3 //peers count to request tx proof from
+	TX_PROOF_TIMEOUT   = 8 //timeout in secs waiting for tx proof
+)
+
 //recent contact const
 const (
 	RECENT_TIMEOUT   = 60
@@ -113,24 +124,50 @@ type PeerAddr struct {
 
 //const channel msg id and type
 const (
-	VERSION_TYPE     = "version"    //peer`s information
-	VERACK_TYPE      = "verack"     //ack msg after version recv
-	GetADDR_TYPE     = "getaddr"    //req nbr address from peer
-	ADDR_TYPE        = "addr"       //nbr address
-	PING_TYPE        = "ping"       //ping  sync height
-	PONG_TYPE        = "pong"       //pong  recv nbr height
-	GET_HEADERS_TYPE = "getheaders" //req blk hdr
-	HEADERS_TYPE     = "headers"    //blk hdr
-	INV_TYPE         = "inv"        //inv payload
-	GET_DATA_TYPE    = "getdata"    //req data from peer
-	BLOCK_TYPE       = "block"      //blk payload
-	TX_TYPE          = "tx"         //transaction
-	CONSENSUS_TYPE   = "consensus"  //consensus payload
-	GET_BLOCKS_TYPE  = "getblocks"  //req blks from peer
-	NOT_FOUND_TYPE   = "notfound"   //peer can`t find blk according to the hash
-	DISCONNECT_TYPE  = "disconnect" //peer disconnect info raise by link
+	VERSION_TYPE      = "version"    //peer`s information
+	VERACK_TYPE       = "verack"     //ack msg after version recv
+	GetADDR_TYPE      = "getaddr"    //req nbr address from peer
+	ADDR_TYPE         = "addr"       //nbr address
+	PING_TYPE         = "ping"       //ping  sync height
+	PONG_TYPE         = "pong"       //pong  recv nbr height
+	GET_HEADERS_TYPE  = "getheaders" //req blk hdr
+	HEADERS_TYPE      = "headers"    //blk hdr
+	INV_TYPE          = "inv"        //inv payload
+	GET_DATA_TYPE     = "getdata"    //req data from peer
+	BLOCK_TYPE        = "block"      //blk payload
+	TX_TYPE           = "tx"         //transaction
+	CONSENSUS_TYPE    = "consensus"  //consensus payload
+	GET_BLOCKS_TYPE   = "getblocks"  //req blks from peer
+	NOT_FOUND_TYPE    = "notfound"   //peer can`t find blk according to the hash
+	DISCONNECT_TYPE   = "disconnect" //peer disconnect info raise by link
+	GET_TX_PROOF_TYPE = "gettxproof" //req tx with merkle proof
+	TX_PROOF_TYPE     = "txproof"    //tx with merkle proof
 )
 
+//MsgStat count the messages and bytes of one msg type
+type MsgStat struct {
+	RxCount uint64 //received msg count
+	RxBytes uint64 //received bytes on the wire
+	TxCount uint64 //sent msg count
+	TxBytes uint64 //sent bytes on the wire
+}
+
+//Add accumulate the counters of other into stat
+func (this *MsgStat) Add(other *MsgStat) {
+	this.RxCount += other.RxCount
+	this.RxBytes += other.RxBytes
+	this.TxCount += other.TxCount
+	this.TxBytes += other.TxBytes
+}
+
+//PeerMsgStats represent the msg statistics of a nbr peer by msg type
+type PeerMsgStats struct {
+	ID       uint64              //peer id
+	Addr     string              //peer sync link address
+	Compress bool                //whether msg compress is negotiated
+	Stats    map[string]*MsgStat //msg type to msg statistics
+}
+
 type AppendPeerID struct {
 	ID uint64 // The peer id
 }
@@ -150,6 +187,15 @@ type AppendBlock struct {
 	Block     *types.Block // Block to be added to the ledger
 }
 
+type AppendTxProof struct {
+	FromID uint64             // The peer id
+	Height uint32             // Height of the block containing the tx
+	Index  uint32             // Index of the tx in the block
+	Proof  []comm.Uint256     // Merkle proof of the tx to TransactionsRoot
+	Tx     *types.Transaction // The proven transaction
+	Notify []byte             // Json encoded execute notify, not proven
+}
+
 //ParseIPAddr return ip address
 func ParseIPAddr(s string) (string, error) {
 	i := strings.Index(s, ":")
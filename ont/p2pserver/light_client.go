@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	comm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/message/msg_pack"
+	"github.com/ontio/ontology/smartcontract/event"
+)
+
+//LightClient fetch transactions with merkle proof from full nodes, and verify
+//them against the headers synced by BlockSyncMgr
+type LightClient struct {
+	server  *P2PServer
+	lock    sync.Mutex
+	pending map[comm.Uint256][]chan *common.AppendTxProof //tx hash => waiting requests
+}
+
+//NewLightClient return a LightClient instance
+func NewLightClient(server *P2PServer) *LightClient {
+	return &LightClient{
+		server:  server,
+		pending: make(map[comm.Uint256][]chan *common.AppendTxProof),
+	}
+}
+
+//GetVerifiedTx request the tx from several peers, and return the first tx proof
+//which is verified by the local header
+func (this *LightClient) GetVerifiedTx(txHash comm.Uint256) (*common.AppendTxProof, error) {
+	peers := this.server.network.GetNeighbors()
+	if len(peers) == 0 {
+		return nil, errors.New("no neighbor peers")
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].GetHeight() > peers[j].GetHeight()
+	})
+	if len(peers) > common.TX_PROOF_REQ_PEERS {
+		peers = peers[:common.TX_PROOF_REQ_PEERS]
+	}
+
+	ch := make(chan *common.AppendTxProof, len(peers))
+	this.addPending(txHash, ch)
+	defer this.delPending(txHash, ch)
+
+	msg := msgpack.NewTxProofReq(txHash)
+	for _, p := range peers {
+		err := this.server.Send(p, msg, false)
+		if err != nil {
+			log.Warnf("[p2p]send tx proof request to %s error:%s", p.GetAddr(), err)
+		}
+	}
+
+	timer := time.NewTimer(common.TX_PROOF_TIMEOUT * time.Second)
+	defer timer.Stop()
+	lastErr := fmt.Errorf("no tx proof received in %ds", common.TX_PROOF_TIMEOUT)
+	for i := 0; i < len(peers); i++ {
+		select {
+		case proof := <-ch:
+			err := this.verifyTxProof(proof)
+			if err == nil {
+				return proof, nil
+			}
+			log.Warnf("[p2p]invalid tx proof from peer %d:%s", proof.FromID, err)
+			lastErr = err
+		case <-timer.C:
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+//OnTxProofReceive dispatch the tx proof to the waiting requests
+func (this *LightClient) OnTxProofReceive(proof *common.AppendTxProof) {
+	if proof.Tx == nil {
+		return
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, ch := range this.pending[proof.Tx.Hash()] {
+		select {
+		case ch <- proof:
+		default:
+		}
+	}
+}
+
+//verifyTxProof check the tx is included in the TransactionsRoot of the synced header.
+//Events are not committed in header, so notify is only checked to belong to the tx
+func (this *LightClient) verifyTxProof(proof *common.AppendTxProof) error {
+	header, err := this.server.ledger.GetHeaderByHeight(proof.Height)
+	if err != nil {
+		return fmt.Errorf("get header of height %d error:%s", proof.Height, err)
+	}
+	txHash := proof.Tx.Hash()
+	if !comm.VerifyMerkleProof(txHash, proof.Index, proof.Proof, header.TransactionsRoot) {
+		return errors.New("tx merkle proof verify failed")
+	}
+	if len(proof.Notify) > 0 {
+		notify := &event.ExecuteNotify{}
+		err = json.Unmarshal(proof.Notify, notify)
+		if err != nil {
+			return fmt.Errorf("unmarshal notify error:%s", err)
+		}
+		if notify.TxHash != txHash {
+			return errors.New("notify not match tx")
+		}
+	}
+	return nil
+}
+
+func (this *LightClient) addPending(txHash comm.Uint256, ch chan *common.AppendTxProof) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.pending[txHash] = append(this.pending[txHash], ch)
+}
+
+func (this *LightClient) delPending(txHash comm.Uint256, ch chan *common.AppendTxProof) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	chs := this.pending[txHash]
+	for i, c := range chs {
+		if c == ch {
+			chs = append(chs[:i], chs[i+1:]...)
+			break
+		}
+	}
+	if len(chs) == 0 {
+		delete(this.pending, txHash)
+	} else {
+		this.pending[txHash] = chs
+	}
+}
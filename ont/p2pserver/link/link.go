@@ -22,7 +22,10 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	comm "github.com/ontio/ontology/common"
@@ -40,11 +43,27 @@ type Link struct {
 	time      time.Time              // The latest time the node activity
 	recvChan  chan *types.MsgPayload //msgpayload channel
 	reqRecord map[string]int64       //Map RequestId to Timestamp, using for rejecting duplicate request in specific time
+	compress  uint32                 //whether compress large msg when send, accessed atomically
+	statLock  sync.Mutex
+	msgStats  map[string]*common.MsgStat //msg statistics by msg type
+}
+
+//countReader count the bytes read from the underlying reader
+type countReader struct {
+	reader io.Reader
+	count  uint64
+}
+
+func (this *countReader) Read(p []byte) (int, error) {
+	n, err := this.reader.Read(p)
+	this.count += uint64(n)
+	return n, err
 }
 
 func NewLink() *Link {
 	link := &Link{
 		reqRecord: make(map[string]int64, 0),
+		msgStats:  make(map[string]*common.MsgStat),
 	}
 	return link
 }
@@ -109,20 +128,76 @@ func (this *Link) GetRXTime() time.Time {
 	return this.time
 }
 
+//SetCompress set whether compress large msg when send
+func (this *Link) SetCompress(compress bool) {
+	var value uint32
+	if compress {
+		value = 1
+	}
+	atomic.StoreUint32(&this.compress, value)
+}
+
+//GetCompress return whether compress large msg when send
+func (this *Link) GetCompress() bool {
+	return atomic.LoadUint32(&this.compress) == 1
+}
+
+//GetMsgStats return a copy of the msg statistics of link
+func (this *Link) GetMsgStats() map[string]*common.MsgStat {
+	this.statLock.Lock()
+	defer this.statLock.Unlock()
+	stats := make(map[string]*common.MsgStat, len(this.msgStats))
+	for cmdType, stat := range this.msgStats {
+		s := *stat
+		stats[cmdType] = &s
+	}
+	return stats
+}
+
+//getMsgStat return the msg statistics of cmdType, caller must hold statLock
+func (this *Link) getMsgStat(cmdType string) *common.MsgStat {
+	stat, ok := this.msgStats[cmdType]
+	if !ok {
+		stat = &common.MsgStat{}
+		this.msgStats[cmdType] = stat
+	}
+	return stat
+}
+
+//addRxStat record a received msg
+func (this *Link) addRxStat(cmdType string, size uint64) {
+	this.statLock.Lock()
+	stat := this.getMsgStat(cmdType)
+	stat.RxCount++
+	stat.RxBytes += size
+	this.statLock.Unlock()
+}
+
+//addTxStat record a sent msg
+func (this *Link) addTxStat(cmdType string, size uint64) {
+	this.statLock.Lock()
+	stat := this.getMsgStat(cmdType)
+	stat.TxCount++
+	stat.TxBytes += size
+	this.statLock.Unlock()
+}
+
 func (this *Link) Rx() {
 	conn := this.conn
 	if conn == nil {
 		return
 	}
 
-	reader := bufio.NewReaderSize(conn, common.MAX_BUF_LEN)
+	reader := &countReader{reader: bufio.NewReaderSize(conn, common.MAX_BUF_LEN)}
 
 	for {
+		start := reader.count
 		msg, payloadSize, err := types.ReadMessage(reader)
 		if err != nil {
 			log.Infof("[p2p]error read from %s :%s", this.GetAddr(), err.Error())
 			break
 		}
+		this.addRxStat(msg.CmdType(), reader.count-start)
 
 		t := time.Now()
 		this.UpdateRXTime(t)
@@ -173,7 +248,12 @@ func (this *Link) Tx(msg types.Message) error {
 	}
 
 	sink := comm.NewZeroCopySink(nil)
-	err := types.WriteMessage(sink, msg)
+	var err error
+	if this.GetCompress() {
+		err = types.WriteCompressibleMessage(sink, msg)
+	} else {
+		err = types.WriteMessage(sink, msg)
+	}
 	if err != nil {
 		log.Debugf("[p2p]error serialize messge ", err.Error())
 		return err
@@ -194,6 +274,7 @@ func (this *Link) Tx(msg types.Message) error {
 		this.disconnectNotify()
 		return err
 	}
+	this.addTxStat(msg.CmdType(), uint64(nByteCnt))
 
 	return nil
 }
@@ -173,6 +173,11 @@ func NewVersion(n p2pnet.P2P, isCons bool, height uint32) mt.Message {
 	} else {
 		version.P.Cap[msgCommon.HTTP_INFO_FLAG] = 0x00
 	}
+	if config.DefConfig.P2PNode.EnableMsgCompress {
+		version.P.Cap[msgCommon.COMPRESS_FLAG] = 0x01
+	} else {
+		version.P.Cap[msgCommon.COMPRESS_FLAG] = 0x00
+	}
 	return &version
 }
 
@@ -205,3 +210,25 @@ func NewConsensusDataReq(hash common.Uint256) mt.Message {
 
 	return &dataReq
 }
+
+//tx proof request package
+func NewTxProofReq(hash common.Uint256) mt.Message {
+	log.Trace()
+	var req mt.TxProofReq
+	req.TxHash = hash
+
+	return &req
+}
+
+//tx proof package
+func NewTxProof(height uint32, index uint32, proof []common.Uint256, txn *ct.Transaction, notify []byte) mt.Message {
+	log.Trace()
+	var txProof mt.TxProof
+	txProof.Height = height
+	txProof.Index = index
+	txProof.Proof = proof
+	txProof.Tx = txn
+	txProof.Notify = notify
+
+	return &txProof
+}
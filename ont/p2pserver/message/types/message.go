@@ -20,10 +20,12 @@ package types
 
 import (
 	"bytes"
+	"compress/zlib"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	comm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
@@ -78,6 +80,17 @@ func newMessageHeader(cmd string, length uint32, checksum [common.CHECKSUM_LEN]b
 }
 
 func WriteMessage(sink *comm.ZeroCopySink, msg Message) error {
+	return writeMessage(sink, msg, false)
+}
+
+//WriteCompressibleMessage write msg to sink like WriteMessage, but the payload of
+//large block, headers and consensus msg is compressed. It should only be used for
+//the peers which announce COMPRESS_FLAG in version cap field
+func WriteCompressibleMessage(sink *comm.ZeroCopySink, msg Message) error {
+	return writeMessage(sink, msg, true)
+}
+
+func writeMessage(sink *comm.ZeroCopySink, msg Message, compress bool) error {
 	pstart := sink.Size()
 	sink.NextBytes(common.MSG_HDR_LEN) // can not save the buf, since it may reallocate in sink
 	err := msg.Serialization(sink)
@@ -88,16 +101,75 @@ func WriteMessage(sink *comm.ZeroCopySink, msg Message) error {
 	total := pend - pstart
 	payLen := total - common.MSG_HDR_LEN
 
+	compressed := false
+	if compress && isCompressibleType(msg.CmdType()) && payLen >= common.MIN_COMPRESS_LEN {
+		sink.BackUp(payLen)
+		payload := sink.NextBytes(payLen)
+		data, err := compressPayload(payload)
+		if err == nil && uint64(len(data)) < payLen {
+			sink.BackUp(payLen)
+			sink.WriteBytes(data)
+			payLen = uint64(len(data))
+			total = payLen + common.MSG_HDR_LEN
+			compressed = true
+		}
+	}
+
 	sink.BackUp(total)
 	buf := sink.NextBytes(total)
 	checksum := common.Checksum(buf[common.MSG_HDR_LEN:])
 	hdr := newMessageHeader(msg.CmdType(), uint32(payLen), checksum)
+	if compressed {
+		hdr.CMD[common.COMPRESS_OFFSET] = common.COMPRESS_MARK
+	}
 
 	sink.BackUp(total)
 	writeMessageHeaderInto(sink, hdr)
 	sink.NextBytes(payLen)
 
-	return err
+	return nil
+}
+
+//isCompressibleType return whether the payload of msg type may be compressed
+func isCompressibleType(cmdType string) bool {
+	switch cmdType {
+	case common.BLOCK_TYPE, common.HEADERS_TYPE, common.CONSENSUS_TYPE:
+		return true
+	default:
+		return false
+	}
+}
+
+func compressPayload(data []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	writer := zlib.NewWriter(buf)
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//decompressPayload decompress data, the decompressed length can not exceed MAX_PAYLOAD_LEN
+func decompressPayload(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	buf, err := ioutil.ReadAll(io.LimitReader(reader, common.MAX_PAYLOAD_LEN+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) > common.MAX_PAYLOAD_LEN {
+		return nil, fmt.Errorf("decompressed payload exceed max payload size: %d", common.MAX_PAYLOAD_LEN)
+	}
+	return buf, nil
 }
 
 func ReadMessage(reader io.Reader) (Message, uint32, error) {
@@ -127,6 +199,14 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 		return nil, 0, fmt.Errorf("message checksum mismatch: %x != %x ", hdr.Checksum, checksum)
 	}
 
+	if hdr.CMD[common.COMPRESS_OFFSET] == common.COMPRESS_MARK {
+		hdr.CMD[common.COMPRESS_OFFSET] = 0
+		buf, err = decompressPayload(buf)
+		if err != nil {
+			return nil, 0, fmt.Errorf("decompress message payload error: %s", err)
+		}
+	}
+
 	cmdType := string(bytes.TrimRight(hdr.CMD[:], string(0)))
 	msg, err := MakeEmptyMessage(cmdType)
 	if err != nil {
@@ -140,7 +220,7 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 		return nil, 0, err
 	}
 
-	return msg, hdr.Length, nil
+	return msg, uint32(len(buf)), nil
 }
 
 func MakeEmptyMessage(cmdType string) (Message, error) {
@@ -177,6 +257,10 @@ func MakeEmptyMessage(cmdType string) (Message, error) {
 		return &Disconnected{}, nil
 	case common.GET_BLOCKS_TYPE:
 		return &BlocksReq{}, nil
+	case common.GET_TX_PROOF_TYPE:
+		return &TxProofReq{}, nil
+	case common.TX_PROOF_TYPE:
+		return &TxProof{}, nil
 	default:
 		return nil, errors.New("unsupported cmd type:" + cmdType)
 	}
@@ -21,6 +21,8 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/ontio/ontology-crypto/keypair"
+	comm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/p2pserver/common"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,3 +42,37 @@ func TestMsgHdrSerializationDeserialization(t *testing.T) {
 	assert.Equal(t, hdr, dehdr)
 
 }
+
+func TestCompressibleMessage(t *testing.T) {
+	_, pub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	msg := &Consensus{
+		Cons: ConsensusPayload{
+			Version: 1,
+			Height:  100,
+			Data:    bytes.Repeat([]byte("consensus"), 1000),
+			Owner:   pub,
+		},
+	}
+
+	sink := comm.NewZeroCopySink(nil)
+	err := WriteCompressibleMessage(sink, msg)
+	assert.Nil(t, err)
+	hdr, err := readMessageHeader(bytes.NewBuffer(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, byte(common.COMPRESS_MARK), hdr.CMD[common.COMPRESS_OFFSET])
+	assert.True(t, int(hdr.Length) < len(msg.Cons.Data))
+
+	demsg, size, err := ReadMessage(bytes.NewBuffer(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.True(t, int(size) > len(msg.Cons.Data))
+	assert.Equal(t, msg.Cons.Data, demsg.(*Consensus).Cons.Data)
+	assert.Equal(t, msg.Cons.Height, demsg.(*Consensus).Cons.Height)
+
+	// small payload is never compressed
+	sink.Reset()
+	err = WriteCompressibleMessage(sink, &Ping{Height: 1})
+	assert.Nil(t, err)
+	hdr, err = readMessageHeader(bytes.NewBuffer(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0), hdr.CMD[common.COMPRESS_OFFSET])
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	comm "github.com/ontio/ontology/p2pserver/common"
+)
+
+//TxProofReq request a transaction with its inclusion proof from full node
+type TxProofReq struct {
+	TxHash common.Uint256
+}
+
+//Serialize message payload
+func (this *TxProofReq) Serialization(sink *common.ZeroCopySink) error {
+	sink.WriteHash(this.TxHash)
+	return nil
+}
+
+func (this *TxProofReq) CmdType() string {
+	return comm.GET_TX_PROOF_TYPE
+}
+
+//Deserialize message payload
+func (this *TxProofReq) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.TxHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+//TxProof is a transaction with the merkle proof of its inclusion in the
+//TransactionsRoot of the block header at Height
+type TxProof struct {
+	Height uint32           //height of the block containing the tx
+	Index  uint32           //index of the tx in block
+	Proof  []common.Uint256 //sibling hashes from tx hash to TransactionsRoot
+	Tx     *types.Transaction
+	Notify []byte //json encoded execute notify of the tx
+}
+
+//Serialize message payload
+func (this *TxProof) Serialization(sink *common.ZeroCopySink) error {
+	sink.WriteUint32(this.Height)
+	sink.WriteUint32(this.Index)
+	sink.WriteVarUint(uint64(len(this.Proof)))
+	for _, hash := range this.Proof {
+		sink.WriteHash(hash)
+	}
+	err := this.Tx.Serialization(sink)
+	if err != nil {
+		return err
+	}
+	sink.WriteVarBytes(this.Notify)
+	return nil
+}
+
+func (this *TxProof) CmdType() string {
+	return comm.TX_PROOF_TYPE
+}
+
+//Deserialize message payload
+func (this *TxProof) Deserialization(source *common.ZeroCopySource) error {
+	var eof, irregular bool
+	this.Height, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Index, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	var n uint64
+	n, _, irregular, eof = source.NextVarUint()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof || n > source.Len()/common.UINT256_SIZE {
+		return io.ErrUnexpectedEOF
+	}
+	this.Proof = make([]common.Uint256, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var hash common.Uint256
+		hash, eof = source.NextHash()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		this.Proof = append(this.Proof, hash)
+	}
+	tx := &types.Transaction{}
+	err := tx.Deserialization(source)
+	if err != nil {
+		return err
+	}
+	this.Tx = tx
+	this.Notify, _, irregular, eof = source.NextVarBytes()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
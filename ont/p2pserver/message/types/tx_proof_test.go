@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxProofReqSerializationDeserialization(t *testing.T) {
+	var msg TxProofReq
+	msg.TxHash = Uint256ParseFromBytes([]byte("123456"))
+
+	MessageTest(t, &msg)
+}
+
+func TestTxProofSerializationDeserialization(t *testing.T) {
+	mutable := &types.MutableTransaction{
+		TxType:  types.Invoke,
+		Nonce:   1,
+		Payload: &payload.InvokeCode{Code: []byte{1, 2, 3}},
+	}
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+
+	msg := &TxProof{
+		Height: 100,
+		Index:  2,
+		Proof:  []common.Uint256{{1}, {2}},
+		Tx:     tx,
+		Notify: []byte(`{"State":1}`),
+	}
+	sink := common.NewZeroCopySink(nil)
+	err = WriteMessage(sink, msg)
+	assert.Nil(t, err)
+
+	demsg, _, err := ReadMessage(bytes.NewBuffer(sink.Bytes()))
+	assert.Nil(t, err)
+	txProof := demsg.(*TxProof)
+	assert.Equal(t, msg.Height, txProof.Height)
+	assert.Equal(t, msg.Index, txProof.Index)
+	assert.Equal(t, msg.Proof, txProof.Proof)
+	assert.Equal(t, msg.Notify, txProof.Notify)
+	assert.Equal(t, tx.Hash(), txProof.Tx.Hash())
+}
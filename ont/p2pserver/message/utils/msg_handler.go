@@ -19,6 +19,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -257,6 +258,7 @@ func VersionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 			//p synclink must exist,merged
 			p.ConsLink = remotePeer.ConsLink
 			p.ConsLink.SetID(version.P.Nonce)
+			p.ConsLink.SetCompress(p.GetCompressState())
 			p.SetConsState(remotePeer.GetConsState())
 			remotePeer = p
 
@@ -357,6 +359,8 @@ func VersionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 			remotePeer.SetHttpInfoState(false)
 		}
 		remotePeer.SetHttpInfoPort(version.P.HttpInfoPort)
+		remotePeer.SetCompressState(config.DefConfig.P2PNode.EnableMsgCompress &&
+			version.P.Cap[msgCommon.COMPRESS_FLAG] == 0x01)
 
 		remotePeer.UpdateInfo(time.Now(), version.P.Version,
 			version.P.Services, version.P.SyncPort,
@@ -503,6 +507,15 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 	hash := dataReq.Hash
 	switch reqType {
 	case common.BLOCK:
+		//light client only stores header, the block body is empty
+		if config.DefConfig.Common.EnableLightClient {
+			msg := msgpack.NewNotFound(hash)
+			err := p2p.Send(remotePeer, msg, false)
+			if err != nil {
+				log.Warn(err)
+			}
+			return
+		}
 		reqID := fmt.Sprintf("%x%s", reqType, hash.ToHexString())
 		data := getRespCacheValue(reqID)
 		var block *types.Block
@@ -595,6 +608,10 @@ func InvHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args .
 		}
 	case common.BLOCK:
 		log.Debug("[p2p]receive block message")
+		//light client syncs headers only
+		if config.DefConfig.Common.EnableLightClient {
+			return
+		}
 		for _, id = range inv.P.Blk {
 			log.Debug("[p2p]receive inv-block message, hash is ", id)
 			// TODO check the ID queue
@@ -654,6 +671,88 @@ func DisconnectHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID,
 	}
 }
 
+// TxProofReqHandle handles the tx proof request from light client
+func TxProofReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive tx proof request message", data.Addr, data.Id)
+	//light client has no block body to prove tx
+	if config.DefConfig.Common.EnableLightClient {
+		return
+	}
+	var req = data.Payload.(*msgTypes.TxProofReq)
+
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in TxProofReqHandle")
+		return
+	}
+	msg, err := GetTxProof(req.TxHash)
+	if err != nil {
+		log.Debug("[p2p]can't get tx proof by hash: ", req.TxHash.ToHexString(),
+			" ,send not found message, ", err)
+		msg = msgpack.NewNotFound(req.TxHash)
+	}
+	err = p2p.Send(remotePeer, msg, false)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+}
+
+// TxProofHandle handles the tx proof from full node
+func TxProofHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive tx proof message", data.Addr, data.Id)
+
+	if pid != nil {
+		var txProof = data.Payload.(*msgTypes.TxProof)
+		input := &msgCommon.AppendTxProof{
+			FromID: data.Id,
+			Height: txProof.Height,
+			Index:  txProof.Index,
+			Proof:  txProof.Proof,
+			Tx:     txProof.Tx,
+			Notify: txProof.Notify,
+		}
+		pid.Tell(input)
+	}
+}
+
+//GetTxProof build the tx proof message of the tx specified by hash
+func GetTxProof(txHash common.Uint256) (msgTypes.Message, error) {
+	tx, height, err := ledger.DefLedger.GetTransactionWithHeight(txHash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ledger.DefLedger.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	hashes := make([]common.Uint256, 0, len(block.Transactions))
+	for i, t := range block.Transactions {
+		hash := t.Hash()
+		if hash == txHash {
+			index = i
+		}
+		hashes = append(hashes, hash)
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("tx not in block %d", height)
+	}
+	proof, err := common.MerkleProof(hashes, uint32(index))
+	if err != nil {
+		return nil, err
+	}
+	var notify []byte
+	evt, err := ledger.DefLedger.GetEventNotifyByTx(txHash)
+	if err == nil && evt != nil {
+		notify, err = json.Marshal(evt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return msgpack.NewTxProof(height, uint32(index), proof, tx, notify), nil
+}
+
 //get blk hdrs from starthash to stophash
 func GetHeadersFromHash(startHash common.Uint256, stopHash common.Uint256) ([]*types.Header, error) {
 	var count uint32 = 0
@@ -73,6 +73,8 @@ func (this *MessageRouter) init(p2p p2p.P2P) {
 	this.RegisterMsgHandler(msgCommon.NOT_FOUND_TYPE, NotFoundHandle)
 	this.RegisterMsgHandler(msgCommon.TX_TYPE, TransactionHandle)
 	this.RegisterMsgHandler(msgCommon.DISCONNECT_TYPE, DisconnectHandle)
+	this.RegisterMsgHandler(msgCommon.GET_TX_PROOF_TYPE, TxProofReqHandle)
+	this.RegisterMsgHandler(msgCommon.TX_PROOF_TYPE, TxProofHandle)
 }
 
 // RegisterMsgHandler registers msg handler with the msg type
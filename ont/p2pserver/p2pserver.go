@@ -52,6 +52,7 @@ type P2PServer struct {
 	msgRouter *utils.MessageRouter
 	pid       *evtActor.PID
 	blockSync *BlockSyncMgr
+	light     *LightClient
 	ledger    *ledger.Ledger
 	ReconnectAddrs
 	recentPeers    map[uint32][]string
@@ -77,6 +78,7 @@ func NewServer() *P2PServer {
 
 	p.msgRouter = utils.NewMsgRouter(p.network)
 	p.blockSync = NewBlockSyncMgr(p)
+	p.light = NewLightClient(p)
 	p.recentPeers = make(map[uint32][]string)
 	p.quitSyncRecent = make(chan bool)
 	p.quitOnline = make(chan bool)
@@ -140,6 +142,16 @@ func (this *P2PServer) GetNeighborAddrs() []common.PeerAddr {
 	return this.network.GetNeighborAddrs()
 }
 
+//GetNeighborMsgStats return msg statistics of all nbr peers
+func (this *P2PServer) GetNeighborMsgStats() []*common.PeerMsgStats {
+	peers := this.network.GetNeighbors()
+	stats := make([]*common.PeerMsgStats, 0, len(peers))
+	for _, p := range peers {
+		stats = append(stats, p.GetMsgStats())
+	}
+	return stats
+}
+
 //Xmit called by other module to broadcast msg
 func (this *P2PServer) Xmit(message interface{}) error {
 	log.Debug()
@@ -210,6 +222,16 @@ func (this *P2PServer) OnBlockReceive(fromID uint64, blockSize uint32, block *ty
 	this.blockSync.OnBlockReceive(fromID, blockSize, block)
 }
 
+// OnTxProofReceive passes the tx proof from network to light client
+func (this *P2PServer) OnTxProofReceive(proof *common.AppendTxProof) {
+	this.light.OnTxProofReceive(proof)
+}
+
+// GetVerifiedTx fetches the tx with merkle proof from peers and verifies it
+func (this *P2PServer) GetVerifiedTx(txHash comm.Uint256) (*common.AppendTxProof, error) {
+	return this.light.GetVerifiedTx(txHash)
+}
+
 // Todo: remove it if no use
 func (this *P2PServer) GetConnectionState() uint32 {
 	return common.INIT
@@ -348,6 +348,35 @@ func (this *Peer) GetHttpInfoState() bool {
 	return this.cap[common.HTTP_INFO_FLAG] == 1
 }
 
+//SetCompressState set whether compress large msg to peer
+func (this *Peer) SetCompressState(compress bool) {
+	this.SyncLink.SetCompress(compress)
+	this.ConsLink.SetCompress(compress)
+}
+
+//GetCompressState return whether compress large msg to peer
+func (this *Peer) GetCompressState() bool {
+	return this.SyncLink.GetCompress()
+}
+
+//GetMsgStats return msg statistics of both sync and consensus link
+func (this *Peer) GetMsgStats() *common.PeerMsgStats {
+	stats := &common.PeerMsgStats{
+		ID:       this.GetID(),
+		Addr:     this.GetAddr(),
+		Compress: this.GetCompressState(),
+		Stats:    this.SyncLink.GetMsgStats(),
+	}
+	for cmdType, stat := range this.ConsLink.GetMsgStats() {
+		if s, ok := stats.Stats[cmdType]; ok {
+			s.Add(stat)
+		} else {
+			stats.Stats[cmdType] = stat
+		}
+	}
+	return stats
+}
+
 //GetHttpInfoPort return peer`s httpinfo port
 func (this *Peer) GetHttpInfoPort() uint16 {
 	return this.base.GetHttpInfoPort()
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package abi
+
+import (
+	"strings"
+	"sync"
+)
+
+//DefNeovmAbiRegistry is the neovm contract ABIs registered locally. It is shared by the command line tools
+//and the http services, so it lives outside of the cmd packages
+var DefNeovmAbiRegistry = NewNeovmAbiRegistry()
+
+type NeovmAbiRegistry struct {
+	abis map[string]*NeovmContractAbi
+	lock sync.RWMutex
+}
+
+func NewNeovmAbiRegistry() *NeovmAbiRegistry {
+	return &NeovmAbiRegistry{
+		abis: make(map[string]*NeovmContractAbi),
+	}
+}
+
+//GetNeovmAbi return the ABI of neovm contract by address in hex, nil if not registered
+func (this *NeovmAbiRegistry) GetNeovmAbi(address string) *NeovmContractAbi {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.abis[normalizeHash(address)]
+}
+
+//RegisterNeovmAbi register the ABI of neovm contract, replacing the old one of the same address
+func (this *NeovmAbiRegistry) RegisterNeovmAbi(abi *NeovmContractAbi) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.abis[normalizeHash(abi.Address)] = abi
+}
+
+//normalizeHash remove the 0x prefix of contract hash written by compilers
+func normalizeHash(hash string) string {
+	return strings.TrimPrefix(strings.ToLower(hash), "0x")
+}
@@ -29,6 +29,9 @@ import (
 // when need to check authorization, use CheckWitness
 // when smart contract execute trigger event, use PushNotifications push it to smart contract notifications
 // when need to invoke a smart contract, use AppCall to invoke it
+// when need to invoke a smart contract without failing the caller, use InvokeWithGasLimit to invoke it
+// when need to invoke a smart contract as a transaction on its own, use InvokeAsTransaction to invoke it
+// when need to know the gas consumed by an invocation, use GetRemainGas before and after it
 type ContextRef interface {
 	PushContext(context *Context)
 	CurrentContext() *Context
@@ -40,6 +43,9 @@ type ContextRef interface {
 	NewExecuteEngine(code []byte) (Engine, error)
 	CheckUseGas(gas uint64) bool
 	CheckExecStep() bool
+	InvokeWithGasLimit(engine Engine, gasLimit uint64) (interface{}, error)
+	InvokeAsTransaction(engine Engine, gasLimit uint64) (interface{}, error)
+	GetRemainGas() uint64
 }
 
 type Engine interface {
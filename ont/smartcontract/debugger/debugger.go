@@ -0,0 +1,369 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package debugger provides a breakpoint and stepping debugger for neovm contract execution
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/ontio/ontology/vm/neovm/types"
+)
+
+type Mode byte
+
+const (
+	MODE_STEP     Mode = iota //pause before the next instruction
+	MODE_NEXT                 //pause before the next instruction of current contract, step over APPCALL
+	MODE_CONTINUE             //run until a breakpoint is hit
+	mode_quit
+)
+
+const (
+	REASON_START      = "start"
+	REASON_STEP       = "step"
+	REASON_BREAKPOINT = "breakpoint"
+	REASON_FINISHED   = "finished"
+
+	MAX_ITEM_COUNT = 1024
+)
+
+var (
+	ErrAborted  = errors.New("[Debugger] execution aborted by debugger")
+	ErrFinished = errors.New("[Debugger] execution already finished")
+)
+
+//Breakpoint pause the execution before the instruction at Offset of Contract, or before calling
+//syscall Syscall. Empty contract address matches all contracts
+type Breakpoint struct {
+	Contract common.Address
+	Offset   int
+	Syscall  string
+}
+
+func (this Breakpoint) String() string {
+	if this.Syscall != "" {
+		return fmt.Sprintf("syscall %s", this.Syscall)
+	}
+	if this.Contract == common.ADDRESS_EMPTY {
+		return fmt.Sprintf("offset %d", this.Offset)
+	}
+	return fmt.Sprintf("%s:%d", this.Contract.ToHexString(), this.Offset)
+}
+
+//Frame is a contract in the call stack
+type Frame struct {
+	Contract string
+	Offset   int
+	OpCode   string
+	Syscall  string `json:",omitempty"`
+}
+
+type StorageItem struct {
+	Contract string
+	Key      string
+	Value    string //empty for deleted item
+}
+
+type Notify struct {
+	ContractAddress string
+	States          interface{}
+}
+
+//State is the snapshot of the execution when paused or finished
+type State struct {
+	Reason          string
+	Finished        bool
+	Frames          []Frame
+	EvaluationStack []interface{} `json:",omitempty"`
+	AltStack        []interface{} `json:",omitempty"`
+	Storage         []StorageItem
+	Notify          []Notify
+	Result          interface{} `json:",omitempty"`
+	Error           string      `json:",omitempty"`
+}
+
+//Session drives one execution in a background goroutine, it implements neovm.Debugger
+type Session struct {
+	lock        sync.Mutex
+	breakpoints map[Breakpoint]bool
+	mode        Mode
+	depth       int
+	started     bool
+	services    []*neovm.NeoVmService
+	cache       *storage.CacheDB
+	sc          *smartcontract.SmartContract
+	resume      chan Mode
+	paused      chan *State
+	state       *State
+}
+
+func NewSession() *Session {
+	return &Session{
+		breakpoints: make(map[Breakpoint]bool),
+		mode:        MODE_STEP,
+		resume:      make(chan Mode),
+		paused:      make(chan *State),
+	}
+}
+
+//AddBreakpoint add a breakpoint to session
+func (this *Session) AddBreakpoint(bp Breakpoint) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.breakpoints[bp] = true
+}
+
+//RemoveBreakpoint remove a breakpoint from session
+func (this *Session) RemoveBreakpoint(bp Breakpoint) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	delete(this.breakpoints, bp)
+}
+
+//Breakpoints return all breakpoints of session
+func (this *Session) Breakpoints() []Breakpoint {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	bps := make([]Breakpoint, 0, len(this.breakpoints))
+	for bp := range this.breakpoints {
+		bps = append(bps, bp)
+	}
+	sort.Slice(bps, func(i, j int) bool {
+		return bps[i].String() < bps[j].String()
+	})
+	return bps
+}
+
+//Start run exec in a new goroutine with the session as debugger, and wait for the first pause
+func (this *Session) Start(exec func(debugger neovm.Debugger) (interface{}, error)) *State {
+	go func() {
+		result, err := exec(this)
+		state := this.snapshot(REASON_FINISHED, nil, 0)
+		state.Finished = true
+		state.Result = result
+		if err != nil {
+			state.Error = err.Error()
+		}
+		this.paused <- state
+	}()
+	this.state = <-this.paused
+	return this.state
+}
+
+//Resume continue a paused execution with mode, and wait for the next pause
+func (this *Session) Resume(mode Mode) (*State, error) {
+	if this.state == nil || this.state.Finished {
+		return nil, ErrFinished
+	}
+	this.resume <- mode
+	this.state = <-this.paused
+	return this.state, nil
+}
+
+//Quit abort a paused execution
+func (this *Session) Quit() *State {
+	if this.state == nil || this.state.Finished {
+		return this.state
+	}
+	this.resume <- mode_quit
+	this.state = <-this.paused
+	return this.state
+}
+
+//State return the latest state of session
+func (this *Session) State() *State {
+	return this.state
+}
+
+func (this *Session) Enter(service *neovm.NeoVmService) {
+	if len(this.services) == 0 {
+		this.cache = service.CacheDB
+		this.sc, _ = service.ContextRef.(*smartcontract.SmartContract)
+	}
+	this.services = append(this.services, service)
+}
+
+func (this *Session) Exit(service *neovm.NeoVmService, err error) {
+	if len(this.services) != 0 {
+		this.services = this.services[:len(this.services)-1]
+	}
+}
+
+func (this *Session) Step(service *neovm.NeoVmService, offset int) error {
+	reason := this.pauseReason(service, offset)
+	if reason == "" {
+		return nil
+	}
+	this.paused <- this.snapshot(reason, service, offset)
+	mode := <-this.resume
+	if mode == mode_quit {
+		return ErrAborted
+	}
+	this.mode = mode
+	this.depth = len(this.services)
+	return nil
+}
+
+func (this *Session) pauseReason(service *neovm.NeoVmService, offset int) string {
+	if !this.started {
+		this.started = true
+		return REASON_START
+	}
+	if this.hitBreakpoint(service, offset) {
+		return REASON_BREAKPOINT
+	}
+	switch this.mode {
+	case MODE_STEP:
+		return REASON_STEP
+	case MODE_NEXT:
+		if len(this.services) <= this.depth {
+			return REASON_STEP
+		}
+	}
+	return ""
+}
+
+func (this *Session) hitBreakpoint(service *neovm.NeoVmService, offset int) bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if len(this.breakpoints) == 0 {
+		return false
+	}
+	if name := neovm.SyscallNameAt(service.Code, offset); name != "" && this.breakpoints[Breakpoint{Syscall: name}] {
+		return true
+	}
+	contract := service.ContractAddress
+	return this.breakpoints[Breakpoint{Contract: contract, Offset: offset}] ||
+		this.breakpoints[Breakpoint{Offset: offset}]
+}
+
+//snapshot build the state of execution, it must be called from the execution goroutine
+func (this *Session) snapshot(reason string, current *neovm.NeoVmService, offset int) *State {
+	state := &State{Reason: reason}
+	for _, service := range this.services {
+		pos := offset
+		if service != current {
+			pos = service.Engine.Context.GetInstructionPointer()
+		}
+		contract := service.ContractAddress
+		state.Frames = append(state.Frames, Frame{
+			Contract: contract.ToHexString(),
+			Offset:   pos,
+			OpCode:   neovm.OpCodeName(neovm.OpCodeAt(service.Code, pos)),
+			Syscall:  neovm.SyscallNameAt(service.Code, pos),
+		})
+	}
+	if current != nil {
+		state.EvaluationStack = convertStack(current.Engine.EvaluationStack)
+		state.AltStack = convertStack(current.Engine.AltStack)
+	}
+	if this.cache != nil {
+		this.cache.ForEachStorage(func(key, val []byte) {
+			if len(val) != 0 {
+				if value, err := states.GetValueFromRawStorageItem(val); err == nil {
+					val = value
+				}
+			}
+			item := StorageItem{Key: common.ToHexString(key), Value: common.ToHexString(val)}
+			if len(key) >= common.ADDR_LEN {
+				item.Contract = common.ToHexString(key[:common.ADDR_LEN])
+				item.Key = common.ToHexString(key[common.ADDR_LEN:])
+			}
+			state.Storage = append(state.Storage, item)
+		})
+	}
+	var notifications []*event.NotifyEventInfo
+	if this.sc != nil {
+		notifications = append(notifications, this.sc.Notifications...)
+	}
+	for _, service := range this.services {
+		notifications = append(notifications, service.Notifications...)
+	}
+	for _, n := range notifications {
+		state.Notify = append(state.Notify, Notify{ContractAddress: n.ContractAddress.ToHexString(), States: n.States})
+	}
+	return state
+}
+
+func convertStack(stack *vm.RandomAccessStack) []interface{} {
+	items := make([]interface{}, 0, stack.Count())
+	count := 0
+	for i := 0; i < stack.Count(); i++ {
+		items = append(items, convertItem(stack.Peek(i), &count))
+	}
+	return items
+}
+
+//convertItem render the stack item to hex string, the depth of nested and cyclic items is limited by MAX_ITEM_COUNT
+func convertItem(item types.StackItems, count *int) interface{} {
+	*count++
+	if *count > MAX_ITEM_COUNT {
+		return "..."
+	}
+	switch v := item.(type) {
+	case *types.ByteArray, *types.Boolean:
+		arr, _ := v.GetByteArray()
+		return common.ToHexString(arr)
+	case *types.Integer:
+		i, _ := v.GetBigInteger()
+		if i.Sign() == 0 {
+			return common.ToHexString([]byte{0})
+		}
+		return common.ToHexString(common.BigIntToNeoBytes(i))
+	case *types.Array:
+		arr, _ := v.GetArray()
+		return convertItems(arr, count)
+	case *types.Struct:
+		arr, _ := v.GetStruct()
+		return convertItems(arr, count)
+	case *types.Map:
+		m, _ := v.GetMap()
+		pairs := make([][]interface{}, 0, len(m))
+		for key, val := range m {
+			pairs = append(pairs, []interface{}{convertItem(key, count), convertItem(val, count)})
+		}
+		return pairs
+	case *types.Interop:
+		it, _ := v.GetInterface()
+		if it == nil {
+			return ""
+		}
+		return common.ToHexString(it.ToArray())
+	default:
+		return fmt.Sprintf("%T", item)
+	}
+}
+
+func convertItems(arr []types.StackItems, count *int) []interface{} {
+	items := make([]interface{}, 0, len(arr))
+	for _, val := range arr {
+		items = append(items, convertItem(val, count))
+	}
+	return items
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package debugger
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func syscall(name string) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteByte(byte(vm.SYSCALL))
+	sink.WriteString(name)
+	return sink.Bytes()
+}
+
+func testCode() []byte {
+	code := []byte{byte(vm.PUSH1), byte(vm.PUSH2), byte(vm.ADD), byte(vm.TOALTSTACK)}
+	code = append(code, byte(vm.PUSHBYTES1), 'v', byte(vm.PUSHBYTES1), 'k')
+	code = append(code, syscall(neovm.STORAGE_GETCONTEXT_NAME)...)
+	return append(code, syscall(neovm.STORAGE_PUT_NAME)...)
+}
+
+func startSession(t *testing.T, code []byte) (*Session, *State) {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: 10},
+		CacheDB: storage.NewCacheDB(overlaydb.NewOverlayDB(memback)),
+		Gas:     100000000,
+	}
+	session := NewSession()
+	state := session.Start(func(debugger neovm.Debugger) (interface{}, error) {
+		sc.Debugger = debugger
+		engine, err := sc.NewExecuteEngine(code)
+		if err != nil {
+			return nil, err
+		}
+		return engine.Invoke()
+	})
+	return session, state
+}
+
+func TestSessionStep(t *testing.T) {
+	session, state := startSession(t, testCode())
+	assert.Equal(t, REASON_START, state.Reason)
+	assert.Equal(t, 1, len(state.Frames))
+	assert.Equal(t, 0, state.Frames[0].Offset)
+	assert.Equal(t, "PUSH1", state.Frames[0].OpCode)
+
+	state, err := session.Resume(MODE_STEP)
+	assert.Nil(t, err)
+	assert.Equal(t, REASON_STEP, state.Reason)
+	assert.Equal(t, 1, state.Frames[0].Offset)
+	assert.Equal(t, []interface{}{"01"}, state.EvaluationStack)
+
+	state, _ = session.Resume(MODE_STEP)
+	state, _ = session.Resume(MODE_STEP)
+	assert.Equal(t, "TOALTSTACK", state.Frames[0].OpCode)
+	assert.Equal(t, []interface{}{"03"}, state.EvaluationStack)
+	state, _ = session.Resume(MODE_NEXT)
+	assert.Equal(t, 0, len(state.EvaluationStack))
+	assert.Equal(t, []interface{}{"03"}, state.AltStack)
+}
+
+func TestSessionBreakpoint(t *testing.T) {
+	code := testCode()
+	session, _ := startSession(t, code)
+	session.AddBreakpoint(Breakpoint{Syscall: neovm.STORAGE_PUT_NAME})
+	session.AddBreakpoint(Breakpoint{Offset: 2})
+	assert.Equal(t, 2, len(session.Breakpoints()))
+
+	state, err := session.Resume(MODE_CONTINUE)
+	assert.Nil(t, err)
+	assert.Equal(t, REASON_BREAKPOINT, state.Reason)
+	assert.Equal(t, 2, state.Frames[0].Offset)
+
+	state, _ = session.Resume(MODE_CONTINUE)
+	assert.Equal(t, REASON_BREAKPOINT, state.Reason)
+	assert.Equal(t, neovm.STORAGE_PUT_NAME, state.Frames[0].Syscall)
+	assert.Equal(t, 0, len(state.Storage))
+
+	state, _ = session.Resume(MODE_CONTINUE)
+	assert.True(t, state.Finished)
+	assert.Equal(t, "", state.Error)
+	contract := common.AddressFromVmCode(code)
+	assert.Equal(t, []StorageItem{{Contract: common.ToHexString(contract[:]), Key: "6b", Value: "76"}}, state.Storage)
+
+	_, err = session.Resume(MODE_STEP)
+	assert.Equal(t, ErrFinished, err)
+}
+
+func TestSessionQuit(t *testing.T) {
+	session, _ := startSession(t, testCode())
+	state := session.Quit()
+	assert.True(t, state.Finished)
+	assert.Equal(t, ErrAborted.Error(), state.Error)
+}
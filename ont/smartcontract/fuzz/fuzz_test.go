@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fuzz
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratorDeterministic(t *testing.T) {
+	g1, g2 := NewGenerator(42), NewGenerator(42)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, g1.Next(), g2.Next())
+	}
+}
+
+func TestRunStorage(t *testing.T) {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushByteArray([]byte("v"))
+	builder.EmitPushByteArray([]byte("k"))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GETCONTEXT_NAME))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_PUT_NAME))
+	builder.EmitPushByteArray([]byte("k"))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GETCONTEXT_NAME))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GET_NAME))
+	code := builder.ToArray()
+
+	result, err := Run(code, GAS_LIMIT)
+	assert.Nil(t, err)
+	assert.Equal(t, "", result.Err)
+	assert.Equal(t, `"76"`, result.Result)
+	assert.Nil(t, Check(code))
+
+	empty, err := Run([]byte{byte(vm.PUSH1)}, GAS_LIMIT)
+	assert.Nil(t, err)
+	assert.NotEqual(t, empty.StateHash, result.StateHash)
+}
+
+func TestRunOutOfGas(t *testing.T) {
+	code := []byte{byte(vm.JMP), 0, 0}
+	result, err := Run(code, GAS_LIMIT)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", result.Err)
+	assert.Equal(t, GAS_LIMIT, result.Gas)
+	assert.Nil(t, Check(code))
+}
+
+func TestFuzz(t *testing.T) {
+	rounds := 500
+	if testing.Short() {
+		rounds = 50
+	}
+	g := NewGenerator(1)
+	for i := 0; i < rounds; i++ {
+		code := g.Next()
+		if err := Check(code); err != nil {
+			t.Fatalf("round %d code %x: %s", i, code, err)
+		}
+	}
+}
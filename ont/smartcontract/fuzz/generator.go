@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package fuzz generates random neovm scripts and checks the invariants of their execution
+package fuzz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+const (
+	MAX_INSTRUCTIONS = 64 // max instructions of a generated script
+	MAX_RANDOM_BYTES = 64 // max length of a script of random bytes
+)
+
+var (
+	opCodes = []vm.OpCode{
+		vm.NOP, vm.DUPFROMALTSTACK, vm.TOALTSTACK, vm.FROMALTSTACK, vm.XDROP, vm.XSWAP, vm.XTUCK,
+		vm.DEPTH, vm.DROP, vm.DUP, vm.NIP, vm.OVER, vm.PICK, vm.ROLL, vm.ROT, vm.SWAP, vm.TUCK,
+		vm.CAT, vm.SUBSTR, vm.LEFT, vm.RIGHT, vm.SIZE,
+		vm.INVERT, vm.AND, vm.OR, vm.XOR, vm.EQUAL,
+		vm.INC, vm.DEC, vm.SIGN, vm.NEGATE, vm.ABS, vm.NOT, vm.NZ, vm.ADD, vm.SUB, vm.MUL, vm.DIV, vm.MOD,
+		vm.SHL, vm.SHR, vm.BOOLAND, vm.BOOLOR, vm.NUMEQUAL, vm.NUMNOTEQUAL, vm.LT, vm.GT, vm.LTE, vm.GTE,
+		vm.MIN, vm.MAX, vm.WITHIN,
+		vm.SHA1, vm.SHA256, vm.HASH160, vm.HASH256,
+		vm.ARRAYSIZE, vm.PACK, vm.UNPACK, vm.PICKITEM, vm.SETITEM, vm.NEWARRAY, vm.NEWSTRUCT, vm.NEWMAP,
+		vm.APPEND, vm.REVERSE, vm.REMOVE,
+		vm.THROWIFNOT,
+	}
+
+	jumpOpCodes = []vm.OpCode{vm.JMP, vm.JMPIF, vm.JMPIFNOT, vm.CALL}
+
+	//syscalls only depend on the CacheDB and the calling contract, so that the result is the same
+	//whether the script is the entry or called by APPCALL
+	syscalls = []string{
+		neovm.STORAGE_GET_NAME,
+		neovm.STORAGE_PUT_NAME,
+		neovm.STORAGE_DELETE_NAME,
+		neovm.STORAGE_GETCONTEXT_NAME,
+		neovm.STORAGE_GETREADONLYCONTEXT_NAME,
+		neovm.STORAGECONTEXT_ASREADONLY_NAME,
+		neovm.RUNTIME_GETTIME_NAME,
+		neovm.RUNTIME_NOTIFY_NAME,
+		neovm.RUNTIME_GETTRIGGER_NAME,
+		neovm.RUNTIME_SERIALIZE_NAME,
+		neovm.RUNTIME_DESERIALIZE_NAME,
+		neovm.RUNTIME_BASE58TOADDRESS_NAME,
+		neovm.RUNTIME_ADDRESSTOBASE58_NAME,
+		neovm.RUNTIME_GETCURRENTBLOCKHASH_NAME,
+		neovm.GETEXECUTINGSCRIPTHASH_NAME,
+	}
+
+	integers = []int64{0, -1, 1, 2, 16, 17, 20, 255, 256, 1024, 2048, 1<<31 - 1, -1 << 31, 1<<63 - 1, -1 << 63}
+
+	//the scripts stay shorter than 32K so that the int16 jump offsets can reach any instruction
+	byteLengths = []int{0, 1, 2, 20, 32, 33, 75, 76, 255, 256}
+)
+
+type jump struct {
+	offset int // offset of the jump instruction
+	target int // index of the target instruction
+}
+
+//Generator generates neovm scripts deterministically from a seed
+type Generator struct {
+	rand *rand.Rand
+}
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+//Next return a valid script, a mutated script or random bytes
+func (this *Generator) Next() []byte {
+	switch n := this.rand.Intn(10); {
+	case n < 6:
+		return this.Script()
+	case n < 9:
+		return this.Mutate(this.Script())
+	default:
+		return this.RandomBytes()
+	}
+}
+
+//Script return a well formed script, whose instructions are complete and jumps target instruction boundaries
+func (this *Generator) Script() []byte {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	count := 1 + this.rand.Intn(MAX_INSTRUCTIONS)
+	starts := make([]int, 0, count+1)
+	var jumps []jump
+	for i := 0; i < count; i++ {
+		starts = append(starts, len(builder.ToArray()))
+		switch n := this.rand.Intn(100); {
+		case n < 20:
+			this.pushInteger(builder)
+		case n < 35:
+			builder.EmitPushByteArray(this.byteArray())
+		case n < 75:
+			builder.Emit(opCodes[this.rand.Intn(len(opCodes))])
+		case n < 85:
+			builder.Emit(vm.SYSCALL)
+			builder.EmitPushByteArray([]byte(syscalls[this.rand.Intn(len(syscalls))]))
+		case n < 95:
+			jumps = append(jumps, jump{offset: len(builder.ToArray()), target: this.rand.Intn(count + 1)})
+			builder.Emit(jumpOpCodes[this.rand.Intn(len(jumpOpCodes))])
+			builder.Emit(vm.PUSH0)
+			builder.Emit(vm.PUSH0)
+		case n < 98:
+			builder.Emit(vm.RET)
+		default:
+			builder.Emit(vm.THROW)
+		}
+	}
+	code := builder.ToArray()
+	starts = append(starts, len(code))
+	for _, j := range jumps {
+		binary.LittleEndian.PutUint16(code[j.offset+1:], uint16(int16(starts[j.target]-j.offset)))
+	}
+	return code
+}
+
+//Mutate return a copy of code with a random byte changed, inserted or removed, or truncated
+func (this *Generator) Mutate(code []byte) []byte {
+	mutated := append([]byte{}, code...)
+	pos := this.rand.Intn(len(mutated))
+	switch this.rand.Intn(5) {
+	case 0:
+		mutated[pos] ^= byte(1 << uint(this.rand.Intn(8)))
+	case 1:
+		mutated[pos] = byte(this.rand.Intn(256))
+	case 2:
+		mutated = append(mutated[:pos], append([]byte{byte(this.rand.Intn(256))}, mutated[pos:]...)...)
+	case 3:
+		if len(mutated) > 1 {
+			mutated = append(mutated[:pos], mutated[pos+1:]...)
+		}
+	default:
+		mutated = mutated[:pos+1]
+	}
+	return mutated
+}
+
+//RandomBytes return a script of random bytes
+func (this *Generator) RandomBytes() []byte {
+	code := make([]byte, 1+this.rand.Intn(MAX_RANDOM_BYTES))
+	this.rand.Read(code)
+	return code
+}
+
+//pushInteger push an edge case integer or a power of 2 up to 256 bits, the big ones are pushed as
+//byte array since EmitPushInteger only checks the low 64 bits for the short forms
+func (this *Generator) pushInteger(builder *vm.ParamsBuilder) {
+	if this.rand.Intn(4) != 0 {
+		builder.EmitPushInteger(big.NewInt(integers[this.rand.Intn(len(integers))]))
+		return
+	}
+	i := new(big.Int).Lsh(big.NewInt(1), uint(this.rand.Intn(256)))
+	if this.rand.Intn(2) == 0 {
+		i.Neg(i)
+	}
+	builder.EmitPushByteArray(common.BigIntToNeoBytes(i))
+}
+
+func (this *Generator) byteArray() []byte {
+	data := make([]byte, byteLengths[this.rand.Intn(len(byteLengths))])
+	this.rand.Read(data)
+	return data
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	scommon "github.com/ontio/ontology/smartcontract/common"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	vmtypes "github.com/ontio/ontology/vm/neovm/types"
+)
+
+const GAS_LIMIT uint64 = 20000 // gas of a checked execution
+
+//Result is the outcome of an execution, all the fields must be the same across repeated executions
+type Result struct {
+	Gas       uint64         // consumed gas
+	Err       string         // execution error, empty if succeeded
+	Result    string         // returned stack item in JSON, converted like the pre-execution result
+	Notify    string         // notifications in JSON
+	StateHash common.Uint256 // hash of the state changes
+}
+
+//gasMonitor implements neovm.Debugger, it checks the remaining gas never increases between instructions
+type gasMonitor struct {
+	sc        *smartcontract.SmartContract
+	remaining uint64
+	err       error
+}
+
+func (this *gasMonitor) Enter(service *neovm.NeoVmService) {}
+
+func (this *gasMonitor) Step(service *neovm.NeoVmService, offset int) error {
+	if this.err == nil && this.sc.Gas > this.remaining {
+		this.err = fmt.Errorf("remaining gas increases from %d to %d at offset %d of %s", this.remaining,
+			this.sc.Gas, offset, service.ContractAddress.ToHexString())
+	}
+	this.remaining = this.sc.Gas
+	return nil
+}
+
+func (this *gasMonitor) Exit(service *neovm.NeoVmService, err error) {}
+
+//Run execute code with gas in NeoVmService over an empty in-memory CacheDB. The error of execution is
+//kept in the Result, a non nil error means a panic of the vm or a broken invariant of the execution
+func Run(code []byte, gas uint64) (*Result, error) {
+	return run(code, nil, gas)
+}
+
+//Check execute code several times and return an error if any invariant is broken:
+//no panic, the remaining gas never increases, repeated executions have the same result and state hash,
+//a successful execution needs exactly the consumed gas, the returned stack item is the same after
+//serialize and deserialize, and calling code by APPCALL changes nothing but the gas
+func Check(code []byte) error {
+	result, err := Run(code, GAS_LIMIT)
+	if err != nil {
+		return err
+	}
+	repeated, err := Run(code, GAS_LIMIT)
+	if err != nil {
+		return err
+	}
+	if *repeated != *result {
+		return fmt.Errorf("repeated execution differs: %+v, %+v", result, repeated)
+	}
+
+	if result.Err == "" && result.Gas > 0 {
+		exact, err := Run(code, result.Gas)
+		if err != nil {
+			return err
+		}
+		if *exact != *result {
+			return fmt.Errorf("execution with the consumed gas %d differs: %+v, %+v", result.Gas, result, exact)
+		}
+		insufficient, err := Run(code, result.Gas-1)
+		if err != nil {
+			return err
+		}
+		if insufficient.Err == "" {
+			return fmt.Errorf("execution with gas %d less than the consumed succeeds: %+v", result.Gas-1, insufficient)
+		}
+	}
+
+	appCallGas, err := neovm.GasPrice(nil, neovm.APPCALL_NAME)
+	if err != nil {
+		return err
+	}
+	address := common.AddressFromVmCode(code)
+	caller := append([]byte{byte(vm.APPCALL)}, address[:]...)
+	called, err := run(caller, [][]byte{code}, GAS_LIMIT+appCallGas)
+	if err != nil {
+		return err
+	}
+	if (called.Err == "") != (result.Err == "") || called.Result != result.Result || called.Notify != result.Notify ||
+		called.StateHash != result.StateHash || called.Gas != result.Gas+appCallGas {
+		return fmt.Errorf("execution by APPCALL differs: %+v, %+v", result, called)
+	}
+	return nil
+}
+
+//run execute entry over an in-memory CacheDB with contracts deployed
+func run(entry []byte, contracts [][]byte, gas uint64) (result *Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("vm panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	if err != nil {
+		return nil, err
+	}
+	if err := deploy(memback, contracts); err != nil {
+		return nil, err
+	}
+	overlay := overlaydb.NewOverlayDB(memback)
+	monitor := &gasMonitor{remaining: gas}
+	sc := &smartcontract.SmartContract{
+		Config:   &smartcontract.Config{Time: 10, Height: 10, Tx: &types.Transaction{}},
+		CacheDB:  storage.NewCacheDB(overlay),
+		Gas:      gas,
+		Debugger: monitor,
+	}
+	monitor.sc = sc
+	engine, err := sc.NewExecuteEngine(entry)
+	if err != nil {
+		return nil, err
+	}
+	ret, execErr := engine.Invoke()
+	if monitor.err != nil {
+		return nil, monitor.err
+	}
+	if sc.Gas > gas {
+		return nil, fmt.Errorf("remaining gas %d exceeds gas limit %d", sc.Gas, gas)
+	}
+
+	result = &Result{Gas: gas - sc.Gas}
+	if execErr != nil {
+		result.Err = execErr.Error()
+	} else if item, ok := ret.(vmtypes.StackItems); ok {
+		if result.Result, err = convertResult(item); err != nil {
+			return nil, err
+		}
+	}
+	notify, err := json.Marshal(sc.Notifications)
+	if err != nil {
+		return nil, err
+	}
+	result.Notify = string(notify)
+	sc.CacheDB.Commit()
+	result.StateHash = overlay.ChangeHash()
+	return result, nil
+}
+
+//deploy put contracts into store as committed by a previous block
+func deploy(store *leveldbstore.LevelDBStore, contracts [][]byte) error {
+	if len(contracts) == 0 {
+		return nil
+	}
+	overlay := overlaydb.NewOverlayDB(store)
+	cache := storage.NewCacheDB(overlay)
+	for _, code := range contracts {
+		if err := cache.PutContract(&payload.DeployCode{Code: code}); err != nil {
+			return err
+		}
+	}
+	cache.Commit()
+	store.NewBatch()
+	overlay.CommitTo()
+	return store.BatchCommit()
+}
+
+//convertResult return the JSON of item converted like the pre-execution result, and check item
+//is the same after serialize and deserialize when it is serializable
+func convertResult(item vmtypes.StackItems) (string, error) {
+	converted, err := convertItem(item)
+	if err != nil {
+		return "", err
+	}
+	data, err := neovm.SerializeStackItem(item)
+	if err != nil {
+		return converted, nil
+	}
+	deserialized, err := neovm.DeserializeStackItem(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("deserialize serialized stack item %x error: %v", data, err)
+	}
+	reserialized, err := neovm.SerializeStackItem(deserialized)
+	if err != nil {
+		return "", fmt.Errorf("serialize deserialized stack item %x error: %v", data, err)
+	}
+	if !bytes.Equal(data, reserialized) {
+		return "", fmt.Errorf("stack item changes after serialize and deserialize: %x, %x", data, reserialized)
+	}
+	reconverted, err := convertItem(deserialized)
+	if err != nil {
+		return "", err
+	}
+	if reconverted != converted {
+		return "", fmt.Errorf("stack item changes after serialize and deserialize: %s, %s", converted, reconverted)
+	}
+	return converted, nil
+}
+
+//convertItem return the JSON of item converted by ConvertNeoVmTypeHexString, or the conversion error
+func convertItem(item vmtypes.StackItems) (string, error) {
+	converted, err := scommon.ConvertNeoVmTypeHexString(item)
+	if err != nil {
+		return err.Error(), nil
+	}
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package profiler breaks down the gas consumed by a neovm execution
+package profiler
+
+import (
+	"sort"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/states"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+type instruction struct {
+	offset  int
+	opCode  string
+	syscall string
+	native  string
+	gas     uint64 // remaining gas before the instruction
+}
+
+type frame struct {
+	contract string
+	gas      uint64 // remaining gas when entering the contract
+	pending  *instruction
+}
+
+type instructionKey struct {
+	contract string
+	offset   int
+}
+
+//Profiler implements neovm.Debugger, it measures the remaining gas before every instruction
+type Profiler struct {
+	frames       []*frame
+	gas          uint64
+	instructions map[instructionKey]*states.InstructionCost
+	opCodes      map[string]*states.GasCost
+	syscalls     map[string]*states.GasCost
+	natives      map[string]*states.GasCost
+	contracts    map[string]*states.GasCost
+}
+
+func NewProfiler() *Profiler {
+	return &Profiler{
+		instructions: make(map[instructionKey]*states.InstructionCost),
+		opCodes:      make(map[string]*states.GasCost),
+		syscalls:     make(map[string]*states.GasCost),
+		natives:      make(map[string]*states.GasCost),
+		contracts:    make(map[string]*states.GasCost),
+	}
+}
+
+func (this *Profiler) Enter(service *neovm.NeoVmService) {
+	contract := service.ContractAddress
+	this.frames = append(this.frames, &frame{contract: contract.ToHexString(), gas: remainingGas(service)})
+}
+
+func (this *Profiler) Step(service *neovm.NeoVmService, offset int) error {
+	if len(this.frames) == 0 {
+		return nil
+	}
+	gas := remainingGas(service)
+	f := this.frames[len(this.frames)-1]
+	this.settle(f, gas)
+
+	opCode := neovm.OpCodeName(neovm.OpCodeAt(service.Code, offset))
+	f.pending = &instruction{offset: offset, opCode: opCode, gas: gas}
+	f.pending.syscall = neovm.SyscallNameAt(service.Code, offset)
+	if f.pending.syscall == neovm.NATIVE_INVOKE_NAME {
+		f.pending.native = nativeTarget(service.Engine)
+	}
+	return nil
+}
+
+func (this *Profiler) Exit(service *neovm.NeoVmService, err error) {
+	if len(this.frames) == 0 {
+		return
+	}
+	gas := remainingGas(service)
+	f := this.frames[len(this.frames)-1]
+	this.frames = this.frames[:len(this.frames)-1]
+	this.settle(f, gas)
+
+	consumed := sub(f.gas, gas)
+	addGasCost(this.contracts, f.contract, consumed)
+	if len(this.frames) != 0 {
+		//exclude the gas of called contract from the APPCALL instruction of caller
+		if caller := this.frames[len(this.frames)-1].pending; caller != nil {
+			caller.gas = sub(caller.gas, consumed)
+		}
+	}
+}
+
+//Profile return the gas breakdown of execution
+func (this *Profiler) Profile() *states.Profile {
+	profile := &states.Profile{
+		Gas:          this.gas,
+		Instructions: make([]*states.InstructionCost, 0, len(this.instructions)),
+		OpCodes:      sortGasCosts(this.opCodes),
+		Syscalls:     sortGasCosts(this.syscalls),
+		NativeCalls:  sortGasCosts(this.natives),
+		Contracts:    sortGasCosts(this.contracts),
+	}
+	for _, cost := range this.instructions {
+		profile.Instructions = append(profile.Instructions, cost)
+	}
+	sort.Slice(profile.Instructions, func(i, j int) bool {
+		a, b := profile.Instructions[i], profile.Instructions[j]
+		if a.Contract != b.Contract {
+			return a.Contract < b.Contract
+		}
+		return a.Offset < b.Offset
+	})
+	return profile
+}
+
+//settle the gas of the pending instruction of frame
+func (this *Profiler) settle(f *frame, gas uint64) {
+	ins := f.pending
+	if ins == nil {
+		return
+	}
+	f.pending = nil
+	consumed := sub(ins.gas, gas)
+	this.gas += consumed
+
+	key := instructionKey{contract: f.contract, offset: ins.offset}
+	cost, ok := this.instructions[key]
+	if !ok {
+		cost = &states.InstructionCost{Contract: f.contract, Offset: ins.offset, OpCode: ins.opCode, Syscall: ins.syscall}
+		this.instructions[key] = cost
+	}
+	cost.Count += 1
+	cost.Gas += consumed
+
+	addGasCost(this.opCodes, ins.opCode, consumed)
+	if ins.syscall != "" {
+		addGasCost(this.syscalls, ins.syscall, consumed)
+	}
+	if ins.native != "" {
+		addGasCost(this.natives, ins.native, consumed)
+	}
+}
+
+func remainingGas(service *neovm.NeoVmService) uint64 {
+	if sc, ok := service.ContextRef.(*smartcontract.SmartContract); ok {
+		return sc.Gas
+	}
+	return 0
+}
+
+//nativeTarget return the native contract address and method the Ontology.Native.Invoke syscall will call
+func nativeTarget(engine *vm.ExecutionEngine) string {
+	if engine.EvaluationStack.Count() < 3 {
+		return ""
+	}
+	address, err := engine.EvaluationStack.Peek(1).GetByteArray()
+	if err != nil {
+		return ""
+	}
+	method, err := engine.EvaluationStack.Peek(2).GetByteArray()
+	if err != nil {
+		return ""
+	}
+	return common.ToHexString(address) + "." + string(method)
+}
+
+func addGasCost(costs map[string]*states.GasCost, name string, gas uint64) {
+	cost, ok := costs[name]
+	if !ok {
+		cost = &states.GasCost{Name: name}
+		costs[name] = cost
+	}
+	cost.Count += 1
+	cost.Gas += gas
+}
+
+//sortGasCosts sort the gas costs by gas consumed in descending order
+func sortGasCosts(costs map[string]*states.GasCost) []*states.GasCost {
+	list := make([]*states.GasCost, 0, len(costs))
+	for _, cost := range costs {
+		list = append(list, cost)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Gas != list[j].Gas {
+			return list[i].Gas > list[j].Gas
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func sub(a, b uint64) uint64 {
+	if a < b {
+		return 0
+	}
+	return a - b
+}
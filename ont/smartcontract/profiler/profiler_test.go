@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package profiler
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiler(t *testing.T) {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteBytes([]byte{byte(vm.PUSH1), byte(vm.PUSH2), byte(vm.ADD), byte(vm.DROP)})
+	sink.WriteBytes([]byte{byte(vm.PUSHBYTES1), 'v', byte(vm.PUSHBYTES1), 'k'})
+	sink.WriteByte(byte(vm.SYSCALL))
+	sink.WriteString(neovm.STORAGE_GETCONTEXT_NAME)
+	putOffset := len(sink.Bytes())
+	sink.WriteByte(byte(vm.SYSCALL))
+	sink.WriteString(neovm.STORAGE_PUT_NAME)
+	code := sink.Bytes()
+
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	p := NewProfiler()
+	sc := &smartcontract.SmartContract{
+		Config:   &smartcontract.Config{Height: 10},
+		CacheDB:  storage.NewCacheDB(overlaydb.NewOverlayDB(memback)),
+		Gas:      100000000,
+		Debugger: p,
+	}
+	engine, err := sc.NewExecuteEngine(code)
+	assert.Nil(t, err)
+	_, err = engine.Invoke()
+	assert.Nil(t, err)
+
+	profile := p.Profile()
+	assert.Equal(t, 100000000-sc.Gas, profile.Gas)
+	assert.Equal(t, 8, len(profile.Instructions))
+	assert.Equal(t, 1, len(profile.Contracts))
+	assert.Equal(t, profile.Gas, profile.Contracts[0].Gas)
+
+	put := profile.Instructions[len(profile.Instructions)-1]
+	assert.Equal(t, putOffset, put.Offset)
+	assert.Equal(t, neovm.STORAGE_PUT_NAME, put.Syscall)
+	assert.Equal(t, neovm.STORAGE_PUT_NAME, profile.Syscalls[0].Name)
+	assert.Equal(t, put.Gas, profile.Syscalls[0].Gas)
+
+	var sum uint64
+	for _, cost := range profile.OpCodes {
+		sum += cost.Gas
+	}
+	assert.Equal(t, profile.Gas, sum)
+}
+
+func TestProfilerAppCall(t *testing.T) {
+	callee := []byte{byte(vm.PUSH1), byte(vm.PUSH2), byte(vm.ADD)}
+	calleeAddr := common.AddressFromVmCode(callee)
+	code := append([]byte{byte(vm.APPCALL)}, calleeAddr[:]...)
+	code = append(code, byte(vm.DROP))
+
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: callee}))
+	p := NewProfiler()
+	sc := &smartcontract.SmartContract{
+		Config:   &smartcontract.Config{Height: 10},
+		CacheDB:  cache,
+		Gas:      100000000,
+		Debugger: p,
+	}
+	engine, err := sc.NewExecuteEngine(code)
+	assert.Nil(t, err)
+	_, err = engine.Invoke()
+	assert.Nil(t, err)
+
+	profile := p.Profile()
+	assert.Equal(t, 100000000-sc.Gas, profile.Gas)
+	assert.Equal(t, 5, len(profile.Instructions))
+	var sum uint64
+	for _, ins := range profile.Instructions {
+		sum += ins.Gas
+	}
+	assert.Equal(t, profile.Gas, sum)
+
+	contracts := make(map[string]uint64)
+	for _, cost := range profile.Contracts {
+		contracts[cost.Name] = cost.Gas
+	}
+	caller := common.AddressFromVmCode(code)
+	assert.Equal(t, profile.Gas, contracts[caller.ToHexString()])
+	assert.True(t, contracts[calleeAddr.ToHexString()] > 0)
+	assert.True(t, contracts[calleeAddr.ToHexString()] < profile.Gas)
+}
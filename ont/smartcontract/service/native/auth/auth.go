@@ -202,6 +202,14 @@ func AssignFuncsToRole(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("[assignFuncsToRole] putRoleFunc failed: %v", err)
 	}
+	if isUpgraded(native) {
+		if err := backfillRoles(native, param.ContractAddr); err != nil {
+			return nil, fmt.Errorf("[assignFuncsToRole] backfillRoles failed: %v", err)
+		}
+		if err := addRole(native, param.ContractAddr, param.Role); err != nil {
+			return nil, fmt.Errorf("[assignFuncsToRole] addRole failed: %v", err)
+		}
+	}
 
 	pushEvent(native, sucState)
 	return utils.BYTE_TRUE, nil
@@ -231,12 +239,22 @@ func assignToRole(native *native.NativeService, param *OntIDsToRoleParam) (bool,
 		return false, nil
 	}
 
-	//init a permanent auth token
+	//init a permanent auth token, it expires at param.ExpireHeight if set after the upgrade height
 	token := new(AuthToken)
 	token.expireTime = uint32(future.Unix())
 	token.level = 2
 	token.role = param.Role
+	expireHeight := uint32(param.ExpireHeight)
 
+	upgraded := isUpgraded(native)
+	if upgraded {
+		if err := backfillRoles(native, param.ContractAddr); err != nil {
+			return false, fmt.Errorf("backfillRoles failed: %v", err)
+		}
+		if err := addRole(native, param.ContractAddr, param.Role); err != nil {
+			return false, fmt.Errorf("addRole failed: %v", err)
+		}
+	}
 	for _, p := range param.Persons {
 		if p == nil {
 			continue
@@ -249,7 +267,7 @@ func assignToRole(native *native.NativeService, param *OntIDsToRoleParam) (bool,
 			tokens = new(roleTokens)
 			tokens.tokens = make([]*AuthToken, 1)
 			tokens.tokens[0] = token
-		} else {
+		} else if findRoleToken(tokens, param.Role) < 0 {
 			ret, err := hasRole(native, param.ContractAddr, p, param.Role)
 			if err != nil {
 				return false, fmt.Errorf("check if %s has role %s failed: %v", string(p),
@@ -261,14 +279,32 @@ func assignToRole(native *native.NativeService, param *OntIDsToRoleParam) (bool,
 				continue
 			}
 		}
+		//assigning a role again only refreshes its expire height
 		err = putOntIDToken(native, param.ContractAddr, p, tokens)
 		if err != nil {
 			return false, err
 		}
+		if !upgraded {
+			continue
+		}
+		putRoleExpire(native, param.ContractAddr, p, param.Role, expireHeight)
+		if err := addRoleHolder(native, param.ContractAddr, param.Role, p); err != nil {
+			return false, fmt.Errorf("addRoleHolder failed: %v", err)
+		}
 	}
 	return true, nil
 }
 
+//findRoleToken return the index of role in tokens, -1 if not found
+func findRoleToken(tokens *roleTokens, role []byte) int {
+	for i, token := range tokens.tokens {
+		if bytes.Compare(token.role, role) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
 func AssignOntIDsToRole(native *native.NativeService) ([]byte, error) {
 	//deserialize param
 	param := new(OntIDsToRoleParam)
@@ -280,6 +316,10 @@ func AssignOntIDsToRole(native *native.NativeService) ([]byte, error) {
 	if param.Role == nil {
 		return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: role is nil")
 	}
+	if isUpgraded(native) && param.ExpireHeight != 0 && param.ExpireHeight <= uint64(native.Height) {
+		return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: expire height %d is not above current height %d",
+			param.ExpireHeight, native.Height)
+	}
 	for i, ontID := range param.Persons {
 		if !account.VerifyID(string(ontID)) {
 			return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: param.Persons[%d]=%s",
@@ -312,6 +352,13 @@ func getAuthToken(native *native.NativeService, contractAddr common.Address, ont
 	if tokens != nil {
 		for _, token := range tokens.tokens {
 			if bytes.Compare(token.role, role) == 0 { //permanent token
+				expired, err := isRoleExpired(native, contractAddr, ontID, role)
+				if err != nil {
+					return nil, fmt.Errorf("get expire height failed, caused by %v", err)
+				}
+				if expired {
+					break
+				}
 				return token, nil
 			}
 		}
@@ -323,6 +370,13 @@ func getAuthToken(native *native.NativeService, contractAddr common.Address, ont
 	if status != nil {
 		for _, s := range status.status {
 			if bytes.Compare(s.role, role) == 0 && native.Time < s.expireTime { //temporary token
+				valid, err := isRootValid(native, contractAddr, s)
+				if err != nil {
+					return nil, fmt.Errorf("check delegation root failed, caused by %v", err)
+				}
+				if !valid {
+					break
+				}
 				token := new(AuthToken)
 				token.role = s.role
 				token.level = s.level
@@ -589,6 +643,13 @@ func verifyToken(native *native.NativeService, contractAddr common.Address, call
 			if funcs == nil || token.expireTime < native.Time {
 				continue
 			}
+			expired, err := isRoleExpired(native, contractAddr, caller, token.role)
+			if err != nil {
+				return false, fmt.Errorf("isRoleExpired failed: %v", err)
+			}
+			if expired {
+				continue
+			}
 			for _, f := range funcs.funcNames {
 				if strings.Compare(fn, f) == 0 {
 					return true, nil
@@ -610,6 +671,13 @@ func verifyToken(native *native.NativeService, contractAddr common.Address, call
 			if funcs == nil || s.expireTime < native.Time {
 				continue
 			}
+			valid, err := isRootValid(native, contractAddr, s)
+			if err != nil {
+				return false, fmt.Errorf("isRootValid failed: %v", err)
+			}
+			if !valid {
+				continue
+			}
 			for _, f := range funcs.funcNames {
 				if strings.Compare(fn, f) == 0 {
 					return true, nil
@@ -645,6 +713,181 @@ func VerifyToken(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_FALSE, nil
 }
 
+/*
+ * revoke removes the role assigned to ontID as well as the role delegated to it.
+ * delegations made by ontID become invalid with the revocation, see isRootValid.
+ */
+func revoke(native *native.NativeService, param *RevokeRoleParam) (bool, error) {
+	admin, err := getContractAdmin(native, param.ContractAddr)
+	if err != nil {
+		return false, fmt.Errorf("getContractAdmin failed: %v", err)
+	}
+	if admin == nil {
+		return false, fmt.Errorf("admin of contract %s is not set", param.ContractAddr.ToHexString())
+	}
+	if bytes.Compare(admin, param.AdminOntID) != 0 {
+		log.Debugf("param's adminOntID doesn't match: %s != %s", string(param.AdminOntID),
+			string(admin))
+		return false, nil
+	}
+	valid, err := verifySig(native, param.AdminOntID, param.KeyNo)
+	if err != nil {
+		return false, fmt.Errorf("verify admin's signature failed: %v", err)
+	}
+	if !valid {
+		log.Debugf("[revokeOntIDFromRole] verifySig return false: adminOntID=%s, keyNo=%d",
+			string(admin), param.KeyNo)
+		return false, nil
+	}
+
+	if err := backfillRoles(native, param.ContractAddr); err != nil {
+		return false, fmt.Errorf("backfillRoles failed: %v", err)
+	}
+	revoked := false
+	tokens, err := getOntIDToken(native, param.ContractAddr, param.OntID)
+	if err != nil {
+		return false, fmt.Errorf("getOntIDToken failed: %v", err)
+	}
+	if tokens != nil {
+		if i := findRoleToken(tokens, param.Role); i >= 0 {
+			tokens.tokens = append(tokens.tokens[:i], tokens.tokens[i+1:]...)
+			if err := putOntIDToken(native, param.ContractAddr, param.OntID, tokens); err != nil {
+				return false, err
+			}
+			revoked = true
+		}
+	}
+	putRoleExpire(native, param.ContractAddr, param.OntID, param.Role, 0)
+	if err := removeRoleHolder(native, param.ContractAddr, param.Role, param.OntID); err != nil {
+		return false, fmt.Errorf("removeRoleHolder failed: %v", err)
+	}
+
+	status, err := getDelegateStatus(native, param.ContractAddr, param.OntID)
+	if err != nil {
+		return false, fmt.Errorf("getDelegateStatus failed: %v", err)
+	}
+	if status != nil {
+		for i, s := range status.status {
+			if bytes.Compare(s.role, param.Role) == 0 {
+				status.status = append(status.status[:i], status.status[i+1:]...)
+				if err := putDelegateStatus(native, param.ContractAddr, param.OntID, status); err != nil {
+					return false, fmt.Errorf("putDelegateStatus failed: %v", err)
+				}
+				revoked = true
+				break
+			}
+		}
+	}
+	if !revoked {
+		log.Debugf("[revokeOntIDFromRole] %s does not have role %s", string(param.OntID), string(param.Role))
+	}
+	return revoked, nil
+}
+
+func RevokeOntIDFromRole(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return nil, fmt.Errorf("[revokeOntIDFromRole] block num is not reached for this func")
+	}
+	param := new(RevokeRoleParam)
+	rd := bytes.NewReader(native.Input)
+	if err := param.Deserialize(rd); err != nil {
+		return nil, fmt.Errorf("[revokeOntIDFromRole] deserialize param failed: %v", err)
+	}
+	if param.Role == nil || param.OntID == nil {
+		return nil, fmt.Errorf("[revokeOntIDFromRole] invalid param: role or ontID is nil")
+	}
+
+	ret, err := revoke(native, param)
+	if err != nil {
+		return nil, fmt.Errorf("[revokeOntIDFromRole] failed: %v", err)
+	}
+
+	contract := param.ContractAddr.ToHexString()
+	failState := []interface{}{"revokeOntIDFromRole", contract, param.OntID, param.Role, false}
+	sucState := []interface{}{"revokeOntIDFromRole", contract, param.OntID, param.Role, true}
+	if ret {
+		pushEvent(native, sucState)
+		return utils.BYTE_TRUE, nil
+	}
+	pushEvent(native, failState)
+	return utils.BYTE_FALSE, nil
+}
+
+//GetRoles return all roles defined in the contract, the param is the contract address.
+//Roles defined before the upgrade height are listed after the first role change of the contract
+func GetRoles(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return nil, fmt.Errorf("[getRoles] block num is not reached for this func")
+	}
+	contractAddr, err := utils.ReadAddress(bytes.NewReader(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("[getRoles] deserialize param failed: %v", err)
+	}
+	roles, err := getRoles(native, contractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("[getRoles] getRoles failed: %v", err)
+	}
+	bf := new(bytes.Buffer)
+	if err := roles.Serialize(bf); err != nil {
+		return nil, fmt.Errorf("[getRoles] serialize RoleList failed: %v", err)
+	}
+	return bf.Bytes(), nil
+}
+
+//GetRoleFuncs return funcNames assigned to the role
+func GetRoleFuncs(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return nil, fmt.Errorf("[getRoleFuncs] block num is not reached for this func")
+	}
+	param := new(RoleQueryParam)
+	if err := param.Deserialize(bytes.NewReader(native.Input)); err != nil {
+		return nil, fmt.Errorf("[getRoleFuncs] deserialize param failed: %v", err)
+	}
+	funcs, err := getRoleFunc(native, param.ContractAddr, param.Role)
+	if err != nil {
+		return nil, fmt.Errorf("[getRoleFuncs] getRoleFunc failed: %v", err)
+	}
+	list := new(FuncList)
+	if funcs != nil {
+		list.FuncNames = funcs.funcNames
+	}
+	bf := new(bytes.Buffer)
+	if err := list.Serialize(bf); err != nil {
+		return nil, fmt.Errorf("[getRoleFuncs] serialize FuncList failed: %v", err)
+	}
+	return bf.Bytes(), nil
+}
+
+//GetRoleHolders return ontIDs assigned to the role with their expire height,
+//expired holders are kept until they are revoked. Holders assigned before the upgrade height are
+//listed after the first role change of the contract
+func GetRoleHolders(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return nil, fmt.Errorf("[getRoleHolders] block num is not reached for this func")
+	}
+	param := new(RoleQueryParam)
+	if err := param.Deserialize(bytes.NewReader(native.Input)); err != nil {
+		return nil, fmt.Errorf("[getRoleHolders] deserialize param failed: %v", err)
+	}
+	holders, err := getRoleHolders(native, param.ContractAddr, param.Role)
+	if err != nil {
+		return nil, fmt.Errorf("[getRoleHolders] getRoleHolders failed: %v", err)
+	}
+	result := new(RoleHolders)
+	for _, ontID := range holders.ontIDs {
+		height, err := getRoleExpire(native, param.ContractAddr, ontID, param.Role)
+		if err != nil {
+			return nil, fmt.Errorf("[getRoleHolders] getRoleExpire failed: %v", err)
+		}
+		result.Holders = append(result.Holders, &RoleHolder{OntID: ontID, ExpireHeight: height})
+	}
+	bf := new(bytes.Buffer)
+	if err := result.Serialize(bf); err != nil {
+		return nil, fmt.Errorf("[getRoleHolders] serialize RoleHolders failed: %v", err)
+	}
+	return bf.Bytes(), nil
+}
+
 func verifySig(native *native.NativeService, ontID []byte, keyNo uint64) (bool, error) {
 	bf := new(bytes.Buffer)
 	if err := serialization.WriteVarBytes(bf, ontID); err != nil {
@@ -677,4 +920,8 @@ func RegisterAuthContract(native *native.NativeService) {
 	native.Register("assignOntIDsToRole", AssignOntIDsToRole)
 	native.Register("verifyToken", VerifyToken)
 	native.Register("transfer", Transfer)
+	native.Register("revokeOntIDFromRole", RevokeOntIDFromRole)
+	native.Register("getRoles", GetRoles)
+	native.Register("getRoleFuncs", GetRoleFuncs)
+	native.Register("getRoleHolders", GetRoleHolders)
 }
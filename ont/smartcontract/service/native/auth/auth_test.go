@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func putRoleToken(t *testing.T, native *native.NativeService, contract common.Address, ontID []byte, roles ...string) {
+	tokens := new(roleTokens)
+	for _, role := range roles {
+		tokens.tokens = append(tokens.tokens, &AuthToken{role: []byte(role), level: 2, expireTime: uint32(future.Unix())})
+	}
+	assert.Nil(t, putOntIDToken(native, contract, ontID, tokens))
+}
+
+func TestBackfillRoles(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	native := testsuite.NewNativeService(t, utils.AuthContractAddress, upgrade-1)
+	contract := common.Address{1}
+	assert.Nil(t, putRoleFunc(native, contract, []byte("admin"), &roleFuncs{funcNames: []string{"foo"}}))
+	putRoleToken(t, native, contract, []byte("did:ont:alice"), "admin", "user")
+	putRoleToken(t, native, contract, []byte("did:ont:bob"), "user")
+	putRoleToken(t, native, common.Address{2}, []byte("did:ont:carol"), "user")
+
+	//the getters are read-only
+	native.Height = upgrade
+	bf := new(bytes.Buffer)
+	assert.Nil(t, utils.WriteAddress(bf, contract))
+	native.Input = bf.Bytes()
+	_, err := GetRoles(native)
+	assert.Nil(t, err)
+	roles, err := getRoles(native, contract)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(roles.Roles))
+
+	assert.Nil(t, backfillRoles(native, contract))
+	roles, err = getRoles(native, contract)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("admin"), []byte("user")}, roles.Roles)
+	holders, err := getRoleHolders(native, contract, []byte("user"))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("did:ont:alice"), []byte("did:ont:bob")}, holders.ontIDs)
+
+	//backfill runs only once
+	assert.Nil(t, removeRoleHolder(native, contract, []byte("user"), []byte("did:ont:bob")))
+	assert.Nil(t, backfillRoles(native, contract))
+	holders, err = getRoleHolders(native, contract, []byte("user"))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("did:ont:alice")}, holders.ontIDs)
+}
+
+func TestDelegation_Cascade(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	native := testsuite.NewNativeService(t, utils.AuthContractAddress, upgrade-1)
+	contract := common.Address{1}
+	root, to, role := []byte("did:ont:alice"), []byte("did:ont:bob"), []byte("admin")
+	putRoleToken(t, native, contract, root, "admin")
+	status := &Status{status: []*DelegateStatus{{root: root, AuthToken: AuthToken{role: role, level: 1, expireTime: 100}}}}
+	assert.Nil(t, putDelegateStatus(native, contract, to, status))
+
+	ret, err := hasRole(native, contract, to, role)
+	assert.Nil(t, err)
+	assert.True(t, ret)
+
+	//the delegation expires with its root
+	native.Height = upgrade
+	putRoleExpire(native, contract, root, role, upgrade+1)
+	ret, err = hasRole(native, contract, to, role)
+	assert.Nil(t, err)
+	assert.True(t, ret)
+	native.Height = upgrade + 1
+	ret, err = hasRole(native, contract, to, role)
+	assert.Nil(t, err)
+	assert.False(t, ret)
+
+	//the delegation is revoked with its root
+	putRoleExpire(native, contract, root, role, 0)
+	ret, err = hasRole(native, contract, to, role)
+	assert.Nil(t, err)
+	assert.True(t, ret)
+	putRoleToken(t, native, contract, root)
+	ret, err = hasRole(native, contract, to, role)
+	assert.Nil(t, err)
+	assert.False(t, ret)
+
+	//the revocation does not affect the delegation before the upgrade height
+	native.Height = upgrade - 1
+	ret, err = hasRole(native, contract, to, role)
+	assert.Nil(t, err)
+	assert.True(t, ret)
+}
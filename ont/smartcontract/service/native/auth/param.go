@@ -22,10 +22,12 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/vm/neovm/types"
 )
 
 /* **********************************************   */
@@ -152,6 +154,7 @@ type OntIDsToRoleParam struct {
 	Role         []byte
 	Persons      [][]byte
 	KeyNo        uint64
+	ExpireHeight uint64 //optional, 0 means the role never expires
 }
 
 func (this *OntIDsToRoleParam) Serialize(w io.Writer) error {
@@ -175,6 +178,11 @@ func (this *OntIDsToRoleParam) Serialize(w io.Writer) error {
 	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
 		return nil
 	}
+	if this.ExpireHeight != 0 {
+		if err := utils.WriteVarUint(w, this.ExpireHeight); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -204,6 +212,20 @@ func (this *OntIDsToRoleParam) Deserialize(rd io.Reader) error {
 	if this.KeyNo, err = utils.ReadVarUint(rd); err != nil {
 		return err
 	}
+	//expire height is optional to keep the old param format valid
+	height, err := serialization.ReadVarBytes(rd)
+	if err == io.EOF {
+		this.ExpireHeight = 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	h := types.BigIntFromBytes(height)
+	if h.Sign() < 0 || h.Cmp(big.NewInt(math.MaxUint32)) > 0 {
+		return fmt.Errorf("invalid expire height: %s", h.String())
+	}
+	this.ExpireHeight = h.Uint64()
 	return nil
 }
 
@@ -358,3 +380,76 @@ func (this *VerifyTokenParam) Deserialize(rd io.Reader) error {
 	}
 	return nil
 }
+
+type RevokeRoleParam struct {
+	ContractAddr common.Address
+	AdminOntID   []byte
+	Role         []byte
+	OntID        []byte
+	KeyNo        uint64
+}
+
+func (this *RevokeRoleParam) Serialize(w io.Writer) error {
+	if err := serializeAddress(w, this.ContractAddr); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.AdminOntID); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.Role); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.OntID); err != nil {
+		return err
+	}
+	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *RevokeRoleParam) Deserialize(rd io.Reader) error {
+	var err error
+	if this.ContractAddr, err = utils.ReadAddress(rd); err != nil {
+		return err
+	}
+	if this.AdminOntID, err = serialization.ReadVarBytes(rd); err != nil {
+		return err
+	}
+	if this.Role, err = serialization.ReadVarBytes(rd); err != nil {
+		return err
+	}
+	if this.OntID, err = serialization.ReadVarBytes(rd); err != nil {
+		return err
+	}
+	if this.KeyNo, err = utils.ReadVarUint(rd); err != nil {
+		return err
+	}
+	return nil
+}
+
+type RoleQueryParam struct {
+	ContractAddr common.Address
+	Role         []byte
+}
+
+func (this *RoleQueryParam) Serialize(w io.Writer) error {
+	if err := serializeAddress(w, this.ContractAddr); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.Role); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *RoleQueryParam) Deserialize(rd io.Reader) error {
+	var err error
+	if this.ContractAddr, err = utils.ReadAddress(rd); err != nil {
+		return err
+	}
+	if this.Role, err = serialization.ReadVarBytes(rd); err != nil {
+		return err
+	}
+	return nil
+}
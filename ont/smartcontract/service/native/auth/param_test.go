@@ -129,6 +129,52 @@ func TestSerialization_AssignOntIDs(t *testing.T) {
 	assert.Equal(t, param, param2)
 }
 
+func TestSerialization_AssignOntIDsWithExpire(t *testing.T) {
+	param := &OntIDsToRoleParam{
+		ContractAddr: OntContractAddr,
+		AdminOntID:   admin,
+		Role:         []byte(role),
+		Persons:      [][]byte{p1, p2},
+		KeyNo:        1,
+		ExpireHeight: 100000,
+	}
+	bf := new(bytes.Buffer)
+	if err := param.Serialize(bf); err != nil {
+		t.Fatal(err)
+	}
+	param2 := new(OntIDsToRoleParam)
+	if err := param2.Deserialize(bytes.NewReader(bf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, param, param2)
+
+	//expire height above uint32 is rejected
+	bf.Reset()
+	param.ExpireHeight = 0
+	param.Serialize(bf)
+	utils.WriteVarUint(bf, 1<<32)
+	assert.Error(t, param2.Deserialize(bytes.NewReader(bf.Bytes())))
+}
+
+func TestSerialization_Revoke(t *testing.T) {
+	param := &RevokeRoleParam{
+		ContractAddr: OntContractAddr,
+		AdminOntID:   admin,
+		Role:         []byte(role),
+		OntID:        p1,
+		KeyNo:        1,
+	}
+	bf := new(bytes.Buffer)
+	if err := param.Serialize(bf); err != nil {
+		t.Fatal(err)
+	}
+	param2 := new(RevokeRoleParam)
+	if err := param2.Deserialize(bytes.NewReader(bf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, param, param2)
+}
+
 func TestSerialization_Delegate(t *testing.T) {
 	param := &DelegateParam{
 		ContractAddr: OntContractAddr,
@@ -187,3 +187,133 @@ func (this *roleTokens) Deserialize(rd io.Reader) error {
 	}
 	return nil
 }
+
+func serializeBytesList(w io.Writer, list [][]byte) error {
+	if err := serialization.WriteUint32(w, uint32(len(list))); err != nil {
+		return err
+	}
+	for _, b := range list {
+		if err := serialization.WriteVarBytes(w, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deserializeBytesList(rd io.Reader) ([][]byte, error) {
+	n, err := serialization.ReadUint32(rd)
+	if err != nil {
+		return nil, err
+	}
+	list := make([][]byte, 0)
+	for i := uint32(0); i < n; i++ {
+		b, err := serialization.ReadVarBytes(rd)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, b)
+	}
+	return list, nil
+}
+
+/*
+ * all roles ever defined in a contract, returned by getRoles
+ */
+type RoleList struct {
+	Roles [][]byte
+}
+
+func (this *RoleList) Serialize(w io.Writer) error {
+	return serializeBytesList(w, this.Roles)
+}
+
+func (this *RoleList) Deserialize(rd io.Reader) error {
+	var err error
+	this.Roles, err = deserializeBytesList(rd)
+	return err
+}
+
+/*
+ * ontIDs assigned to a role by the admin
+ */
+type holderList struct {
+	ontIDs [][]byte
+}
+
+func (this *holderList) Serialize(w io.Writer) error {
+	return serializeBytesList(w, this.ontIDs)
+}
+
+func (this *holderList) Deserialize(rd io.Reader) error {
+	var err error
+	this.ontIDs, err = deserializeBytesList(rd)
+	return err
+}
+
+/*
+ * funcNames of a role, returned by getRoleFuncs
+ */
+type FuncList struct {
+	FuncNames []string
+}
+
+func (this *FuncList) Serialize(w io.Writer) error {
+	funcs := &roleFuncs{funcNames: this.FuncNames}
+	return funcs.Serialize(w)
+}
+
+func (this *FuncList) Deserialize(rd io.Reader) error {
+	funcs := new(roleFuncs)
+	if err := funcs.Deserialize(rd); err != nil {
+		return err
+	}
+	this.FuncNames = funcs.funcNames
+	return nil
+}
+
+/*
+ * holders of a role, returned by getRoleHolders.
+ * ExpireHeight is 0 if the role never expires
+ */
+type RoleHolder struct {
+	OntID        []byte
+	ExpireHeight uint32
+}
+
+type RoleHolders struct {
+	Holders []*RoleHolder
+}
+
+func (this *RoleHolders) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint32(w, uint32(len(this.Holders))); err != nil {
+		return err
+	}
+	for _, h := range this.Holders {
+		if err := serialization.WriteVarBytes(w, h.OntID); err != nil {
+			return err
+		}
+		if err := serialization.WriteUint32(w, h.ExpireHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *RoleHolders) Deserialize(rd io.Reader) error {
+	n, err := serialization.ReadUint32(rd)
+	if err != nil {
+		return err
+	}
+	this.Holders = make([]*RoleHolder, 0)
+	for i := uint32(0); i < n; i++ {
+		h := new(RoleHolder)
+		if h.OntID, err = serialization.ReadVarBytes(rd); err != nil {
+			return err
+		}
+		if h.ExpireHeight, err = serialization.ReadUint32(rd); err != nil {
+			return err
+		}
+		this.Holders = append(this.Holders, h)
+	}
+	return nil
+}
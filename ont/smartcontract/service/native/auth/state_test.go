@@ -21,6 +21,8 @@ package auth
 import (
 	"bytes"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSerRoleFuncs(t *testing.T) {
@@ -98,3 +100,32 @@ func TestSerDelegateStatus(t *testing.T) {
 		t.Fatalf("failed")
 	}
 }
+
+func TestSerRoleHolders(t *testing.T) {
+	holders := &RoleHolders{
+		Holders: []*RoleHolder{
+			{OntID: []byte("did:ont:1"), ExpireHeight: 0},
+			{OntID: []byte("did:ont:2"), ExpireHeight: 1000},
+		},
+	}
+	bf := new(bytes.Buffer)
+	if err := holders.Serialize(bf); err != nil {
+		t.Fatal(err)
+	}
+	holders2 := new(RoleHolders)
+	if err := holders2.Deserialize(bytes.NewReader(bf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, holders, holders2)
+
+	roles := &RoleList{Roles: [][]byte{[]byte("role1"), []byte("role2")}}
+	bf.Reset()
+	if err := roles.Serialize(bf); err != nil {
+		t.Fatal(err)
+	}
+	roles2 := new(RoleList)
+	if err := roles2.Deserialize(bytes.NewReader(bf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, roles, roles2)
+}
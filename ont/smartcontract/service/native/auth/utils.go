@@ -24,7 +24,9 @@ import (
 	"io"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/serialization"
+	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -35,11 +37,26 @@ var (
 	PreRoleFunc       = []byte{0x02}
 	PreRoleToken      = []byte{0x03}
 	PreDelegateStatus = []byte{0x04}
+	PreRoleExpire     = []byte{0x05}
+	PreRoles          = []byte{0x06}
+	PreRoleHolders    = []byte{0x07}
+	PreBackfilled     = []byte{0x08}
 )
 
+//gas charged for each role func and role token scanned by backfillRoles
+const BACKFILL_ITEM_GAS uint64 = 1000
+
+//isUpgraded return true if role expiry, revocation and the role lists are enabled at the current height
+func isUpgraded(native *native.NativeService) bool {
+	return native.Height >= config.GetNativeUpgradeHeight()
+}
+
 //type(this.contractAddr.Admin) = []byte
 func concatContractAdminKey(native *native.NativeService, contractAddr common.Address) []byte {
-	this := native.ContextRef.CurrentContext().ContractAddress
+	return contractAdminKey(native.ContextRef.CurrentContext().ContractAddress, contractAddr)
+}
+
+func contractAdminKey(this, contractAddr common.Address) []byte {
 	adminKey := append(this[:], contractAddr[:]...)
 	adminKey = append(adminKey, PreAdmin...)
 
@@ -47,7 +64,15 @@ func concatContractAdminKey(native *native.NativeService, contractAddr common.Ad
 }
 
 func getContractAdmin(native *native.NativeService, contractAddr common.Address) ([]byte, error) {
-	key := concatContractAdminKey(native, contractAddr)
+	return getContractAdminByKey(native, concatContractAdminKey(native, contractAddr))
+}
+
+//GetContractAdmin return the admin ONT ID of contract, nil if not set. It can be called by other native contracts
+func GetContractAdmin(native *native.NativeService, contractAddr common.Address) ([]byte, error) {
+	return getContractAdminByKey(native, contractAdminKey(utils.AuthContractAddress, contractAddr))
+}
+
+func getContractAdminByKey(native *native.NativeService, key []byte) ([]byte, error) {
 	item, err := utils.GetStorageItem(native, key)
 	if err != nil {
 		return nil, err
@@ -181,6 +206,264 @@ func putDelegateStatus(native *native.NativeService, contractAddr common.Address
 	return nil
 }
 
+//type(this.contractAddr.RoleExpire.ontID.role) = uint32
+func concatRoleExpireKey(native *native.NativeService, contractAddr common.Address, ontID, role []byte) []byte {
+	this := native.ContextRef.CurrentContext().ContractAddress
+	key := append(this[:], contractAddr[:]...)
+	key = append(key, PreRoleExpire...)
+	bf := new(bytes.Buffer)
+	serialization.WriteVarBytes(bf, ontID)
+	key = append(key, bf.Bytes()...)
+	key = append(key, role...)
+
+	return key
+}
+
+//getRoleExpire return 0 if the role assigned to ontID never expires
+func getRoleExpire(native *native.NativeService, contractAddr common.Address, ontID, role []byte) (uint32, error) {
+	key := concatRoleExpireKey(native, contractAddr, ontID, role)
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return 0, err
+	}
+	if item == nil {
+		return 0, nil
+	}
+	height, err := serialization.ReadUint32(bytes.NewReader(item.Value))
+	if err != nil {
+		return 0, fmt.Errorf("deserialize expire height failed. data: %x", item.Value)
+	}
+	return height, nil
+}
+
+func putRoleExpire(native *native.NativeService, contractAddr common.Address, ontID, role []byte, height uint32) {
+	key := concatRoleExpireKey(native, contractAddr, ontID, role)
+	if height == 0 {
+		native.CacheDB.Delete(key)
+		return
+	}
+	bf := new(bytes.Buffer)
+	serialization.WriteUint32(bf, height)
+	utils.PutBytes(native, key, bf.Bytes())
+}
+
+func isRoleExpired(native *native.NativeService, contractAddr common.Address, ontID, role []byte) (bool, error) {
+	height, err := getRoleExpire(native, contractAddr, ontID, role)
+	if err != nil {
+		return false, err
+	}
+	return height != 0 && native.Height >= height, nil
+}
+
+//type(this.contractAddr.Roles) = RoleList
+func concatRolesKey(native *native.NativeService, contractAddr common.Address) []byte {
+	this := native.ContextRef.CurrentContext().ContractAddress
+	key := append(this[:], contractAddr[:]...)
+	key = append(key, PreRoles...)
+
+	return key
+}
+
+func getRoles(native *native.NativeService, contractAddr common.Address) (*RoleList, error) {
+	key := concatRolesKey(native, contractAddr)
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return nil, err
+	}
+	roles := new(RoleList)
+	if item == nil {
+		return roles, nil
+	}
+	if err := roles.Deserialize(bytes.NewReader(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize RoleList object failed. data: %x", item.Value)
+	}
+	return roles, nil
+}
+
+//addRole record role in the role list of contract if it is not there yet
+func addRole(native *native.NativeService, contractAddr common.Address, role []byte) error {
+	roles, err := getRoles(native, contractAddr)
+	if err != nil {
+		return err
+	}
+	for _, r := range roles.Roles {
+		if bytes.Equal(r, role) {
+			return nil
+		}
+	}
+	roles.Roles = append(roles.Roles, role)
+	bf := new(bytes.Buffer)
+	if err := roles.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize RoleList failed, caused by %v", err)
+	}
+	utils.PutBytes(native, concatRolesKey(native, contractAddr), bf.Bytes())
+	return nil
+}
+
+//type(this.contractAddr.RoleHolders.role) = holderList
+func concatRoleHoldersKey(native *native.NativeService, contractAddr common.Address, role []byte) []byte {
+	this := native.ContextRef.CurrentContext().ContractAddress
+	key := append(this[:], contractAddr[:]...)
+	key = append(key, PreRoleHolders...)
+	key = append(key, role...)
+
+	return key
+}
+
+func getRoleHolders(native *native.NativeService, contractAddr common.Address, role []byte) (*holderList, error) {
+	key := concatRoleHoldersKey(native, contractAddr, role)
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return nil, err
+	}
+	holders := new(holderList)
+	if item == nil {
+		return holders, nil
+	}
+	if err := holders.Deserialize(bytes.NewReader(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize holderList object failed. data: %x", item.Value)
+	}
+	return holders, nil
+}
+
+func putRoleHolders(native *native.NativeService, contractAddr common.Address, role []byte, holders *holderList) error {
+	key := concatRoleHoldersKey(native, contractAddr, role)
+	if len(holders.ontIDs) == 0 {
+		native.CacheDB.Delete(key)
+		return nil
+	}
+	bf := new(bytes.Buffer)
+	if err := holders.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize holderList failed, caused by %v", err)
+	}
+	utils.PutBytes(native, key, bf.Bytes())
+	return nil
+}
+
+func addRoleHolder(native *native.NativeService, contractAddr common.Address, role, ontID []byte) error {
+	holders, err := getRoleHolders(native, contractAddr, role)
+	if err != nil {
+		return err
+	}
+	for _, h := range holders.ontIDs {
+		if bytes.Equal(h, ontID) {
+			return nil
+		}
+	}
+	holders.ontIDs = append(holders.ontIDs, ontID)
+	return putRoleHolders(native, contractAddr, role, holders)
+}
+
+func removeRoleHolder(native *native.NativeService, contractAddr common.Address, role, ontID []byte) error {
+	holders, err := getRoleHolders(native, contractAddr, role)
+	if err != nil {
+		return err
+	}
+	for i, h := range holders.ontIDs {
+		if bytes.Equal(h, ontID) {
+			holders.ontIDs = append(holders.ontIDs[:i], holders.ontIDs[i+1:]...)
+			return putRoleHolders(native, contractAddr, role, holders)
+		}
+	}
+	return nil
+}
+
+//type(this.contractAddr.Backfilled) = bool
+func concatBackfilledKey(native *native.NativeService, contractAddr common.Address) []byte {
+	this := native.ContextRef.CurrentContext().ContractAddress
+	key := append(this[:], contractAddr[:]...)
+	key = append(key, PreBackfilled...)
+
+	return key
+}
+
+//scanPrefix return the key suffixes and values of the items stored under prefix
+func scanPrefix(native *native.NativeService, prefix []byte) ([][]byte, [][]byte, error) {
+	var suffixes, values [][]byte
+	iter := native.CacheDB.NewIterator(prefix)
+	defer iter.Release()
+	for has := iter.First(); has; has = iter.Next() {
+		value, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		suffixes = append(suffixes, append([]byte{}, iter.Key()[len(prefix):]...))
+		values = append(values, append([]byte{}, value...))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, nil, err
+	}
+	return suffixes, values, nil
+}
+
+//backfillRoles record the roles and holders assigned before the upgrade height in the role list
+//and the holder lists, it runs once for each contract on its first role change and charges gas for the scan
+func backfillRoles(native *native.NativeService, contractAddr common.Address) error {
+	key := concatBackfilledKey(native, contractAddr)
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return err
+	}
+	if item != nil {
+		return nil
+	}
+	roles, _, err := scanPrefix(native, concatRoleFuncKey(native, contractAddr, nil))
+	if err != nil {
+		return fmt.Errorf("scan role funcs failed, caused by %v", err)
+	}
+	for _, role := range roles {
+		if !native.ContextRef.CheckUseGas(BACKFILL_ITEM_GAS) {
+			return fmt.Errorf("gas insufficient")
+		}
+		if err := addRole(native, contractAddr, role); err != nil {
+			return err
+		}
+	}
+	ontIDs, values, err := scanPrefix(native, concatOntIDTokenKey(native, contractAddr, nil))
+	if err != nil {
+		return fmt.Errorf("scan role tokens failed, caused by %v", err)
+	}
+	for i, ontID := range ontIDs {
+		if !native.ContextRef.CheckUseGas(BACKFILL_ITEM_GAS) {
+			return fmt.Errorf("gas insufficient")
+		}
+		tokens := new(roleTokens)
+		if err := tokens.Deserialize(bytes.NewReader(values[i])); err != nil {
+			return fmt.Errorf("deserialize roleTokens object failed. data: %x", values[i])
+		}
+		for _, token := range tokens.tokens {
+			if err := addRole(native, contractAddr, token.role); err != nil {
+				return err
+			}
+			if err := addRoleHolder(native, contractAddr, token.role, ontID); err != nil {
+				return err
+			}
+		}
+	}
+	utils.PutBytes(native, key, utils.BYTE_TRUE)
+	return nil
+}
+
+//isRootValid return false if the root of a delegation no longer holds the role because it is revoked or expired,
+//delegations are kept valid before the upgrade height
+func isRootValid(native *native.NativeService, contractAddr common.Address, s *DelegateStatus) (bool, error) {
+	if !isUpgraded(native) {
+		return true, nil
+	}
+	tokens, err := getOntIDToken(native, contractAddr, s.root)
+	if err != nil {
+		return false, err
+	}
+	if tokens == nil || findRoleToken(tokens, s.role) < 0 {
+		return false, nil
+	}
+	expired, err := isRoleExpired(native, contractAddr, s.root, s.role)
+	if err != nil {
+		return false, err
+	}
+	return !expired, nil
+}
+
 //remote duplicates in the slice of string
 func stringSliceUniq(s []string) []string {
 	smap := make(map[string]int)
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract_abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/auth"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	//function name
+	SET_ABI = "setAbi"
+	GET_ABI = "getAbi"
+
+	//key prefix
+	ABI = "abi"
+
+	MAX_ABI_SIZE = 64 * 1024
+)
+
+//Init contract abi registry address
+func InitContractAbi() {
+	native.Contracts[utils.AbiContractAddress] = RegisterContractAbiContract
+}
+
+//Register methods of contract abi registry
+func RegisterContractAbiContract(native *native.NativeService) {
+	native.Register(SET_ABI, SetAbi)
+	native.Register(GET_ABI, GetAbi)
+}
+
+//SetAbi attach the ABI to a deployed contract, replacing the old one. It is called by
+//the contract itself, or by the contract admin set in the auth contract
+func SetAbi(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("setAbi, block num is not reached for this func")
+	}
+	params := new(SetAbiParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("setAbi, deserialize params error: %v", err)
+	}
+	dep, err := native.CacheDB.GetContract(params.Contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("setAbi, get contract error: %v", err)
+	}
+	if dep == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("setAbi, contract %s not exists", params.Contract.ToHexString())
+	}
+	if err := checkAbi(params.Contract, params.Abi); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("setAbi, %v", err)
+	}
+	if err := checkAuthority(native, params.Contract, params.KeyNo); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("setAbi, %v", err)
+	}
+	utils.PutBytes(native, abiKey(params.Contract), params.Abi)
+	pushEvent(native, []interface{}{"SetAbi", params.Contract.ToHexString()})
+	return utils.BYTE_TRUE, nil
+}
+
+//GetAbi return the ABI of contract in JSON, empty if not set
+func GetAbi(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil, fmt.Errorf("getAbi, block num is not reached for this func")
+	}
+	addr, err := utils.ReadAddress(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("getAbi, deserialize contract address error: %v", err)
+	}
+	item, err := utils.GetStorageItem(native, abiKey(addr))
+	if err != nil {
+		return nil, fmt.Errorf("getAbi, get abi error: %v", err)
+	}
+	if item == nil {
+		return []byte{}, nil
+	}
+	return item.Value, nil
+}
+
+//AbiKey return the storage key of contract ABI in the registry, without registry address
+func AbiKey(contract common.Address) []byte {
+	return append([]byte(ABI), contract[:]...)
+}
+
+func abiKey(contract common.Address) []byte {
+	return utils.ConcatKey(utils.AbiContractAddress, AbiKey(contract))
+}
+
+//checkAbi check the ABI is a JSON object, and the hash in it is the contract address if present
+func checkAbi(contract common.Address, abi []byte) error {
+	if len(abi) == 0 || len(abi) > MAX_ABI_SIZE {
+		return fmt.Errorf("abi size should be between 1 and %d", MAX_ABI_SIZE)
+	}
+	var header struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(abi, &header); err != nil {
+		return fmt.Errorf("invalid abi json: %v", err)
+	}
+	hash := strings.TrimPrefix(strings.ToLower(header.Hash), "0x")
+	if hash != "" && hash != contract.ToHexString() {
+		return fmt.Errorf("abi hash %s mismatch contract %s", header.Hash, contract.ToHexString())
+	}
+	return nil
+}
+
+func checkAuthority(native *native.NativeService, contract common.Address, keyNo uint64) error {
+	if cxt := native.ContextRef.CallingContext(); cxt != nil && cxt.ContractAddress == contract {
+		return nil
+	}
+	admin, err := auth.GetContractAdmin(native, contract)
+	if err != nil {
+		return fmt.Errorf("get contract admin error: %v", err)
+	}
+	if admin == nil {
+		return fmt.Errorf("contract admin is not set")
+	}
+	bf := new(bytes.Buffer)
+	if err := serialization.WriteVarBytes(bf, admin); err != nil {
+		return err
+	}
+	if err := utils.WriteVarUint(bf, keyNo); err != nil {
+		return err
+	}
+	ret, err := native.NativeCall(utils.OntIDContractAddress, "verifySignature", bf.Bytes())
+	if err != nil {
+		return fmt.Errorf("verify admin signature error: %v", err)
+	}
+	if valid, ok := ret.([]byte); !ok || !bytes.Equal(valid, utils.BYTE_TRUE) {
+		return fmt.Errorf("verify admin signature failed")
+	}
+	return nil
+}
+
+func pushEvent(native *native.NativeService, s interface{}) {
+	native.Notifications = append(native.Notifications, &event.NotifyEventInfo{
+		ContractAddress: native.ContextRef.CurrentContext().ContractAddress,
+		States:          s,
+	})
+}
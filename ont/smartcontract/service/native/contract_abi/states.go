@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract_abi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//SetAbiParam is the ABI of Contract in JSON. KeyNo is the key of contract admin ONT ID signing
+//the transaction, it is ignored when the contract sets its own ABI
+type SetAbiParam struct {
+	Contract common.Address
+	Abi      []byte
+	KeyNo    uint64
+}
+
+func (this *SetAbiParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Contract); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize contract error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Abi); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize abi error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize keyNo error: %v", err)
+	}
+	return nil
+}
+
+func (this *SetAbiParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Contract, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize contract error: %v", err)
+	}
+	if this.Abi, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize abi error: %v", err)
+	}
+	if this.KeyNo, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize keyNo error: %v", err)
+	}
+	return nil
+}
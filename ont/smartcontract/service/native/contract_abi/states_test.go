@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract_abi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAbiParam_Serialize(t *testing.T) {
+	param := &SetAbiParam{
+		Contract: common.AddressFromVmCode([]byte{1}),
+		Abi:      []byte(`{"functions":[]}`),
+		KeyNo:    1,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, param.Serialize(bf))
+	param2 := new(SetAbiParam)
+	assert.Nil(t, param2.Deserialize(bf))
+	assert.Equal(t, param, param2)
+}
+
+func TestCheckAbi(t *testing.T) {
+	contract := common.AddressFromVmCode([]byte{1})
+	assert.Nil(t, checkAbi(contract, []byte(`{"functions":[],"events":[]}`)))
+	assert.Nil(t, checkAbi(contract, []byte(`{"hash":"`+contract.ToHexString()+`","functions":[]}`)))
+	assert.Nil(t, checkAbi(contract, []byte(`{"hash":"0x`+contract.ToHexString()+`","functions":[]}`)))
+	assert.NotNil(t, checkAbi(contract, []byte(`{"hash":"0100000000000000000000000000000000000000"}`)))
+	assert.NotNil(t, checkAbi(contract, []byte(`[1, 2]`)))
+	assert.NotNil(t, checkAbi(contract, []byte(`{"functions":`)))
+	assert.NotNil(t, checkAbi(contract, nil))
+	assert.NotNil(t, checkAbi(contract, make([]byte, MAX_ABI_SIZE+1)))
+}
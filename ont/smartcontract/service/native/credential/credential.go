@@ -0,0 +1,243 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package credential
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	//function name
+	COMMIT     = "commit"
+	REVOKE     = "revoke"
+	REMOVE     = "remove"
+	GET_STATUS = "getStatus"
+
+	//key prefix
+	RECORD = "record"
+)
+
+//Init credential registry contract address
+func InitCredential() {
+	native.Contracts[utils.CredentialContractAddress] = RegisterCredentialContract
+}
+
+//Register methods of credential registry contract
+func RegisterCredentialContract(native *native.NativeService) {
+	native.Register(COMMIT, Commit)
+	native.Register(REVOKE, Revoke)
+	native.Register(REMOVE, Remove)
+	native.Register(GET_STATUS, GetStatus)
+}
+
+//Commit a credential hash issued by an ONT ID to a subject ONT ID, the claim id is unique in the
+//credentials of the issuer
+func Commit(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, block num is not reached for this func")
+	}
+	params := new(CommitParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, deserialize params error: %v", err)
+	}
+	if len(params.ClaimId) == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, claim id is empty")
+	}
+	if !account.VerifyID(string(params.Subject)) {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, invalid subject ONT ID")
+	}
+	if err := verifySignature(native, params.Issuer, params.KeyNo); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, %v", err)
+	}
+
+	record, err := getRecord(native, params.Issuer, params.ClaimId)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, %v", err)
+	}
+	if record != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, credential already exists")
+	}
+	record = &Record{
+		Issuer:       params.Issuer,
+		Subject:      params.Subject,
+		Status:       STATUS_COMMITTED,
+		CommitHeight: native.Height,
+	}
+	if err := putRecord(native, params.Issuer, params.ClaimId, record); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit, %v", err)
+	}
+	pushEvent(native, []interface{}{"Commit", hex.EncodeToString(params.ClaimId), string(params.Issuer),
+		string(params.Subject)})
+	return utils.BYTE_TRUE, nil
+}
+
+//Revoke a committed credential by its issuer
+func Revoke(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, block num is not reached for this func")
+	}
+	params := new(RevokeParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, deserialize params error: %v", err)
+	}
+	record, err := getRecord(native, params.Issuer, params.ClaimId)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, %v", err)
+	}
+	if record == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, credential not exists")
+	}
+	if record.Status != STATUS_COMMITTED {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, credential already revoked or removed")
+	}
+	if err := verifySignature(native, params.Issuer, params.KeyNo); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, %v", err)
+	}
+
+	record.Status = STATUS_REVOKED
+	record.RevokeHeight = native.Height
+	if err := putRecord(native, params.Issuer, params.ClaimId, record); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke, %v", err)
+	}
+	pushEvent(native, []interface{}{"Revoke", hex.EncodeToString(params.ClaimId), string(params.Issuer)})
+	return utils.BYTE_TRUE, nil
+}
+
+//Remove a credential from the registry by its subject, the record is kept with removed status, so that
+//the claim id can not be committed again. The record of a revoked credential is kept as revoked
+func Remove(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, block num is not reached for this func")
+	}
+	params := new(RemoveParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, deserialize params error: %v", err)
+	}
+	record, err := getRecord(native, params.Issuer, params.ClaimId)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, %v", err)
+	}
+	if record == nil || record.Status == STATUS_REMOVED {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, credential not exists")
+	}
+	if !bytes.Equal(record.Subject, params.Subject) {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, only subject can remove credential")
+	}
+	if record.Status == STATUS_REVOKED {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, revoked credential can not be removed")
+	}
+	if err := verifySignature(native, params.Subject, params.KeyNo); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, %v", err)
+	}
+
+	record.Status = STATUS_REMOVED
+	if err := putRecord(native, params.Issuer, params.ClaimId, record); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove, %v", err)
+	}
+	pushEvent(native, []interface{}{"Remove", hex.EncodeToString(params.ClaimId), string(params.Issuer),
+		string(params.Subject)})
+	return utils.BYTE_TRUE, nil
+}
+
+//GetStatus return the serialized record of a credential of the issuer, empty if not exists
+func GetStatus(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil, fmt.Errorf("getStatus, block num is not reached for this func")
+	}
+	params := new(StatusParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return nil, fmt.Errorf("getStatus, deserialize params error: %v", err)
+	}
+	item, err := utils.GetStorageItem(native, recordKey(params.Issuer, params.ClaimId))
+	if err != nil {
+		return nil, fmt.Errorf("getStatus, get record error: %v", err)
+	}
+	if item == nil {
+		return []byte{}, nil
+	}
+	return item.Value, nil
+}
+
+//recordKey return the storage key of credential claimId of issuer, claim ids of different issuers
+//are independent
+func recordKey(issuer, claimId []byte) []byte {
+	bf := new(bytes.Buffer)
+	serialization.WriteVarBytes(bf, issuer)
+	return utils.ConcatKey(utils.CredentialContractAddress, []byte(RECORD), bf.Bytes(), claimId)
+}
+
+func getRecord(native *native.NativeService, issuer, claimId []byte) (*Record, error) {
+	item, err := utils.GetStorageItem(native, recordKey(issuer, claimId))
+	if err != nil {
+		return nil, fmt.Errorf("get record error: %v", err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+	record := new(Record)
+	if err := record.Deserialize(bytes.NewBuffer(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize record error: %v", err)
+	}
+	return record, nil
+}
+
+func putRecord(native *native.NativeService, issuer, claimId []byte, record *Record) error {
+	bf := new(bytes.Buffer)
+	if err := record.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize record error: %v", err)
+	}
+	native.CacheDB.Put(recordKey(issuer, claimId), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+//verifySignature checks the ONT ID signed the transaction with its key keyNo
+func verifySignature(native *native.NativeService, ontID []byte, keyNo uint64) error {
+	bf := new(bytes.Buffer)
+	if err := serialization.WriteVarBytes(bf, ontID); err != nil {
+		return err
+	}
+	if err := utils.WriteVarUint(bf, keyNo); err != nil {
+		return err
+	}
+	ret, err := native.NativeCall(utils.OntIDContractAddress, "verifySignature", bf.Bytes())
+	if err != nil {
+		return fmt.Errorf("verify signature of %s error: %v", string(ontID), err)
+	}
+	valid, ok := ret.([]byte)
+	if !ok || !bytes.Equal(valid, utils.BYTE_TRUE) {
+		return fmt.Errorf("verify signature of %s failed", string(ontID))
+	}
+	return nil
+}
+
+func pushEvent(native *native.NativeService, s interface{}) {
+	native.Notifications = append(native.Notifications, &event.NotifyEventInfo{
+		ContractAddress: native.ContextRef.CurrentContext().ContractAddress,
+		States:          s,
+	})
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package credential
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	STATUS_COMMITTED byte = 1
+	STATUS_REVOKED   byte = 2
+	STATUS_REMOVED   byte = 3
+)
+
+//Record is the registry entry of a credential
+type Record struct {
+	Issuer       []byte
+	Subject      []byte
+	Status       byte
+	CommitHeight uint32
+	RevokeHeight uint32
+}
+
+func (this *Record) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.Issuer); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize issuer error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Subject); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize subject error: %v", err)
+	}
+	if err := serialization.WriteByte(w, this.Status); err != nil {
+		return fmt.Errorf("serialization.WriteByte, serialize status error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.CommitHeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize commit height error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.RevokeHeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize revoke height error: %v", err)
+	}
+	return nil
+}
+
+func (this *Record) Deserialize(r io.Reader) error {
+	var err error
+	if this.Issuer, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize issuer error: %v", err)
+	}
+	if this.Subject, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize subject error: %v", err)
+	}
+	if this.Status, err = serialization.ReadByte(r); err != nil {
+		return fmt.Errorf("serialization.ReadByte, deserialize status error: %v", err)
+	}
+	if this.CommitHeight, err = serialization.ReadUint32(r); err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize commit height error: %v", err)
+	}
+	if this.RevokeHeight, err = serialization.ReadUint32(r); err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize revoke height error: %v", err)
+	}
+	return nil
+}
+
+//CommitParam is the param of commit, the issuer signs with its key KeyNo
+type CommitParam struct {
+	ClaimId []byte
+	Issuer  []byte
+	KeyNo   uint64
+	Subject []byte
+}
+
+func (this *CommitParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.ClaimId); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize claim id error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Issuer); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize issuer error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize key no error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Subject); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize subject error: %v", err)
+	}
+	return nil
+}
+
+func (this *CommitParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.ClaimId, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize claim id error: %v", err)
+	}
+	if this.Issuer, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize issuer error: %v", err)
+	}
+	if this.KeyNo, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize key no error: %v", err)
+	}
+	if this.Subject, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize subject error: %v", err)
+	}
+	return nil
+}
+
+//RevokeParam is the param of revoke, the issuer signs with its key KeyNo
+type RevokeParam struct {
+	ClaimId []byte
+	Issuer  []byte
+	KeyNo   uint64
+}
+
+func (this *RevokeParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.ClaimId); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize claim id error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Issuer); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize issuer error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize key no error: %v", err)
+	}
+	return nil
+}
+
+func (this *RevokeParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.ClaimId, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize claim id error: %v", err)
+	}
+	if this.Issuer, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize issuer error: %v", err)
+	}
+	if this.KeyNo, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize key no error: %v", err)
+	}
+	return nil
+}
+
+//RemoveParam is the param of remove, the subject signs with its key KeyNo
+type RemoveParam struct {
+	ClaimId []byte
+	Issuer  []byte
+	Subject []byte
+	KeyNo   uint64
+}
+
+func (this *RemoveParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.ClaimId); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize claim id error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Issuer); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize issuer error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Subject); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize subject error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize key no error: %v", err)
+	}
+	return nil
+}
+
+func (this *RemoveParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.ClaimId, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize claim id error: %v", err)
+	}
+	if this.Issuer, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize issuer error: %v", err)
+	}
+	if this.Subject, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize subject error: %v", err)
+	}
+	if this.KeyNo, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize key no error: %v", err)
+	}
+	return nil
+}
+
+//StatusParam is the param of getStatus
+type StatusParam struct {
+	Issuer  []byte
+	ClaimId []byte
+}
+
+func (this *StatusParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.Issuer); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize issuer error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.ClaimId); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize claim id error: %v", err)
+	}
+	return nil
+}
+
+func (this *StatusParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Issuer, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize issuer error: %v", err)
+	}
+	if this.ClaimId, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize claim id error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package credential
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordSerialize(t *testing.T) {
+	record := &Record{
+		Issuer:       []byte("did:ont:issuer"),
+		Subject:      []byte("did:ont:subject"),
+		Status:       STATUS_REVOKED,
+		CommitHeight: 100,
+		RevokeHeight: 200,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, record.Serialize(bf))
+	record2 := new(Record)
+	assert.Nil(t, record2.Deserialize(bf))
+	assert.Equal(t, record, record2)
+}
+
+func TestParamSerialize(t *testing.T) {
+	commit := &CommitParam{
+		ClaimId: []byte{1, 2, 3},
+		Issuer:  []byte("did:ont:issuer"),
+		KeyNo:   1,
+		Subject: []byte("did:ont:subject"),
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, commit.Serialize(bf))
+	commit2 := new(CommitParam)
+	assert.Nil(t, commit2.Deserialize(bf))
+	assert.Equal(t, commit, commit2)
+
+	revoke := &RevokeParam{
+		ClaimId: []byte{1, 2, 3},
+		Issuer:  []byte("did:ont:issuer"),
+		KeyNo:   2,
+	}
+	bf.Reset()
+	assert.Nil(t, revoke.Serialize(bf))
+	revoke2 := new(RevokeParam)
+	assert.Nil(t, revoke2.Deserialize(bf))
+	assert.Equal(t, revoke, revoke2)
+
+	remove := &RemoveParam{
+		ClaimId: []byte{1, 2, 3},
+		Issuer:  []byte("did:ont:issuer"),
+		Subject: []byte("did:ont:subject"),
+		KeyNo:   3,
+	}
+	bf.Reset()
+	assert.Nil(t, remove.Serialize(bf))
+	remove2 := new(RemoveParam)
+	assert.Nil(t, remove2.Deserialize(bf))
+	assert.Equal(t, remove, remove2)
+
+	status := &StatusParam{
+		Issuer:  []byte("did:ont:issuer"),
+		ClaimId: []byte{1, 2, 3},
+	}
+	bf.Reset()
+	assert.Nil(t, status.Serialize(bf))
+	status2 := new(StatusParam)
+	assert.Nil(t, status2.Deserialize(bf))
+	assert.Equal(t, status, status2)
+}
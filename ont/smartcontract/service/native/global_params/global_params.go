@@ -24,6 +24,7 @@ import (
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
+	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -31,6 +32,9 @@ import (
 
 type paramType byte
 
+//ValueType is the type of param value declared in ParamSpec
+type ValueType byte
+
 const (
 	VERSION_CONTRACT_GLOBAL_PARAMS           = byte(0)
 	CURRENT_VALUE                  paramType = 0x00
@@ -42,6 +46,17 @@ const (
 	SET_GLOBAL_PARAM_NAME                    = "setGlobalParam"
 	GET_GLOBAL_PARAM_NAME                    = "getGlobalParam"
 	CREATE_SNAPSHOT_NAME                     = "createSnapshot"
+	SCHEDULE_GLOBAL_PARAM_NAME               = "scheduleGlobalParam"
+	GET_GLOBAL_PARAM_AT_NAME                 = "getGlobalParamAt"
+	GET_PARAM_HISTORY_NAME                   = "getParamHistory"
+	SET_PARAM_SPEC_NAME                      = "setParamSpec"
+	GET_PARAM_SPEC_NAME                      = "getParamSpec"
+)
+
+const (
+	VALUE_TYPE_STRING ValueType = 0x00
+	VALUE_TYPE_UINT64 ValueType = 0x01
+	VALUE_TYPE_BOOL   ValueType = 0x02
 )
 
 func InitGlobalParams() {
@@ -56,6 +71,11 @@ func RegisterParamContract(native *native.NativeService) {
 	native.Register(SET_GLOBAL_PARAM_NAME, SetGlobalParam)
 	native.Register(GET_GLOBAL_PARAM_NAME, GetGlobalParam)
 	native.Register(CREATE_SNAPSHOT_NAME, CreateSnapshot)
+	native.Register(SCHEDULE_GLOBAL_PARAM_NAME, ScheduleGlobalParam)
+	native.Register(GET_GLOBAL_PARAM_AT_NAME, GetGlobalParamAt)
+	native.Register(GET_PARAM_HISTORY_NAME, GetParamHistory)
+	native.Register(SET_PARAM_SPEC_NAME, SetParamSpec)
+	native.Register(GET_PARAM_SPEC_NAME, GetParamSpec)
 }
 
 func ParamInit(native *native.NativeService) ([]byte, error) {
@@ -166,6 +186,20 @@ func SetGlobalParam(native *native.NativeService) ([]byte, error) {
 	if len(params) == 0 {
 		return utils.BYTE_FALSE, errors.NewErr("set param, params is nil!")
 	}
+	if isUpgraded(native) {
+		if err := validateParams(native, contract, params); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "set param, invalid param!")
+		}
+		// params set by operator supersede their scheduled values
+		if err := applyPendingParams(native, contract); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"set param, apply pending param error!")
+		}
+		if err := cancelPendingParams(native, contract, params); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"set param, cancel pending param error!")
+		}
+	}
 	// read old param from database
 	storageParams, err := getStorageParam(native, generateParamKey(contract, PREPARE_VALUE))
 	if err != nil {
@@ -191,28 +225,200 @@ func GetGlobalParam(native *native.NativeService) ([]byte, error) {
 	if len(paramNameList) == 0 {
 		return utils.BYTE_FALSE, errors.NewErr("get param, required params is nil!")
 	}
-	// read from db
+	// read values effective at current height
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	var params Params
+	var err error
+	if isUpgraded(native) {
+		params, err = getParamsAt(native, contract, paramNameList, native.Height)
+	} else {
+		params, err = getCurrentParams(native, contract, paramNameList)
+	}
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "get param, read storage param error!")
+	}
+	result := new(bytes.Buffer)
+	err = params.Serialize(result)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "get param, serialize result error!")
+	}
+	return result.Bytes(), nil
+}
+
+//GetGlobalParamAt return value of params effective at the height of query
+func GetGlobalParamAt(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return utils.BYTE_FALSE, errors.NewErr("get param at height, block num is not reached for this func")
+	}
+	query := new(ParamQuery)
+	if err := query.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewErr("get param at height, deserialize failed!")
+	}
+	if len(query.Names) == 0 {
+		return utils.BYTE_FALSE, errors.NewErr("get param at height, required params is nil!")
+	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
-	storageParams, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+	params, err := getParamsAt(native, contract, query.Names, query.Height)
 	if err != nil {
 		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
-			"get param, read storage current param error!")
+			"get param at height, read storage param error!")
 	}
-	if len(storageParams) == 0 {
-		return utils.BYTE_FALSE, errors.NewErr("get param, there are no params!")
+	result := new(bytes.Buffer)
+	if err := params.Serialize(result); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"get param at height, serialize result error!")
 	}
-	params := new(Params)
-	for _, paramName := range paramNameList { // read param not in cache
-		if index, value := storageParams.GetParam(paramName); index >= 0 {
-			params.SetParam(value)
-		} else {
-			params.SetParam(Param{Key: paramName, Value: ""})
-		}
+	return result.Bytes(), nil
+}
+
+//GetParamHistory return all records of a param, including the scheduled ones
+func GetParamHistory(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return utils.BYTE_FALSE, errors.NewErr("get param history, block num is not reached for this func")
+	}
+	name, err := serialization.ReadString(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewErr("get param history, deserialize failed!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	history, err := getParamHistory(native, contract, name)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"get param history, read storage history error!")
+	}
+	pending, err := getPendingParams(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"get param history, read storage pending param error!")
+	}
+	if index, p := pending.GetPending(name); index >= 0 {
+		history.SetRecord(ParamRecord{Height: p.Height, Value: p.Value})
 	}
 	result := new(bytes.Buffer)
-	err = params.Serialize(result)
+	if err := history.Serialize(result); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"get param history, serialize result error!")
+	}
+	return result.Bytes(), nil
+}
+
+//ScheduleGlobalParam make params effective at a future height, so that transactions
+//in between are executed with the old values
+func ScheduleGlobalParam(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return utils.BYTE_FALSE, errors.NewErr("schedule param, block num is not reached for this func")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	operator, err := GetStorageRole(native, GenerateOperatorKey(contract))
+	if err != nil || operator == common.ADDRESS_EMPTY {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule param, operator doesn't exist, caused by %v", err)
+	}
+	if !native.ContextRef.CheckWitness(operator) {
+		return utils.BYTE_FALSE, errors.NewErr("schedule param, authentication failed!")
+	}
+	schedule := new(ScheduleParams)
+	if err := schedule.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewErr("schedule param, deserialize failed!")
+	}
+	if len(schedule.Params) == 0 {
+		return utils.BYTE_FALSE, errors.NewErr("schedule param, params is nil!")
+	}
+	if schedule.Height <= native.Height {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule param, height %d is not above current height %d",
+			schedule.Height, native.Height)
+	}
+	if err := validateParams(native, contract, schedule.Params); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "schedule param, invalid param!")
+	}
+	if err := applyPendingParams(native, contract); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"schedule param, apply pending param error!")
+	}
+	// scheduled values are kept pending until their height, a new schedule of the same param supersedes the old one
+	pending, err := getPendingParams(native, contract)
 	if err != nil {
-		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "get param, serialize result error!")
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"schedule param, read storage pending param error!")
+	}
+	for _, param := range schedule.Params {
+		pending.SetPending(PendingParam{Height: schedule.Height, Param: param})
+	}
+	putPendingParams(native, contract, pending)
+
+	NotifyScheduleParams(native, contract, SCHEDULE_GLOBAL_PARAM_NAME, schedule)
+	return utils.BYTE_TRUE, nil
+}
+
+//SetParamSpec declare the type and range of a param, it is only allowed for admin
+func SetParamSpec(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return utils.BYTE_FALSE, errors.NewErr("set param spec, block num is not reached for this func")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	admin, err := GetStorageRole(native, generateAdminKey(contract, false))
+	if err != nil || admin == common.ADDRESS_EMPTY {
+		return utils.BYTE_FALSE, fmt.Errorf("set param spec, admin doesn't exist, caused by %v", err)
+	}
+	if !native.ContextRef.CheckWitness(admin) {
+		return utils.BYTE_FALSE, errors.NewErr("set param spec, authentication failed!")
+	}
+	spec := new(ParamSpec)
+	if err := spec.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewErr("set param spec, deserialize failed!")
+	}
+	if err := spec.Check(); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	// the value in use must satisfy the new spec
+	params, err := getParamsAt(native, contract, ParamNameList{spec.Key}, native.Height)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "set param spec, read storage param error!")
+	}
+	if value := params[0].Value; value != "" {
+		if err := spec.Validate(value); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"set param spec, current value doesn't match!")
+		}
+	}
+	pending, err := getPendingParams(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+			"set param spec, read storage pending param error!")
+	}
+	if index, p := pending.GetPending(spec.Key); index >= 0 {
+		if err := spec.Validate(p.Value); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"set param spec, scheduled value doesn't match!")
+		}
+	}
+	bf := new(bytes.Buffer)
+	if err := spec.Serialize(bf); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "set param spec, serialize spec error!")
+	}
+	native.CacheDB.Put(generateSpecKey(contract, spec.Key), (&cstates.StorageItem{Value: bf.Bytes()}).ToArray())
+	return utils.BYTE_TRUE, nil
+}
+
+//GetParamSpec return the spec of a param, empty if it is not declared
+func GetParamSpec(native *native.NativeService) ([]byte, error) {
+	if !isUpgraded(native) {
+		return utils.BYTE_FALSE, errors.NewErr("get param spec, block num is not reached for this func")
+	}
+	name, err := serialization.ReadString(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewErr("get param spec, deserialize failed!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	spec, err := getParamSpec(native, contract, name)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "get param spec, read storage spec error!")
+	}
+	if spec == nil {
+		return []byte{}, nil
+	}
+	result := new(bytes.Buffer)
+	if err := spec.Serialize(result); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "get param spec, serialize spec error!")
 	}
 	return result.Bytes(), nil
 }
@@ -226,6 +432,13 @@ func CreateSnapshot(native *native.NativeService) ([]byte, error) {
 	if !native.ContextRef.CheckWitness(operator) {
 		return utils.BYTE_FALSE, errors.NewErr("create snapshot, authentication failed!")
 	}
+	upgraded := isUpgraded(native)
+	if upgraded {
+		if err := applyPendingParams(native, contract); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"create snapshot, apply pending param error!")
+		}
+	}
 	// read prepare param
 	prepareParam, err := getStorageParam(native, generateParamKey(contract, PREPARE_VALUE))
 	if err != nil {
@@ -235,9 +448,67 @@ func CreateSnapshot(native *native.NativeService) ([]byte, error) {
 	if len(prepareParam) == 0 {
 		return utils.BYTE_FALSE, errors.NewErr("create snapshot, prepare param doesn't exist!")
 	}
+	if upgraded {
+		currentParam, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+		if err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"create snapshot, read storage current param error!")
+		}
+		changed := Params{}
+		for _, param := range prepareParam {
+			if index, value := currentParam.GetParam(param.Key); index < 0 || value.Value != param.Value {
+				changed.SetParam(param)
+			}
+		}
+		if err := validateParams(native, contract, changed); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "create snapshot, invalid param!")
+		}
+		if err := recordParams(native, contract, currentParam, changed, native.Height); err != nil {
+			return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode,
+				"create snapshot, record param history error!")
+		}
+	}
 	// set prepare value to current value, make it effective
 	native.CacheDB.Put(generateParamKey(contract, CURRENT_VALUE), getParamStorageItem(prepareParam).ToArray())
 
 	NotifyParamChange(native, contract, CREATE_SNAPSHOT_NAME, prepareParam)
 	return utils.BYTE_TRUE, nil
 }
+
+//SetParams update params and make them effective at once, it is called by the
+//governance contract to execute passed proposals.
+func SetParams(native *native.NativeService, params Params) error {
+	if len(params) == 0 {
+		return errors.NewErr("set params, params is nil!")
+	}
+	contract := utils.ParamContractAddress
+	if err := validateParams(native, contract, params); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "set params, invalid param!")
+	}
+	if err := applyPendingParams(native, contract); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "set params, apply pending param error!")
+	}
+	if err := cancelPendingParams(native, contract, params); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "set params, cancel pending param error!")
+	}
+	current, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "set params, read storage current param error!")
+	}
+	if err := recordParams(native, contract, current, params, native.Height); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "set params, record param history error!")
+	}
+	for _, valueType := range []paramType{PREPARE_VALUE, CURRENT_VALUE} {
+		storageParams, err := getStorageParam(native, generateParamKey(contract, valueType))
+		if err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "set params, read storage param error!")
+		}
+		for _, param := range params {
+			storageParams.SetParam(param)
+		}
+		native.CacheDB.Put(generateParamKey(contract, valueType), getParamStorageItem(storageParams).ToArray())
+	}
+
+	NotifyParamChange(native, contract, SET_GLOBAL_PARAM_NAME, params)
+	return nil
+}
@@ -20,9 +20,15 @@ package global_params
 
 import (
 	"bytes"
+	"io"
 	"strconv"
 	"testing"
 
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +69,156 @@ func TestParamNameList_Serialize_Deserialize(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, nameList, deserializeNameList)
 }
+
+func TestParamHistory(t *testing.T) {
+	history := ParamHistory{}
+	_, ok := history.ValueAt(100)
+	assert.False(t, ok)
+
+	history.SetRecord(ParamRecord{Height: 200, Value: "2"})
+	history.SetRecord(ParamRecord{Height: 0, Value: "0"})
+	history.SetRecord(ParamRecord{Height: 100, Value: "1"})
+	history.SetRecord(ParamRecord{Height: 200, Value: "3"})
+	assert.Equal(t, ParamHistory{{0, "0"}, {100, "1"}, {200, "3"}}, history)
+
+	for height, expect := range map[uint32]string{0: "0", 99: "0", 100: "1", 199: "1", 200: "3", 1000: "3"} {
+		value, ok := history.ValueAt(height)
+		assert.True(t, ok)
+		assert.Equal(t, expect, value, "height %d", height)
+	}
+
+	bf := new(bytes.Buffer)
+	assert.Nil(t, history.Serialize(bf))
+	history2 := ParamHistory{}
+	assert.Nil(t, history2.Deserialize(bf))
+	assert.Equal(t, history, history2)
+}
+
+func TestParamSpec(t *testing.T) {
+	spec := &ParamSpec{Key: "gasPrice", Type: VALUE_TYPE_UINT64, Min: 500, Max: 10000}
+	assert.Nil(t, spec.Check())
+	assert.Nil(t, spec.Validate("500"))
+	assert.Nil(t, spec.Validate("10000"))
+	assert.NotNil(t, spec.Validate("499"))
+	assert.NotNil(t, spec.Validate("10001"))
+	assert.NotNil(t, spec.Validate("abc"))
+
+	bf := new(bytes.Buffer)
+	assert.Nil(t, spec.Serialize(bf))
+	spec2 := new(ParamSpec)
+	assert.Nil(t, spec2.Deserialize(bf))
+	assert.Equal(t, spec, spec2)
+
+	spec = &ParamSpec{Key: "flag", Type: VALUE_TYPE_BOOL}
+	assert.Nil(t, spec.Validate("true"))
+	assert.NotNil(t, spec.Validate("1"))
+
+	spec = &ParamSpec{Key: "name", Type: VALUE_TYPE_STRING, Min: 1, Max: 4}
+	assert.Nil(t, spec.Validate("abcd"))
+	assert.NotNil(t, spec.Validate(""))
+	assert.NotNil(t, spec.Validate("abcde"))
+
+	assert.NotNil(t, (&ParamSpec{Key: "a", Type: 3}).Check())
+	assert.NotNil(t, (&ParamSpec{Key: "a", Type: VALUE_TYPE_UINT64, Min: 2, Max: 1}).Check())
+}
+
+func TestScheduleParams_Serialize_Deserialize(t *testing.T) {
+	schedule := &ScheduleParams{Height: 12345, Params: Params{{"gasPrice", "1000"}, {"SHA256", "20"}}}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, schedule.Serialize(bf))
+	schedule2 := new(ScheduleParams)
+	assert.Nil(t, schedule2.Deserialize(bf))
+	assert.Equal(t, schedule, schedule2)
+
+	query := &ParamQuery{Height: 100, Names: ParamNameList{"gasPrice"}}
+	bf.Reset()
+	assert.Nil(t, query.Serialize(bf))
+	query2 := new(ParamQuery)
+	assert.Nil(t, query2.Deserialize(bf))
+	assert.Equal(t, query, query2)
+}
+
+//newTestService return a native service with params, signed by the operator
+func newTestService(t *testing.T, params Params) *native.NativeService {
+	contract := utils.ParamContractAddress
+	operator := common.Address{1}
+	native := testsuite.NewNativeService(t, contract, 0, operator)
+	native.CacheDB.Put(GenerateOperatorKey(contract), getRoleStorageItem(operator).ToArray())
+	native.CacheDB.Put(generateParamKey(contract, PREPARE_VALUE), getParamStorageItem(params).ToArray())
+	native.CacheDB.Put(generateParamKey(contract, CURRENT_VALUE), getParamStorageItem(params).ToArray())
+	return native
+}
+
+func invoke(native *native.NativeService, height uint32, method native.Handler, input interface {
+	Serialize(w io.Writer) error
+}) error {
+	bf := new(bytes.Buffer)
+	if err := input.Serialize(bf); err != nil {
+		return err
+	}
+	native.Height = height
+	native.Input = bf.Bytes()
+	_, err := method(native)
+	return err
+}
+
+func paramAt(t *testing.T, native *native.NativeService, name string, height uint32) string {
+	params, err := getParamsAt(native, utils.ParamContractAddress, ParamNameList{name}, height)
+	assert.Nil(t, err)
+	return params[0].Value
+}
+
+func TestScheduleGlobalParam(t *testing.T) {
+	h := config.GetNativeUpgradeHeight()
+	native := newTestService(t, Params{{"gasPrice", "500"}})
+	contract := utils.ParamContractAddress
+	schedule := &ScheduleParams{Height: h + 200, Params: Params{{"gasPrice", "1000"}}}
+	if h > 0 {
+		assert.NotNil(t, invoke(native, h-1, ScheduleGlobalParam, schedule))
+	}
+	assert.NotNil(t, invoke(native, h+200, ScheduleGlobalParam, schedule))
+	assert.Nil(t, invoke(native, h+100, ScheduleGlobalParam, schedule))
+
+	//the scheduled value is pending until its height
+	current, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+	assert.Nil(t, err)
+	assert.Equal(t, Params{{"gasPrice", "500"}}, current)
+	assert.Equal(t, "500", paramAt(t, native, "gasPrice", h+199))
+	assert.Equal(t, "1000", paramAt(t, native, "gasPrice", h+200))
+
+	//a new schedule supersedes the old one
+	schedule = &ScheduleParams{Height: h + 300, Params: Params{{"gasPrice", "2000"}}}
+	assert.Nil(t, invoke(native, h+150, ScheduleGlobalParam, schedule))
+	assert.Equal(t, "500", paramAt(t, native, "gasPrice", h+299))
+	assert.Equal(t, "2000", paramAt(t, native, "gasPrice", h+300))
+
+	//it is applied at its height by the next change
+	assert.Nil(t, invoke(native, h+320, SetGlobalParam, &Params{{"SHA256", "10"}}))
+	pending, err := getPendingParams(native, contract)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(pending))
+	history, err := getParamHistory(native, contract, "gasPrice")
+	assert.Nil(t, err)
+	assert.Equal(t, ParamHistory{{0, "500"}, {h + 300, "2000"}}, history)
+	assert.Equal(t, "2000", paramAt(t, native, "gasPrice", h+320))
+}
+
+func TestScheduleGlobalParam_CancelledBySet(t *testing.T) {
+	h := config.GetNativeUpgradeHeight()
+	native := newTestService(t, Params{{"gasPrice", "500"}})
+	contract := utils.ParamContractAddress
+	schedule := &ScheduleParams{Height: h + 300, Params: Params{{"gasPrice", "1000"}}}
+	assert.Nil(t, invoke(native, h+100, ScheduleGlobalParam, schedule))
+
+	//a later set by operator wins over the older schedule
+	assert.Nil(t, invoke(native, h+200, SetGlobalParam, &Params{{"gasPrice", "700"}}))
+	native.Input = nil
+	_, err := CreateSnapshot(native)
+	assert.Nil(t, err)
+	pending, err := getPendingParams(native, contract)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(pending))
+	assert.Equal(t, "500", paramAt(t, native, "gasPrice", h+199))
+	assert.Equal(t, "700", paramAt(t, native, "gasPrice", h+200))
+	assert.Equal(t, "700", paramAt(t, native, "gasPrice", h+300))
+}
@@ -20,6 +20,8 @@ package global_params
 
 import (
 	"io"
+	"math"
+	"strconv"
 
 	"fmt"
 	"github.com/ontio/ontology/common/serialization"
@@ -118,3 +120,293 @@ func (nameList *ParamNameList) Deserialize(r io.Reader) error {
 	}
 	return nil
 }
+
+//ParamRecord is the value of a param effective from Height
+type ParamRecord struct {
+	Height uint32
+	Value  string
+}
+
+//ParamHistory is the records of a param sorted by height, it may contain
+//records scheduled at future height
+type ParamHistory []ParamRecord
+
+//ValueAt return the value effective at height, false if there is no record at or below height
+func (history *ParamHistory) ValueAt(height uint32) (string, bool) {
+	for i := len(*history) - 1; i >= 0; i-- {
+		if (*history)[i].Height <= height {
+			return (*history)[i].Value, true
+		}
+	}
+	return "", false
+}
+
+//SetRecord insert record keeping history sorted, record at the same height is replaced
+func (history *ParamHistory) SetRecord(record ParamRecord) {
+	for index, r := range *history {
+		if r.Height == record.Height {
+			(*history)[index] = record
+			return
+		}
+		if r.Height > record.Height {
+			*history = append(*history, ParamRecord{})
+			copy((*history)[index+1:], (*history)[index:])
+			(*history)[index] = record
+			return
+		}
+	}
+	*history = append(*history, record)
+}
+
+func (history *ParamHistory) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(len(*history))); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param history, serialize history length error!")
+	}
+	for _, record := range *history {
+		if err := utils.WriteVarUint(w, uint64(record.Height)); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "param history, serialize record height error!")
+		}
+		if err := serialization.WriteString(w, record.Value); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "param history, serialize record value error!")
+		}
+	}
+	return nil
+}
+
+func (history *ParamHistory) Deserialize(r io.Reader) error {
+	num, err := utils.ReadVarUint(r)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param history, deserialize history length error!")
+	}
+	for i := uint64(0); i < num; i++ {
+		record := ParamRecord{}
+		if record.Height, err = readHeight(r); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "param history, deserialize record height error!")
+		}
+		if record.Value, err = serialization.ReadString(r); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "param history, deserialize record value error!")
+		}
+		*history = append(*history, record)
+	}
+	return nil
+}
+
+//ParamSpec restricts the values of a param, Min and Max bound the number for
+//VALUE_TYPE_UINT64 and the length for VALUE_TYPE_STRING, Max 0 means no upper bound
+type ParamSpec struct {
+	Key  string
+	Type ValueType
+	Min  uint64
+	Max  uint64
+}
+
+func (spec *ParamSpec) Check() error {
+	switch spec.Type {
+	case VALUE_TYPE_STRING, VALUE_TYPE_UINT64, VALUE_TYPE_BOOL:
+	default:
+		return fmt.Errorf("param spec, unknown value type %d", spec.Type)
+	}
+	if spec.Key == "" {
+		return errors.NewErr("param spec, key is empty!")
+	}
+	if spec.Max != 0 && spec.Max < spec.Min {
+		return fmt.Errorf("param spec, max %d is less than min %d", spec.Max, spec.Min)
+	}
+	return nil
+}
+
+//Validate check whether value matches the spec
+func (spec *ParamSpec) Validate(value string) error {
+	var n uint64
+	switch spec.Type {
+	case VALUE_TYPE_UINT64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("param %s, value %s is not uint64", spec.Key, value)
+		}
+		n = v
+	case VALUE_TYPE_BOOL:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("param %s, value %s is not bool", spec.Key, value)
+		}
+		return nil
+	default:
+		n = uint64(len(value))
+	}
+	if n < spec.Min || (spec.Max != 0 && n > spec.Max) {
+		return fmt.Errorf("param %s, value %s out of range [%d, %d]", spec.Key, value, spec.Min, spec.Max)
+	}
+	return nil
+}
+
+func (spec *ParamSpec) Serialize(w io.Writer) error {
+	if err := serialization.WriteString(w, spec.Key); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, serialize key error!")
+	}
+	if err := utils.WriteVarUint(w, uint64(spec.Type)); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, serialize type error!")
+	}
+	if err := utils.WriteVarUint(w, spec.Min); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, serialize min error!")
+	}
+	if err := utils.WriteVarUint(w, spec.Max); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, serialize max error!")
+	}
+	return nil
+}
+
+func (spec *ParamSpec) Deserialize(r io.Reader) error {
+	var err error
+	if spec.Key, err = serialization.ReadString(r); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, deserialize key error!")
+	}
+	valueType, err := utils.ReadVarUint(r)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, deserialize type error!")
+	}
+	if valueType > math.MaxUint8 {
+		return fmt.Errorf("param spec, invalid value type %d", valueType)
+	}
+	spec.Type = ValueType(valueType)
+	if spec.Min, err = utils.ReadVarUint(r); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, deserialize min error!")
+	}
+	if spec.Max, err = utils.ReadVarUint(r); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param spec, deserialize max error!")
+	}
+	return nil
+}
+
+//ScheduleParams is the input of scheduleGlobalParam, Params become effective at Height
+type ScheduleParams struct {
+	Height uint32
+	Params Params
+}
+
+func (schedule *ScheduleParams) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(schedule.Height)); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "schedule params, serialize height error!")
+	}
+	return schedule.Params.Serialize(w)
+}
+
+func (schedule *ScheduleParams) Deserialize(r io.Reader) error {
+	var err error
+	if schedule.Height, err = readHeight(r); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "schedule params, deserialize height error!")
+	}
+	return schedule.Params.Deserialize(r)
+}
+
+//PendingParam is a scheduled param waiting for its height
+type PendingParam struct {
+	Height uint32
+	Param
+}
+
+//PendingParams is sorted by height, each param has one pending value at most
+type PendingParams []PendingParam
+
+//GetPending return the index and the pending value of key, -1 if it is not pending
+func (pending *PendingParams) GetPending(key string) (int, PendingParam) {
+	for index, p := range *pending {
+		if p.Key == key {
+			return index, p
+		}
+	}
+	return -1, PendingParam{}
+}
+
+//SetPending supersede the pending value of the same key and keep pending sorted by height
+func (pending *PendingParams) SetPending(p PendingParam) {
+	pending.RemovePending(p.Key)
+	for index, r := range *pending {
+		if r.Height > p.Height {
+			*pending = append(*pending, PendingParam{})
+			copy((*pending)[index+1:], (*pending)[index:])
+			(*pending)[index] = p
+			return
+		}
+	}
+	*pending = append(*pending, p)
+}
+
+//RemovePending cancel the pending value of key, false if it is not pending
+func (pending *PendingParams) RemovePending(key string) bool {
+	if index, _ := pending.GetPending(key); index >= 0 {
+		*pending = append((*pending)[:index], (*pending)[index+1:]...)
+		return true
+	}
+	return false
+}
+
+func (pending *PendingParams) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(len(*pending))); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, serialize length error!")
+	}
+	for _, p := range *pending {
+		if err := utils.WriteVarUint(w, uint64(p.Height)); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, serialize height error!")
+		}
+		if err := serialization.WriteString(w, p.Key); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, serialize key error!")
+		}
+		if err := serialization.WriteString(w, p.Value); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, serialize value error!")
+		}
+	}
+	return nil
+}
+
+func (pending *PendingParams) Deserialize(r io.Reader) error {
+	num, err := utils.ReadVarUint(r)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, deserialize length error!")
+	}
+	for i := uint64(0); i < num; i++ {
+		p := PendingParam{}
+		if p.Height, err = readHeight(r); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, deserialize height error!")
+		}
+		if p.Key, err = serialization.ReadString(r); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, deserialize key error!")
+		}
+		if p.Value, err = serialization.ReadString(r); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "pending params, deserialize value error!")
+		}
+		*pending = append(*pending, p)
+	}
+	return nil
+}
+
+//ParamQuery is the input of getGlobalParamAt
+type ParamQuery struct {
+	Height uint32
+	Names  ParamNameList
+}
+
+func (query *ParamQuery) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(query.Height)); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param query, serialize height error!")
+	}
+	return query.Names.Serialize(w)
+}
+
+func (query *ParamQuery) Deserialize(r io.Reader) error {
+	var err error
+	if query.Height, err = readHeight(r); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "param query, deserialize height error!")
+	}
+	return query.Names.Deserialize(r)
+}
+
+func readHeight(r io.Reader) (uint32, error) {
+	height, err := utils.ReadVarUint(r)
+	if err != nil {
+		return 0, err
+	}
+	if height > math.MaxUint32 {
+		return 0, fmt.Errorf("height %d overflow", height)
+	}
+	return uint32(height), nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -34,8 +35,16 @@ const (
 	TRANSFER = "transfer"
 	ADMIN    = "admin"
 	OPERATOR = "operator"
+	HISTORY  = "paramHistory"
+	SPEC     = "paramSpec"
+	PENDING  = "pendingParam"
 )
 
+//isUpgraded return true if scheduled params, param specs and param history are enabled at the current height
+func isUpgraded(native *native.NativeService) bool {
+	return native.Height >= config.GetNativeUpgradeHeight()
+}
+
 func getRoleStorageItem(role common.Address) *cstates.StorageItem {
 	bf := new(bytes.Buffer)
 	utils.WriteAddress(bf, role)
@@ -77,6 +86,216 @@ func getStorageParam(native *native.NativeService, key []byte) (Params, error) {
 	return params, err
 }
 
+func generateHistoryKey(contract common.Address, name string) []byte {
+	key := append(contract[:], HISTORY...)
+	return append(key, name...)
+}
+
+func generateSpecKey(contract common.Address, name string) []byte {
+	key := append(contract[:], SPEC...)
+	return append(key, name...)
+}
+
+func getParamHistory(native *native.NativeService, contract common.Address, name string) (ParamHistory, error) {
+	item, err := utils.GetStorageItem(native, generateHistoryKey(contract, name))
+	history := ParamHistory{}
+	if err != nil || item == nil {
+		return history, err
+	}
+	err = history.Deserialize(bytes.NewBuffer(item.Value))
+	return history, err
+}
+
+func putParamHistory(native *native.NativeService, contract common.Address, name string, history ParamHistory) {
+	bf := new(bytes.Buffer)
+	history.Serialize(bf)
+	native.CacheDB.Put(generateHistoryKey(contract, name), (&cstates.StorageItem{Value: bf.Bytes()}).ToArray())
+}
+
+//recordParams add records of params effective from height. current is the params before
+//the change, its value is kept as the record at height 0 when a param gets its first record.
+//records above height are dropped, the latest change always wins
+func recordParams(native *native.NativeService, contract common.Address, current Params, params Params,
+	height uint32) error {
+	for _, param := range params {
+		history, err := getParamHistory(native, contract, param.Key)
+		if err != nil {
+			return err
+		}
+		if len(history) == 0 {
+			if index, value := current.GetParam(param.Key); index >= 0 && height > 0 {
+				history.SetRecord(ParamRecord{Height: 0, Value: value.Value})
+			}
+		}
+		for len(history) > 0 && history[len(history)-1].Height > height {
+			history = history[:len(history)-1]
+		}
+		history.SetRecord(ParamRecord{Height: height, Value: param.Value})
+		putParamHistory(native, contract, param.Key, history)
+	}
+	return nil
+}
+
+func generatePendingKey(contract common.Address) []byte {
+	return append(contract[:], PENDING...)
+}
+
+func getPendingParams(native *native.NativeService, contract common.Address) (PendingParams, error) {
+	item, err := utils.GetStorageItem(native, generatePendingKey(contract))
+	pending := PendingParams{}
+	if err != nil || item == nil {
+		return pending, err
+	}
+	err = pending.Deserialize(bytes.NewBuffer(item.Value))
+	return pending, err
+}
+
+func putPendingParams(native *native.NativeService, contract common.Address, pending PendingParams) {
+	if len(pending) == 0 {
+		native.CacheDB.Delete(generatePendingKey(contract))
+		return
+	}
+	bf := new(bytes.Buffer)
+	pending.Serialize(bf)
+	native.CacheDB.Put(generatePendingKey(contract), (&cstates.StorageItem{Value: bf.Bytes()}).ToArray())
+}
+
+//applyPendingParams move the pending params whose height has arrived to prepare and current value,
+//and record them at their scheduled height. getParamsAt already reads them from the pending list,
+//so they are effective at their height even if nothing is written to the contract then
+func applyPendingParams(native *native.NativeService, contract common.Address) error {
+	pending, err := getPendingParams(native, contract)
+	if err != nil {
+		return err
+	}
+	i := 0
+	for ; i < len(pending) && pending[i].Height <= native.Height; i++ {
+		current, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+		if err != nil {
+			return err
+		}
+		params := Params{pending[i].Param}
+		if err := recordParams(native, contract, current, params, pending[i].Height); err != nil {
+			return err
+		}
+		for _, valueType := range []paramType{PREPARE_VALUE, CURRENT_VALUE} {
+			storageParams, err := getStorageParam(native, generateParamKey(contract, valueType))
+			if err != nil {
+				return err
+			}
+			storageParams.SetParam(pending[i].Param)
+			native.CacheDB.Put(generateParamKey(contract, valueType), getParamStorageItem(storageParams).ToArray())
+		}
+	}
+	if i > 0 {
+		putPendingParams(native, contract, pending[i:])
+	}
+	return nil
+}
+
+//cancelPendingParams cancel the pending values of params set by operator
+func cancelPendingParams(native *native.NativeService, contract common.Address, params Params) error {
+	pending, err := getPendingParams(native, contract)
+	if err != nil {
+		return err
+	}
+	cancelled := false
+	for _, param := range params {
+		if pending.RemovePending(param.Key) {
+			cancelled = true
+		}
+	}
+	if cancelled {
+		putPendingParams(native, contract, pending)
+	}
+	return nil
+}
+
+//getCurrentParams return the current value of params, as they are read before the upgrade height
+func getCurrentParams(native *native.NativeService, contract common.Address, names ParamNameList) (Params, error) {
+	current, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nil, errors.NewErr("there are no params!")
+	}
+	params := Params{}
+	for _, name := range names {
+		if index, value := current.GetParam(name); index >= 0 {
+			params.SetParam(value)
+		} else {
+			params.SetParam(Param{Key: name, Value: ""})
+		}
+	}
+	return params, nil
+}
+
+//getParamsAt return value of names effective at height, pending params whose height has arrived
+//override the history, params without history fall back to current value
+func getParamsAt(native *native.NativeService, contract common.Address, names ParamNameList,
+	height uint32) (Params, error) {
+	current, err := getStorageParam(native, generateParamKey(contract, CURRENT_VALUE))
+	if err != nil {
+		return nil, err
+	}
+	if len(current) == 0 {
+		return nil, errors.NewErr("there are no params!")
+	}
+	pending, err := getPendingParams(native, contract)
+	if err != nil {
+		return nil, err
+	}
+	params := Params{}
+	for _, name := range names {
+		if index, p := pending.GetPending(name); index >= 0 && p.Height <= height {
+			params.SetParam(p.Param)
+			continue
+		}
+		history, err := getParamHistory(native, contract, name)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := history.ValueAt(height); ok {
+			params.SetParam(Param{Key: name, Value: value})
+		} else if index, value := current.GetParam(name); index >= 0 {
+			params.SetParam(value)
+		} else {
+			params.SetParam(Param{Key: name, Value: ""})
+		}
+	}
+	return params, nil
+}
+
+func getParamSpec(native *native.NativeService, contract common.Address, name string) (*ParamSpec, error) {
+	item, err := utils.GetStorageItem(native, generateSpecKey(contract, name))
+	if err != nil || item == nil {
+		return nil, err
+	}
+	spec := new(ParamSpec)
+	if err := spec.Deserialize(bytes.NewBuffer(item.Value)); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+//validateParams check params against their specs, params without spec are accepted
+func validateParams(native *native.NativeService, contract common.Address, params Params) error {
+	for _, param := range params {
+		spec, err := getParamSpec(native, contract, param.Key)
+		if err != nil {
+			return err
+		}
+		if spec == nil {
+			continue
+		}
+		if err := spec.Validate(param.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GetStorageRole(native *native.NativeService, key []byte) (common.Address, error) {
 	item, err := utils.GetStorageItem(native, key)
 	var role common.Address
@@ -127,3 +346,20 @@ func NotifyParamChange(native *native.NativeService, contract common.Address, fu
 			States:          []interface{}{functionName, paramsString},
 		})
 }
+
+func NotifyScheduleParams(native *native.NativeService, contract common.Address, functionName string,
+	schedule *ScheduleParams) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	paramsString := ""
+	for _, param := range schedule.Params {
+		paramsString += param.Key + "," + param.Value + ";"
+	}
+	paramsString = paramsString[:len(paramsString)-1]
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{functionName, schedule.Height, paramsString},
+		})
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	ctypes "github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsProposalPair(t *testing.T) {
+	sysTxs := []common.Uint256{{1}, {2}}
+	block := &ctypes.Header{Height: 10, Timestamp: 100, TransactionsRoot: common.Uint256{3}}
+	empty := func(hashes ...common.Uint256) *ctypes.Header {
+		return &ctypes.Header{Height: 10, Timestamp: 100, TransactionsRoot: common.ComputeMerkleRoot(hashes)}
+	}
+
+	assert.True(t, IsProposalPair(block, empty(), sysTxs))
+	assert.True(t, IsProposalPair(block, empty(sysTxs[0]), sysTxs))
+	assert.True(t, IsProposalPair(empty(sysTxs[1]), block, sysTxs))
+	assert.True(t, IsProposalPair(block, empty(sysTxs...), sysTxs))
+	//system transactions out of order or with user transaction
+	assert.False(t, IsProposalPair(block, empty(sysTxs[1], sysTxs[0]), sysTxs))
+	assert.False(t, IsProposalPair(block, empty(sysTxs[0], common.Uint256{3}), sysTxs))
+	//different metadata of proposal
+	other := empty()
+	other.Timestamp = 101
+	assert.False(t, IsProposalPair(block, other, sysTxs))
+}
@@ -75,6 +75,9 @@ const (
 	ADD_INIT_POS                     = "addInitPos"
 	REDUCE_INIT_POS                  = "reduceInitPos"
 	SET_PROMISE_POS                  = "setPromisePos"
+	REPORT_EQUIVOCATION              = "reportEquivocation"
+	CREATE_PROPOSAL                  = "createProposal"
+	VOTE_PROPOSAL                    = "voteProposal"
 
 	//key prefix
 	GLOBAL_PARAM      = "globalParam"
@@ -93,6 +96,12 @@ const (
 	SPLIT_FEE_ADDRESS = "splitFeeAddress"
 	PROMISE_POS       = "promisePos"
 	PRE_CONFIG        = "preConfig"
+	PROPOSAL_INDEX    = "proposalIndex"
+	PROPOSAL          = "proposal"
+	PROPOSAL_VOTE     = "proposalVote"
+	PROPOSAL_VOTER    = "proposalVoter"
+	ACTIVE_PROPOSALS  = "activeProposals"
+	SPLIT_FEE_HISTORY = "splitFeeHistory"
 
 	//global
 	PRECISE           = 1000000
@@ -100,6 +109,37 @@ const (
 	NEW_VERSION_BLOCK = 414100
 )
 
+const (
+	//equivocation evidence type
+	DOUBLE_PROPOSAL uint64 = iota + 1
+	DOUBLE_ENDORSEMENT
+
+	//an honest node signs at most three blocks of other proposers at one height:
+	//the endorsed block, the endorsed empty block and the committed block
+	MAX_HONEST_SIGNATURES = 3
+)
+
+const (
+	//proposal type
+	PROPOSAL_CONFIG uint64 = iota + 1
+	PROPOSAL_GLOBAL_PARAM
+	PROPOSAL_GLOBAL_PARAM2
+	PROPOSAL_SPLIT_CURVE
+	PROPOSAL_PARAMS
+
+	//proposal status
+	PROPOSAL_VOTING   uint8 = 0
+	PROPOSAL_PASSED   uint8 = 1
+	PROPOSAL_REJECTED uint8 = 2
+	PROPOSAL_FAILED   uint8 = 3
+
+	//a proposal is tallied at the first commitDpos after it has been open for this many views,
+	//it passes if at least 1/3 of total stake voted and at least 2/3 of voted stake approved
+	PROPOSAL_VOTE_VIEWS  = 2
+	MAX_ACTIVE_PROPOSALS = 16
+	MAX_PROPOSAL_VOTERS  = 128
+)
+
 // candidate fee must >= 1 ONG
 var MIN_CANDIDATE_FEE = uint64(math.Pow(10, constants.ONG_DECIMALS))
 var AUTHORIZE_INFO_POOL = []byte{118, 111, 116, 101, 73, 110, 102, 111, 80, 111, 111, 108}
@@ -148,6 +188,9 @@ func RegisterGovernanceContract(native *native.NativeService) {
 	native.Register(UPDATE_SPLIT_CURVE, UpdateSplitCurve)
 	native.Register(TRANSFER_PENALTY, TransferPenalty)
 	native.Register(SET_PROMISE_POS, SetPromisePos)
+	native.Register(REPORT_EQUIVOCATION, ReportEquivocation)
+	native.Register(CREATE_PROPOSAL, CreateProposal)
+	native.Register(VOTE_PROPOSAL, VoteProposal)
 }
 
 //Init governance contract, include vbft config, global param and ontid admin.
@@ -615,46 +658,52 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
 	}
-	commit := false
-	for _, peerPubkey := range params.PeerPubkeyList {
-		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
-		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
-		}
-		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
-		if !ok {
-			return utils.BYTE_FALSE, fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
-		}
+	err = blackPeers(native, contract, view, peerPoolMap, params.PeerPubkeyList)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("blackPeers, black peers error: %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
 
-		blackListItem := &BlackListItem{
-			PeerPubkey: peerPoolItem.PeerPubkey,
-			Address:    peerPoolItem.Address,
-			InitPos:    peerPoolItem.InitPos,
-		}
-		bf := new(bytes.Buffer)
-		if err := blackListItem.Serialize(bf); err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("serialize, serialize blackListItem error: %v", err)
-		}
-		//put peer into black list
-		native.CacheDB.Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(bf.Bytes()))
-		//change peerPool status
-		if peerPoolItem.Status == ConsensusStatus {
-			commit = true
-		}
-		peerPoolItem.Status = BlackStatus
-		peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
+//Report a node which signed conflicting blocks at the same height, the evidence is verified on chain.
+//Proven node is put into black list as blackNode does, its stake will be punished and can be transferred by transferPenalty.
+func ReportEquivocation(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("block num is not reached for this func")
 	}
-	err = putPeerPoolMap(native, contract, view, peerPoolMap)
+	params := new(ReportEquivocationParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, contract params deserialize error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putPeerPoolMap, put peerPoolMap error: %v", err)
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[params.PeerPubkey]
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, peerPubkey is not in peerPoolMap")
+	}
+	if peerPoolItem.Status == BlackStatus {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, peerPubkey is already in black list")
 	}
 
-	//commitDpos
-	if commit {
-		err = executeCommitDpos(native, contract)
-		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
-		}
+	//check evidence
+	err = verifyEquivocation(native, params, peerPoolItem)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("verifyEquivocation, verify evidence error: %v", err)
+	}
+
+	err = blackPeers(native, contract, view, peerPoolMap, []string{params.PeerPubkey})
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("blackPeers, black peers error: %v", err)
 	}
 	return utils.BYTE_TRUE, nil
 }
@@ -1016,26 +1065,36 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
-	//get globalParam
-	globalParam, err := getGlobalParam(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
-	}
-
 	configuration := new(Configuration)
 	if err := configuration.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize configuration error: %v", err)
 	}
 
+	err = updateConfig(native, contract, configuration)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//check the configuration and set it to take effect at next view
+func updateConfig(native *native.NativeService, contract common.Address, configuration *Configuration) error {
+	//get globalParam
+	globalParam, err := getGlobalParam(native, contract)
+	if err != nil {
+		return fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
+	}
+
 	//get current view
 	view, err := GetView(native, contract)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+		return fmt.Errorf("getView, get view error: %v", err)
 	}
 	//get peerPoolMap
 	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
 	}
 	candidateNum := 0
 	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
@@ -1046,31 +1105,31 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 
 	//check the configuration
 	if configuration.C == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. C can not be 0 in config")
+		return fmt.Errorf("updateConfig. C can not be 0 in config")
 	}
 	if int(configuration.K) > candidateNum {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. K can not be larger than num of candidate peer in config")
+		return fmt.Errorf("updateConfig. K can not be larger than num of candidate peer in config")
 	}
 	if configuration.L < 16*configuration.K || configuration.L%configuration.K != 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. L can not be less than 16*K and K must be times of L in config")
+		return fmt.Errorf("updateConfig. L can not be less than 16*K and K must be times of L in config")
 	}
 	if configuration.K < 2*configuration.C+1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. K can not be less than 2*C+1 in config")
+		return fmt.Errorf("updateConfig. K can not be less than 2*C+1 in config")
 	}
 	if 4*configuration.K > globalParam.CandidateNum {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. 4*K can not be more than candidateNum")
+		return fmt.Errorf("updateConfig. 4*K can not be more than candidateNum")
 	}
 	if configuration.N < configuration.K || configuration.K < 7 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. config not match N >= K >= 7")
+		return fmt.Errorf("updateConfig. config not match N >= K >= 7")
 	}
 	if configuration.BlockMsgDelay < 5000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
+		return fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
 	}
 	if configuration.HashMsgDelay < 5000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
+		return fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
 	}
 	if configuration.PeerHandshakeTimeout < 10 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
+		return fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
 	}
 
 	preConfig := &PreConfig{
@@ -1079,10 +1138,10 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 	}
 	err = putPreConfig(native, contract, preConfig)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putPreConfig, put preConfig error: %v", err)
+		return fmt.Errorf("putPreConfig, put preConfig error: %v", err)
 	}
 
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Update global params of this governance contract
@@ -1101,49 +1160,56 @@ func UpdateGlobalParam(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
-	// get config
-	config, err := getConfig(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
-	}
-
 	globalParam := new(GlobalParam)
 	if err := globalParam.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize globalParam error: %v", err)
 	}
 
+	err = updateGlobalParam(native, contract, globalParam)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//check and put global params
+func updateGlobalParam(native *native.NativeService, contract common.Address, globalParam *GlobalParam) error {
+	// get config
+	config, err := getConfig(native, contract)
+	if err != nil {
+		return fmt.Errorf("getConfig, get config error: %v", err)
+	}
+
 	//check the globalParam
 	if (globalParam.A + globalParam.B) != 100 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. A + B must equal to 100")
+		return fmt.Errorf("updateGlobalParam. A + B must equal to 100")
 	}
 	if globalParam.Yita == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. Yita must > 0")
+		return fmt.Errorf("updateGlobalParam. Yita must > 0")
 	}
 	if globalParam.Penalty > 100 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. Penalty must <= 100")
+		return fmt.Errorf("updateGlobalParam. Penalty must <= 100")
 	}
 	if globalParam.PosLimit < 1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. PosLimit must >= 1")
+		return fmt.Errorf("updateGlobalParam. PosLimit must >= 1")
 	}
 	if globalParam.CandidateNum < 4*config.K {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. CandidateNum must >= 4*K")
+		return fmt.Errorf("updateGlobalParam. CandidateNum must >= 4*K")
 	}
 	if globalParam.CandidateFee != 0 && globalParam.CandidateFee < MIN_CANDIDATE_FEE {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. CandidateFee must >= %d", MIN_CANDIDATE_FEE)
+		return fmt.Errorf("updateGlobalParam. CandidateFee must >= %d", MIN_CANDIDATE_FEE)
 	}
 	err = putGlobalParam(native, contract, globalParam)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putGlobalParam, put globalParam error: %v", err)
+		return fmt.Errorf("putGlobalParam, put globalParam error: %v", err)
 	}
 
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Update global params of this governance contract
 func UpdateGlobalParam2(native *native.NativeService) ([]byte, error) {
-	if native.Height < NEW_VERSION_BLOCK {
-		return utils.BYTE_FALSE, fmt.Errorf("block num is not reached for this func")
-	}
 	// get admin from database
 	adminAddress, err := global_params.GetStorageRole(native,
 		global_params.GenerateOperatorKey(utils.ParamContractAddress))
@@ -1163,25 +1229,39 @@ func UpdateGlobalParam2(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize globalParam2 error: %v", err)
 	}
 
+	err = updateGlobalParam2(native, contract, globalParam2)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//check and put global params 2
+func updateGlobalParam2(native *native.NativeService, contract common.Address, globalParam2 *GlobalParam2) error {
+	if native.Height < NEW_VERSION_BLOCK {
+		return fmt.Errorf("block num is not reached for this func")
+	}
+
 	//check the globalParam
 	if globalParam2.MinAuthorizePos == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("globalParam2.MinAuthorizePos can not be 0")
+		return fmt.Errorf("globalParam2.MinAuthorizePos can not be 0")
 	}
 	// get config
 	config, err := getConfig(native, contract)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
+		return fmt.Errorf("getConfig, get config error: %v", err)
 	}
 	if globalParam2.CandidateFeeSplitNum < config.K {
-		return utils.BYTE_FALSE, fmt.Errorf("globalParam2.CandidateFeeSplitNum can not be less than config.K")
+		return fmt.Errorf("globalParam2.CandidateFeeSplitNum can not be less than config.K")
 	}
 
 	err = putGlobalParam2(native, contract, globalParam2)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putGlobalParam2, put globalParam2 error: %v", err)
+		return fmt.Errorf("putGlobalParam2, put globalParam2 error: %v", err)
 	}
 
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Update split curve
@@ -1203,6 +1283,11 @@ func UpdateSplitCurve(native *native.NativeService) ([]byte, error) {
 	if err := splitCurve.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize splitCurve error: %v", err)
 	}
+	if native.Height >= config.GetNativeUpgradeHeight() {
+		if err := validateSplitCurve(splitCurve); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("validateSplitCurve, %v", err)
+		}
+	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
 	err = putSplitCurve(native, contract, splitCurve)
@@ -1627,3 +1712,159 @@ func SetPromisePos(native *native.NativeService) ([]byte, error) {
 
 	return utils.BYTE_TRUE, nil
 }
+
+//Create a parameter change proposal, only owner of candidate or consensus node can create proposal
+func CreateProposal(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, block num is not reached for this func")
+	}
+	params := new(CreateProposalParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize createProposalParam error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("validateOwner, checkWitness error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[params.PeerPubkey]
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, peerPubkey is not in peerPoolMap")
+	}
+	if peerPoolItem.Address != params.Address {
+		return utils.BYTE_FALSE, fmt.Errorf("address is not peer owner")
+	}
+	if peerPoolItem.Status != CandidateStatus && peerPoolItem.Status != ConsensusStatus {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, peer status is not candidate or consensus")
+	}
+
+	//check the proposal content
+	if _, err := validateProposalContent(params.Type, params.Content); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("validateProposalContent error: %v", err)
+	}
+
+	activeProposals, err := getActiveProposals(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getActiveProposals, get activeProposals error: %v", err)
+	}
+	if len(activeProposals.Ids) >= MAX_ACTIVE_PROPOSALS {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, num of proposals in voting reach the limit %d", MAX_ACTIVE_PROPOSALS)
+	}
+
+	proposalIndex, err := getProposalIndex(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalIndex, get proposalIndex error: %v", err)
+	}
+	proposalIndex = proposalIndex + 1
+
+	proposal := &Proposal{
+		Id:        proposalIndex,
+		Proposer:  params.PeerPubkey,
+		Type:      params.Type,
+		Content:   params.Content,
+		StartView: view,
+		EndView:   view + PROPOSAL_VOTE_VIEWS,
+		Status:    PROPOSAL_VOTING,
+	}
+	err = putProposal(native, contract, proposal)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposal, put proposal error: %v", err)
+	}
+	err = putProposalIndex(native, contract, proposalIndex)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposalIndex, put proposalIndex error: %v", err)
+	}
+	activeProposals.Ids = append(activeProposals.Ids, proposalIndex)
+	err = putActiveProposals(native, contract, activeProposals)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putActiveProposals, put activeProposals error: %v", err)
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//Vote for a proposal, vote is weighted by total stake of address in this contract
+func VoteProposal(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, block num is not reached for this func")
+	}
+	params := new(VoteProposalParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize voteProposalParam error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("validateOwner, checkWitness error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	proposal, err := GetProposal(native, contract, params.Id)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposal, get proposal error: %v", err)
+	}
+	if proposal.Status != PROPOSAL_VOTING {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, proposal is not in voting")
+	}
+	if proposal.VoteCount >= MAX_PROPOSAL_VOTERS {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, num of voters reach the limit %d", MAX_PROPOSAL_VOTERS)
+	}
+
+	proposalVote, err := getProposalVote(native, contract, params.Id, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalVote, get proposalVote error: %v", err)
+	}
+	if proposalVote != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, address has already voted")
+	}
+
+	totalStake, err := getTotalStake(native, contract, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getTotalStake, get totalStake error: %v", err)
+	}
+	if totalStake.Stake == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, address has no stake")
+	}
+
+	proposalVote = &ProposalVote{
+		Address: params.Address,
+		Approve: params.Approve,
+		Stake:   totalStake.Stake,
+	}
+	err = putProposalVote(native, contract, params.Id, proposalVote)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposalVote, put proposalVote error: %v", err)
+	}
+
+	if params.Approve {
+		proposal.ApproveStake = proposal.ApproveStake + totalStake.Stake
+	} else {
+		proposal.RejectStake = proposal.RejectStake + totalStake.Stake
+	}
+	err = putProposalVoter(native, contract, params.Id, proposal.VoteCount, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposalVoter, put proposalVoter error: %v", err)
+	}
+	proposal.VoteCount = proposal.VoteCount + 1
+	err = putProposal(native, contract, proposal)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposal, put proposal error: %v", err)
+	}
+
+	return utils.BYTE_TRUE, nil
+}
@@ -24,10 +24,15 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/signature"
 	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/global_params"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 )
 
@@ -664,6 +669,125 @@ func withdrawPenaltyStake(native *native.NativeService, contract common.Address,
 	return nil
 }
 
+func blackPeers(native *native.NativeService, contract common.Address, view uint32, peerPoolMap *PeerPoolMap, peerPubkeyList []string) error {
+	commit := false
+	for _, peerPubkey := range peerPubkeyList {
+		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
+		if err != nil {
+			return fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+		}
+		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
+		if !ok {
+			return fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
+		}
+
+		blackListItem := &BlackListItem{
+			PeerPubkey: peerPoolItem.PeerPubkey,
+			Address:    peerPoolItem.Address,
+			InitPos:    peerPoolItem.InitPos,
+		}
+		bf := new(bytes.Buffer)
+		if err := blackListItem.Serialize(bf); err != nil {
+			return fmt.Errorf("serialize, serialize blackListItem error: %v", err)
+		}
+		//put peer into black list
+		native.CacheDB.Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(bf.Bytes()))
+		//change peerPool status
+		if peerPoolItem.Status == ConsensusStatus {
+			commit = true
+		}
+		peerPoolItem.Status = BlackStatus
+		peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
+	}
+	err := putPeerPoolMap(native, contract, view, peerPoolMap)
+	if err != nil {
+		return fmt.Errorf("putPeerPoolMap, put peerPoolMap error: %v", err)
+	}
+
+	//commitDpos
+	if commit {
+		err = executeCommitDpos(native, contract)
+		if err != nil {
+			return fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
+		}
+	}
+	return nil
+}
+
+//verifyEquivocation check all headers are at the same height and signed by the reported peer.
+//Double proposal: two blocks proposed by the peer which are not the block and empty block of one proposal.
+//Double endorsement: more blocks of other proposers signed by the peer than an honest node could sign.
+func verifyEquivocation(native *native.NativeService, params *ReportEquivocationParam, peerPoolItem *PeerPoolItem) error {
+	if len(params.Headers) < 2 || len(params.Headers) != len(params.Sigs) {
+		return fmt.Errorf("invalid evidence, headers: %d, sigs: %d", len(params.Headers), len(params.Sigs))
+	}
+	pubkeyBytes, err := hex.DecodeString(params.PeerPubkey)
+	if err != nil {
+		return fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	pubkey, err := keypair.DeserializePublicKey(pubkeyBytes)
+	if err != nil {
+		return fmt.Errorf("keypair.DeserializePublicKey, deserialize peerPubkey error: %v", err)
+	}
+
+	height := params.Headers[0].Height
+	if height > native.Height {
+		return fmt.Errorf("evidence height %d is higher than current height %d", height, native.Height)
+	}
+	hashes := make(map[common.Uint256]bool)
+	proposers := make([]uint32, 0, len(params.Headers))
+	for i, header := range params.Headers {
+		if header.Height != height {
+			return fmt.Errorf("evidence headers are not at the same height")
+		}
+		hash := header.Hash()
+		if hashes[hash] {
+			return fmt.Errorf("duplicated evidence header %s", hash.ToHexString())
+		}
+		hashes[hash] = true
+		if err := signature.Verify(pubkey, hash[:], params.Sigs[i]); err != nil {
+			return fmt.Errorf("signature.Verify, verify signature of header %s error: %v", hash.ToHexString(), err)
+		}
+		info, err := vconfig.VbftBlock(header)
+		if err != nil {
+			return fmt.Errorf("vconfig.VbftBlock, get block info error: %v", err)
+		}
+		proposers = append(proposers, info.Proposer)
+	}
+
+	switch params.EvidenceType {
+	case DOUBLE_PROPOSAL:
+		if len(params.Headers) != 2 {
+			return fmt.Errorf("double proposal evidence needs 2 headers")
+		}
+		for _, proposer := range proposers {
+			if proposer != peerPoolItem.Index {
+				return fmt.Errorf("block is not proposed by peer %d", peerPoolItem.Index)
+			}
+		}
+		h1, h2 := params.Headers[0], params.Headers[1]
+		sysTxs, err := systemTxHashes(height)
+		if err != nil {
+			return fmt.Errorf("systemTxHashes, get system transactions error: %v", err)
+		}
+		if IsProposalPair(h1, h2, sysTxs) {
+			return fmt.Errorf("headers are block and empty block of one proposal")
+		}
+	case DOUBLE_ENDORSEMENT:
+		if len(params.Headers) <= MAX_HONEST_SIGNATURES {
+			return fmt.Errorf("double endorsement evidence needs more than %d headers", MAX_HONEST_SIGNATURES)
+		}
+		for _, proposer := range proposers {
+			if proposer == peerPoolItem.Index {
+				return fmt.Errorf("block is proposed by peer %d itself", peerPoolItem.Index)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown evidence type %d", params.EvidenceType)
+	}
+	return nil
+}
+
 func executeCommitDpos(native *native.NativeService, contract common.Address) error {
 	governanceView, err := GetGovernanceView(native, contract)
 	if err != nil {
@@ -675,6 +799,12 @@ func executeCommitDpos(native *native.NativeService, contract common.Address) er
 	//get current view
 	view := governanceView.View
 
+	//tally proposals before config is updated
+	err = executeProposals(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("executeProposals error: %v", err)
+	}
+
 	if view <= NEW_VERSION_VIEW {
 		err = executeCommitDpos1(native, contract)
 		if err != nil {
@@ -1261,6 +1391,28 @@ func executeCommitDpos2(native *native.NativeService, contract common.Address) e
 		return fmt.Errorf("putSplitFee, put splitFee error: %v", err)
 	}
 
+	err = recordSplitFee(native, contract, view, splitSum)
+	if err != nil {
+		return fmt.Errorf("recordSplitFee, record splitFee error: %v", err)
+	}
+
+	return nil
+}
+
+//record split fee of this view for query, it is recorded since the native upgrade height
+func recordSplitFee(native *native.NativeService, contract common.Address, view uint32, amount uint64) error {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil
+	}
+	splitFeeRecord := &SplitFeeRecord{
+		View:   view,
+		Height: native.Height,
+		Amount: amount,
+	}
+	err := putSplitFeeRecord(native, contract, splitFeeRecord)
+	if err != nil {
+		return fmt.Errorf("putSplitFeeRecord, put splitFeeRecord error: %v", err)
+	}
 	return nil
 }
 
@@ -1307,3 +1459,172 @@ func splitNodeFee(native *native.NativeService, contract common.Address, peerPub
 	}
 	return nil
 }
+
+func validateSplitCurve(splitCurve *SplitCurve) error {
+	if len(splitCurve.Yi) != 101 {
+		return fmt.Errorf("length of split curve != 101")
+	}
+	return nil
+}
+
+//deserialize and check the parameter change of a proposal
+func validateProposalContent(proposalType uint64, content []byte) (interface{}, error) {
+	switch proposalType {
+	case PROPOSAL_CONFIG:
+		configuration := new(Configuration)
+		if err := configuration.Deserialize(bytes.NewBuffer(content)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize configuration error: %v", err)
+		}
+		return configuration, nil
+	case PROPOSAL_GLOBAL_PARAM:
+		globalParam := new(GlobalParam)
+		if err := globalParam.Deserialize(bytes.NewBuffer(content)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize globalParam error: %v", err)
+		}
+		return globalParam, nil
+	case PROPOSAL_GLOBAL_PARAM2:
+		globalParam2 := new(GlobalParam2)
+		if err := globalParam2.Deserialize(bytes.NewBuffer(content)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize globalParam2 error: %v", err)
+		}
+		return globalParam2, nil
+	case PROPOSAL_SPLIT_CURVE:
+		splitCurve := new(SplitCurve)
+		if err := splitCurve.Deserialize(bytes.NewBuffer(content)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize splitCurve error: %v", err)
+		}
+		if err := validateSplitCurve(splitCurve); err != nil {
+			return nil, fmt.Errorf("validateSplitCurve, %v", err)
+		}
+		return splitCurve, nil
+	case PROPOSAL_PARAMS:
+		params := new(global_params.Params)
+		if err := params.Deserialize(bytes.NewBuffer(content)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize params error: %v", err)
+		}
+		if len(*params) == 0 {
+			return nil, fmt.Errorf("params is empty")
+		}
+		return params, nil
+	default:
+		return nil, fmt.Errorf("unknown proposal type %d", proposalType)
+	}
+}
+
+func executeProposal(native *native.NativeService, contract common.Address, proposal *Proposal) error {
+	change, err := validateProposalContent(proposal.Type, proposal.Content)
+	if err != nil {
+		return err
+	}
+	switch v := change.(type) {
+	case *Configuration:
+		return updateConfig(native, contract, v)
+	case *GlobalParam:
+		return updateGlobalParam(native, contract, v)
+	case *GlobalParam2:
+		return updateGlobalParam2(native, contract, v)
+	case *SplitCurve:
+		return putSplitCurve(native, contract, v)
+	case *global_params.Params:
+		return global_params.SetParams(native, *v)
+	default:
+		return fmt.Errorf("unknown change of proposal type %d", proposal.Type)
+	}
+}
+
+//tally proposals whose voting period ends at this view, and execute the passed ones
+func executeProposals(native *native.NativeService, contract common.Address, view uint32) error {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil
+	}
+	activeProposals, err := getActiveProposals(native, contract)
+	if err != nil {
+		return fmt.Errorf("getActiveProposals, get activeProposals error: %v", err)
+	}
+	if len(activeProposals.Ids) == 0 {
+		return nil
+	}
+
+	//total stake of candidate and consensus peers
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	var totalStake uint64
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
+			totalStake = totalStake + peerPoolItem.InitPos + peerPoolItem.TotalPos
+		}
+	}
+
+	ids := make([]uint32, 0)
+	for _, id := range activeProposals.Ids {
+		proposal, err := GetProposal(native, contract, id)
+		if err != nil {
+			return fmt.Errorf("getProposal, get proposal error: %v", err)
+		}
+		if proposal.EndView > view {
+			ids = append(ids, id)
+			continue
+		}
+
+		//stake withdrawn after voting is not counted
+		var approveStake, rejectStake uint64
+		for i := uint32(0); i < proposal.VoteCount; i++ {
+			voter, err := getProposalVoter(native, contract, id, i)
+			if err != nil {
+				return fmt.Errorf("getProposalVoter, get proposalVoter error: %v", err)
+			}
+			proposalVote, err := getProposalVote(native, contract, id, voter)
+			if err != nil {
+				return fmt.Errorf("getProposalVote, get proposalVote error: %v", err)
+			}
+			if proposalVote == nil {
+				return fmt.Errorf("executeProposals, proposalVote of voter is not exist")
+			}
+			voterStake, err := getTotalStake(native, contract, voter)
+			if err != nil {
+				return fmt.Errorf("getTotalStake, get totalStake error: %v", err)
+			}
+			stake := proposalVote.Stake
+			if voterStake.Stake < stake {
+				stake = voterStake.Stake
+			}
+			if proposalVote.Approve {
+				approveStake = approveStake + stake
+			} else {
+				rejectStake = rejectStake + stake
+			}
+		}
+		proposal.ApproveStake = approveStake
+		proposal.RejectStake = rejectStake
+
+		votedStake := approveStake + rejectStake
+		if totalStake != 0 && 3*votedStake >= totalStake && 3*approveStake >= 2*votedStake {
+			proposal.Status = PROPOSAL_PASSED
+			//a failed proposal should not block commitDpos, its partial changes are reverted
+			snapshot := native.CacheDB.Snapshot()
+			notifications := len(native.Notifications)
+			if err := executeProposal(native, contract, proposal); err != nil {
+				native.CacheDB.RevertToSnapshot(snapshot)
+				native.Notifications = native.Notifications[:notifications]
+				proposal.Status = PROPOSAL_FAILED
+			} else {
+				native.CacheDB.DiscardSnapshot(snapshot)
+			}
+		} else {
+			proposal.Status = PROPOSAL_REJECTED
+		}
+		err = putProposal(native, contract, proposal)
+		if err != nil {
+			return fmt.Errorf("putProposal, put proposal error: %v", err)
+		}
+	}
+
+	activeProposals.Ids = ids
+	err = putActiveProposals(native, contract, activeProposals)
+	if err != nil {
+		return fmt.Errorf("putActiveProposals, put activeProposals error: %v", err)
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 )
 
@@ -992,3 +993,160 @@ func (this *ChangeInitPosParam) Deserialize(r io.Reader) error {
 	this.Pos = uint32(pos)
 	return nil
 }
+
+type ReportEquivocationParam struct {
+	PeerPubkey   string
+	EvidenceType uint64
+	Headers      []*types.Header
+	Sigs         [][]byte
+}
+
+func (this *ReportEquivocationParam) Serialize(w io.Writer) error {
+	if len(this.Headers) != len(this.Sigs) {
+		return fmt.Errorf("headers length %d not equal to sigs length %d", len(this.Headers), len(this.Sigs))
+	}
+	if err := serialization.WriteString(w, this.PeerPubkey); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize peerPubkey error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.EvidenceType); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize evidenceType error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(len(this.Headers))); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize headers length error: %v", err)
+	}
+	for i, header := range this.Headers {
+		if err := serialization.WriteVarBytes(w, header.ToArray()); err != nil {
+			return fmt.Errorf("serialization.WriteVarBytes, serialize header error: %v", err)
+		}
+		if err := serialization.WriteVarBytes(w, this.Sigs[i]); err != nil {
+			return fmt.Errorf("serialization.WriteVarBytes, serialize sig error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (this *ReportEquivocationParam) Deserialize(r io.Reader) error {
+	peerPubkey, err := serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize peerPubkey error: %v", err)
+	}
+	evidenceType, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize evidenceType error: %v", err)
+	}
+	n, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize headers length error: %v", err)
+	}
+	headers := make([]*types.Header, 0)
+	sigs := make([][]byte, 0)
+	for i := 0; uint64(i) < n; i++ {
+		raw, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadVarBytes, deserialize header error: %v", err)
+		}
+		header, err := types.HeaderFromRawBytes(raw)
+		if err != nil {
+			return fmt.Errorf("types.HeaderFromRawBytes, deserialize header error: %v", err)
+		}
+		sig, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadVarBytes, deserialize sig error: %v", err)
+		}
+		headers = append(headers, header)
+		sigs = append(sigs, sig)
+	}
+	this.PeerPubkey = peerPubkey
+	this.EvidenceType = evidenceType
+	this.Headers = headers
+	this.Sigs = sigs
+	return nil
+}
+
+type CreateProposalParam struct {
+	PeerPubkey string
+	Address    common.Address
+	Type       uint64
+	Content    []byte
+}
+
+func (this *CreateProposalParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteString(w, this.PeerPubkey); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize peerPubkey error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Address[:]); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize address error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Type); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize type error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Content); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize content error: %v", err)
+	}
+	return nil
+}
+
+func (this *CreateProposalParam) Deserialize(r io.Reader) error {
+	peerPubkey, err := serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize peerPubkey error: %v", err)
+	}
+	address, err := utils.ReadAddress(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize address error: %v", err)
+	}
+	typ, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize type error: %v", err)
+	}
+	content, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize content error: %v", err)
+	}
+	this.PeerPubkey = peerPubkey
+	this.Address = address
+	this.Type = typ
+	this.Content = content
+	return nil
+}
+
+type VoteProposalParam struct {
+	Address common.Address
+	Id      uint32
+	Approve bool
+}
+
+func (this *VoteProposalParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.Address[:]); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize address error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.Id)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	if err := serialization.WriteBool(w, this.Approve); err != nil {
+		return fmt.Errorf("serialization.WriteBool, serialize approve error: %v", err)
+	}
+	return nil
+}
+
+func (this *VoteProposalParam) Deserialize(r io.Reader) error {
+	address, err := utils.ReadAddress(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize address error: %v", err)
+	}
+	id, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	if id > math.MaxUint32 {
+		return fmt.Errorf("id larger than max of uint32")
+	}
+	approve, err := serialization.ReadBool(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadBool, deserialize approve error: %v", err)
+	}
+	this.Address = address
+	this.Id = uint32(id)
+	this.Approve = approve
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+//newTestService return a governance native service at height, signed by the test voters
+func newTestService(t *testing.T, height uint32) *native.NativeService {
+	return testsuite.NewNativeService(t, utils.GovernanceContractAddress, height,
+		common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4})
+}
+
+func testSplitCurve(n int) []byte {
+	bf := new(bytes.Buffer)
+	utils.WriteVarUint(bf, uint64(n))
+	for i := 0; i < n; i++ {
+		utils.WriteVarUint(bf, uint64(i))
+	}
+	return bf.Bytes()
+}
+
+func vote(native *native.NativeService, address common.Address, id uint32, approve bool) error {
+	params := &VoteProposalParam{Address: address, Id: id, Approve: approve}
+	bf := new(bytes.Buffer)
+	if err := params.Serialize(bf); err != nil {
+		return err
+	}
+	native.Input = bf.Bytes()
+	_, err := VoteProposal(native)
+	return err
+}
+
+func TestProposal_Serialize(t *testing.T) {
+	proposal := &Proposal{
+		Id:           3,
+		Proposer:     "02abcd",
+		Type:         PROPOSAL_SPLIT_CURVE,
+		Content:      testSplitCurve(101),
+		StartView:    10,
+		EndView:      12,
+		Status:       PROPOSAL_PASSED,
+		ApproveStake: 1000,
+		RejectStake:  10,
+		VoteCount:    5,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, proposal.Serialize(bf))
+	proposal2 := new(Proposal)
+	assert.Nil(t, proposal2.Deserialize(bf))
+	assert.Equal(t, proposal, proposal2)
+}
+
+func TestValidateProposalContent(t *testing.T) {
+	change, err := validateProposalContent(PROPOSAL_SPLIT_CURVE, testSplitCurve(101))
+	assert.Nil(t, err)
+	assert.Equal(t, 101, len(change.(*SplitCurve).Yi))
+	_, err = validateProposalContent(PROPOSAL_SPLIT_CURVE, testSplitCurve(100))
+	assert.NotNil(t, err)
+	_, err = validateProposalContent(PROPOSAL_CONFIG, []byte{1})
+	assert.NotNil(t, err)
+	_, err = validateProposalContent(PROPOSAL_PARAMS+1, testSplitCurve(101))
+	assert.NotNil(t, err)
+}
+
+func TestProposal_VoteAndTally(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	native := newTestService(t, upgrade-1)
+	contract := utils.GovernanceContractAddress
+	view := uint32(5)
+
+	_, err := CreateProposal(native)
+	assert.NotNil(t, err)
+	native.Height = upgrade
+
+	peerPoolMap := &PeerPoolMap{PeerPoolMap: map[string]*PeerPoolItem{
+		"peer": {PeerPubkey: "peer", Status: ConsensusStatus, InitPos: 100, TotalPos: 200},
+	}}
+	assert.Nil(t, putPeerPoolMap(native, contract, view, peerPoolMap))
+	voters := []common.Address{{1}, {2}, {3}}
+	for i, voter := range voters {
+		assert.Nil(t, putTotalStake(native, contract, &TotalStake{Address: voter, Stake: uint64(i+1) * 50}))
+	}
+	proposal := &Proposal{
+		Id:      1,
+		Type:    PROPOSAL_SPLIT_CURVE,
+		Content: testSplitCurve(101),
+		EndView: view,
+		Status:  PROPOSAL_VOTING,
+	}
+	assert.Nil(t, putProposal(native, contract, proposal))
+	assert.Nil(t, putActiveProposals(native, contract, &ActiveProposals{Ids: []uint32{1}}))
+
+	assert.Nil(t, vote(native, voters[0], 1, false))
+	assert.Nil(t, vote(native, voters[1], 1, true))
+	assert.Nil(t, vote(native, voters[2], 1, true))
+	assert.NotNil(t, vote(native, voters[2], 1, true))
+	assert.NotNil(t, vote(native, common.Address{4}, 1, true))
+	proposal, err = GetProposal(native, contract, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), proposal.VoteCount)
+	assert.Equal(t, uint64(250), proposal.ApproveStake)
+	assert.Equal(t, uint64(50), proposal.RejectStake)
+
+	//stake withdrawn after voting is not counted
+	assert.Nil(t, putTotalStake(native, contract, &TotalStake{Address: voters[2], Stake: 0}))
+	assert.Nil(t, executeProposals(native, contract, view))
+	proposal, err = GetProposal(native, contract, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, PROPOSAL_PASSED, proposal.Status)
+	assert.Equal(t, uint64(100), proposal.ApproveStake)
+	splitCurve, err := getSplitCurve(native, contract)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(100), splitCurve.Yi[100])
+	activeProposals, err := getActiveProposals(native, contract)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(activeProposals.Ids))
+}
+
+func TestProposal_MaxVoters(t *testing.T) {
+	native := newTestService(t, config.GetNativeUpgradeHeight())
+	contract := utils.GovernanceContractAddress
+	voter := common.Address{1}
+	assert.Nil(t, putTotalStake(native, contract, &TotalStake{Address: voter, Stake: 1}))
+	proposal := &Proposal{Id: 1, Status: PROPOSAL_VOTING, VoteCount: MAX_PROPOSAL_VOTERS}
+	assert.Nil(t, putProposal(native, contract, proposal))
+	assert.NotNil(t, vote(native, voter, 1, true))
+
+	proposal.VoteCount = MAX_PROPOSAL_VOTERS - 1
+	assert.Nil(t, putProposal(native, contract, proposal))
+	assert.Nil(t, vote(native, voter, 1, true))
+	addr, err := getProposalVoter(native, contract, 1, MAX_PROPOSAL_VOTERS-1)
+	assert.Nil(t, err)
+	assert.Equal(t, voter, addr)
+}
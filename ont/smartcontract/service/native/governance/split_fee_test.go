@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common/config"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFeeRecord_Serialize(t *testing.T) {
+	record := &SplitFeeRecord{View: 7, Height: 120000, Amount: 5000000000}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, record.Serialize(bf))
+	record2 := new(SplitFeeRecord)
+	assert.Nil(t, record2.Deserialize(bf))
+	assert.Equal(t, record, record2)
+}
+
+func TestRecordSplitFee(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	contract := utils.GovernanceContractAddress
+	getRecord := func(native *native.NativeService, view uint32) *SplitFeeRecord {
+		viewBytes, err := GetUint32Bytes(view)
+		assert.Nil(t, err)
+		value, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(SPLIT_FEE_HISTORY), viewBytes))
+		assert.Nil(t, err)
+		if value == nil {
+			return nil
+		}
+		store, err := cstates.GetValueFromRawStorageItem(value)
+		assert.Nil(t, err)
+		record := new(SplitFeeRecord)
+		assert.Nil(t, record.Deserialize(bytes.NewBuffer(store)))
+		return record
+	}
+
+	native := newTestService(t, upgrade-1)
+	assert.Nil(t, recordSplitFee(native, contract, 3, 100))
+	assert.Nil(t, getRecord(native, 3))
+
+	native.Height = upgrade
+	assert.Nil(t, recordSplitFee(native, contract, 4, 200))
+	assert.Equal(t, &SplitFeeRecord{View: 4, Height: upgrade, Amount: 200}, getRecord(native, 4))
+}
@@ -547,3 +547,214 @@ func (this *SplitFeeAddress) Deserialize(r io.Reader) error {
 	this.Amount = amount
 	return nil
 }
+
+type Proposal struct { //table record a parameter change proposal and its votes
+	Id           uint32
+	Proposer     string //peer pubKey of the node which created this proposal
+	Type         uint64
+	Content      []byte //serialized parameter change
+	StartView    uint32
+	EndView      uint32 //proposal is tallied at the commitDpos of this view
+	Status       uint8
+	ApproveStake uint64
+	RejectStake  uint64
+	VoteCount    uint32 //voters are stored by index in PROPOSAL_VOTER
+}
+
+func (this *Proposal) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint32(w, this.Id); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize id error: %v", err)
+	}
+	if err := serialization.WriteString(w, this.Proposer); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize proposer error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.Type); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize type error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Content); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize content error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.StartView); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize startView error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.EndView); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize endView error: %v", err)
+	}
+	if err := serialization.WriteUint8(w, this.Status); err != nil {
+		return fmt.Errorf("serialization.WriteUint8, serialize status error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.ApproveStake); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize approveStake error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.RejectStake); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize rejectStake error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.VoteCount); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize voteCount error: %v", err)
+	}
+	return nil
+}
+
+func (this *Proposal) Deserialize(r io.Reader) error {
+	id, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize id error: %v", err)
+	}
+	proposer, err := serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize proposer error: %v", err)
+	}
+	typ, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize type error: %v", err)
+	}
+	content, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize content error: %v", err)
+	}
+	startView, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize startView error: %v", err)
+	}
+	endView, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize endView error: %v", err)
+	}
+	status, err := serialization.ReadUint8(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint8, deserialize status error: %v", err)
+	}
+	approveStake, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize approveStake error: %v", err)
+	}
+	rejectStake, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize rejectStake error: %v", err)
+	}
+	voteCount, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize voteCount error: %v", err)
+	}
+	this.Id = id
+	this.Proposer = proposer
+	this.Type = typ
+	this.Content = content
+	this.StartView = startView
+	this.EndView = endView
+	this.Status = status
+	this.ApproveStake = approveStake
+	this.RejectStake = rejectStake
+	this.VoteCount = voteCount
+	return nil
+}
+
+type ProposalVote struct { //table record each address's vote of a proposal
+	Address common.Address
+	Approve bool
+	Stake   uint64 //stake of address at the time of voting
+}
+
+func (this *ProposalVote) Serialize(w io.Writer) error {
+	if err := this.Address.Serialize(w); err != nil {
+		return fmt.Errorf("address.Serialize, serialize address error: %v", err)
+	}
+	if err := serialization.WriteBool(w, this.Approve); err != nil {
+		return fmt.Errorf("serialization.WriteBool, serialize approve error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.Stake); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize stake error: %v", err)
+	}
+	return nil
+}
+
+func (this *ProposalVote) Deserialize(r io.Reader) error {
+	address := new(common.Address)
+	err := address.Deserialize(r)
+	if err != nil {
+		return fmt.Errorf("address.Deserialize, deserialize address error: %v", err)
+	}
+	approve, err := serialization.ReadBool(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadBool, deserialize approve error: %v", err)
+	}
+	stake, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize stake error: %v", err)
+	}
+	this.Address = *address
+	this.Approve = approve
+	this.Stake = stake
+	return nil
+}
+
+type ActiveProposals struct { //table record ids of proposals in voting
+	Ids []uint32
+}
+
+func (this *ActiveProposals) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint32(w, uint32(len(this.Ids))); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize ids length error: %v", err)
+	}
+	for _, id := range this.Ids {
+		if err := serialization.WriteUint32(w, id); err != nil {
+			return fmt.Errorf("serialization.WriteUint32, serialize id error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (this *ActiveProposals) Deserialize(r io.Reader) error {
+	n, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize ids length error: %v", err)
+	}
+	ids := make([]uint32, 0)
+	for i := 0; uint32(i) < n; i++ {
+		id, err := serialization.ReadUint32(r)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadUint32, deserialize id error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	this.Ids = ids
+	return nil
+}
+
+type SplitFeeRecord struct { //table record ong split to peers and stakers in each view
+	View   uint32
+	Height uint32
+	Amount uint64
+}
+
+func (this *SplitFeeRecord) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint32(w, this.View); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize view error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.Height); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize height error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.Amount); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize amount error: %v", err)
+	}
+	return nil
+}
+
+func (this *SplitFeeRecord) Deserialize(r io.Reader) error {
+	view, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize view error: %v", err)
+	}
+	height, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize height error: %v", err)
+	}
+	amount, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize amount error: %v", err)
+	}
+	this.View = view
+	this.Height = height
+	this.Amount = amount
+	return nil
+}
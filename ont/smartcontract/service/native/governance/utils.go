@@ -29,6 +29,8 @@ import (
 	"github.com/ontio/ontology/common/serialization"
 	vbftconfig "github.com/ontio/ontology/consensus/vbft/config"
 	cstates "github.com/ontio/ontology/core/states"
+	ctypes "github.com/ontio/ontology/core/types"
+	cutils "github.com/ontio/ontology/core/utils"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/auth"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
@@ -794,3 +796,256 @@ func putPromisePos(native *native.NativeService, contract common.Address, promis
 		cstates.GenRawStorageItem(bf.Bytes()))
 	return nil
 }
+
+func getProposalIndex(native *native.NativeService, contract common.Address) (uint32, error) {
+	proposalIndexBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX)))
+	if err != nil {
+		return 0, fmt.Errorf("native.CacheDB.Get, get proposalIndex error: %v", err)
+	}
+	if proposalIndexBytes == nil {
+		return 0, nil
+	}
+	proposalIndexStore, err := cstates.GetValueFromRawStorageItem(proposalIndexBytes)
+	if err != nil {
+		return 0, fmt.Errorf("getProposalIndex, deserialize from raw storage item err:%v", err)
+	}
+	proposalIndex, err := GetBytesUint32(proposalIndexStore)
+	if err != nil {
+		return 0, fmt.Errorf("GetBytesUint32, get proposalIndex error: %v", err)
+	}
+	return proposalIndex, nil
+}
+
+func putProposalIndex(native *native.NativeService, contract common.Address, proposalIndex uint32) error {
+	proposalIndexBytes, err := GetUint32Bytes(proposalIndex)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get proposalIndexBytes error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX)), cstates.GenRawStorageItem(proposalIndexBytes))
+	return nil
+}
+
+func GetProposal(native *native.NativeService, contract common.Address, id uint32) (*Proposal, error) {
+	idBytes, err := GetUint32Bytes(id)
+	if err != nil {
+		return nil, fmt.Errorf("GetUint32Bytes, get idBytes error: %v", err)
+	}
+	proposalBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL), idBytes))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get proposalBytes error: %v", err)
+	}
+	if proposalBytes == nil {
+		return nil, fmt.Errorf("getProposal, proposal %d is not exist", id)
+	}
+	proposalStore, err := cstates.GetValueFromRawStorageItem(proposalBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, deserialize from raw storage item err:%v", err)
+	}
+	proposal := new(Proposal)
+	if err := proposal.Deserialize(bytes.NewBuffer(proposalStore)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposal error: %v", err)
+	}
+	return proposal, nil
+}
+
+func putProposal(native *native.NativeService, contract common.Address, proposal *Proposal) error {
+	idBytes, err := GetUint32Bytes(proposal.Id)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get idBytes error: %v", err)
+	}
+	bf := new(bytes.Buffer)
+	if err := proposal.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize proposal error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL), idBytes), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func getProposalVote(native *native.NativeService, contract common.Address, id uint32, address common.Address) (*ProposalVote, error) {
+	idBytes, err := GetUint32Bytes(id)
+	if err != nil {
+		return nil, fmt.Errorf("GetUint32Bytes, get idBytes error: %v", err)
+	}
+	proposalVoteBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_VOTE), idBytes, address[:]))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get proposalVoteBytes error: %v", err)
+	}
+	if proposalVoteBytes == nil {
+		return nil, nil
+	}
+	proposalVoteStore, err := cstates.GetValueFromRawStorageItem(proposalVoteBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposalVote, deserialize from raw storage item err:%v", err)
+	}
+	proposalVote := new(ProposalVote)
+	if err := proposalVote.Deserialize(bytes.NewBuffer(proposalVoteStore)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposalVote error: %v", err)
+	}
+	return proposalVote, nil
+}
+
+func putProposalVote(native *native.NativeService, contract common.Address, id uint32, proposalVote *ProposalVote) error {
+	idBytes, err := GetUint32Bytes(id)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get idBytes error: %v", err)
+	}
+	bf := new(bytes.Buffer)
+	if err := proposalVote.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize proposalVote error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_VOTE), idBytes, proposalVote.Address[:]),
+		cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func getProposalVoter(native *native.NativeService, contract common.Address, id uint32, index uint32) (common.Address, error) {
+	idBytes, err := GetUint32Bytes(id)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("GetUint32Bytes, get idBytes error: %v", err)
+	}
+	indexBytes, err := GetUint32Bytes(index)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("GetUint32Bytes, get indexBytes error: %v", err)
+	}
+	voterBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_VOTER), idBytes, indexBytes))
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("native.CacheDB.Get, get voterBytes error: %v", err)
+	}
+	if voterBytes == nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("getProposalVoter, voter %d of proposal %d is not exist", index, id)
+	}
+	voterStore, err := cstates.GetValueFromRawStorageItem(voterBytes)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("getProposalVoter, deserialize from raw storage item err:%v", err)
+	}
+	return common.AddressParseFromBytes(voterStore)
+}
+
+func putProposalVoter(native *native.NativeService, contract common.Address, id uint32, index uint32, voter common.Address) error {
+	idBytes, err := GetUint32Bytes(id)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get idBytes error: %v", err)
+	}
+	indexBytes, err := GetUint32Bytes(index)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get indexBytes error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_VOTER), idBytes, indexBytes), cstates.GenRawStorageItem(voter[:]))
+	return nil
+}
+
+func getActiveProposals(native *native.NativeService, contract common.Address) (*ActiveProposals, error) {
+	activeProposalsBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(ACTIVE_PROPOSALS)))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get activeProposalsBytes error: %v", err)
+	}
+	activeProposals := &ActiveProposals{
+		Ids: make([]uint32, 0),
+	}
+	if activeProposalsBytes != nil {
+		activeProposalsStore, err := cstates.GetValueFromRawStorageItem(activeProposalsBytes)
+		if err != nil {
+			return nil, fmt.Errorf("getActiveProposals, deserialize from raw storage item err:%v", err)
+		}
+		if err := activeProposals.Deserialize(bytes.NewBuffer(activeProposalsStore)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize activeProposals error: %v", err)
+		}
+	}
+	return activeProposals, nil
+}
+
+func putActiveProposals(native *native.NativeService, contract common.Address, activeProposals *ActiveProposals) error {
+	bf := new(bytes.Buffer)
+	if err := activeProposals.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize activeProposals error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(ACTIVE_PROPOSALS)), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func putSplitFeeRecord(native *native.NativeService, contract common.Address, splitFeeRecord *SplitFeeRecord) error {
+	viewBytes, err := GetUint32Bytes(splitFeeRecord.View)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get viewBytes error: %v", err)
+	}
+	bf := new(bytes.Buffer)
+	if err := splitFeeRecord.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize splitFeeRecord error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(SPLIT_FEE_HISTORY), viewBytes), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+//SystemTxHash return the hash of the system transaction invoking method of native contract addr,
+//which is inserted by consensus at height
+func SystemTxHash(addr common.Address, method string, height uint32) (common.Uint256, error) {
+	mutable := cutils.BuildNativeTransaction(addr, method, []byte{})
+	mutable.Nonce = height
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	return tx.Hash(), nil
+}
+
+//systemTx is a native method invoked by consensus in system transaction besides commitDpos
+type systemTx struct {
+	addr   common.Address
+	method string
+}
+
+var systemTxs = make([]systemTx, 0)
+
+//RegisterSystemTx register a native method invoked by consensus in system transaction after commitDpos,
+//so that the empty block containing it is recognized when verifying equivocation evidence
+func RegisterSystemTx(addr common.Address, method string) {
+	systemTxs = append(systemTxs, systemTx{addr: addr, method: method})
+}
+
+//systemTxHashes return the hashes of all the system transactions may be inserted at height, in order
+func systemTxHashes(height uint32) ([]common.Uint256, error) {
+	govTx, err := SystemTxHash(utils.GovernanceContractAddress, COMMIT_DPOS, height)
+	if err != nil {
+		return nil, fmt.Errorf("SystemTxHash, build governance transaction error: %v", err)
+	}
+	hashes := []common.Uint256{govTx}
+	for _, sysTx := range systemTxs {
+		hash, err := SystemTxHash(sysTx.addr, sysTx.method, height)
+		if err != nil {
+			return nil, fmt.Errorf("SystemTxHash, build %s transaction error: %v", sysTx.method, err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+//emptyBlockTxRoots return all the possible TransactionsRoot of the empty block, the empty block
+//only contains some of the system transactions sysTxs, in their order
+func emptyBlockTxRoots(sysTxs []common.Uint256) []common.Uint256 {
+	roots := make([]common.Uint256, 0, 1<<uint(len(sysTxs)))
+	for mask := 0; mask < 1<<uint(len(sysTxs)); mask++ {
+		hashes := make([]common.Uint256, 0, len(sysTxs))
+		for i, hash := range sysTxs {
+			if mask&(1<<uint(i)) != 0 {
+				hashes = append(hashes, hash)
+			}
+		}
+		roots = append(roots, common.ComputeMerkleRoot(hashes))
+	}
+	return roots
+}
+
+//IsProposalPair check if two headers are the block and empty block of one proposal, which share the
+//metadata of the proposal and one of them only contains the system transactions sysTxs of the height
+func IsProposalPair(h1, h2 *ctypes.Header, sysTxs []common.Uint256) bool {
+	if h1.Height != h2.Height || h1.PrevBlockHash != h2.PrevBlockHash || h1.Timestamp != h2.Timestamp ||
+		!bytes.Equal(h1.ConsensusPayload, h2.ConsensusPayload) {
+		return false
+	}
+	for _, root := range emptyBlockTxRoots(sysTxs) {
+		if h1.TransactionsRoot == root || h2.TransactionsRoot == root {
+			return true
+		}
+	}
+	return false
+}
@@ -23,19 +23,27 @@ import (
 	"math/big"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/service/native/auth"
+	"github.com/ontio/ontology/smartcontract/service/native/contract_abi"
+	"github.com/ontio/ontology/smartcontract/service/native/credential"
 	params "github.com/ontio/ontology/smartcontract/service/native/global_params"
 	"github.com/ontio/ontology/smartcontract/service/native/governance"
+	"github.com/ontio/ontology/smartcontract/service/native/multisig"
 	"github.com/ontio/ontology/smartcontract/service/native/ong"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
 	"github.com/ontio/ontology/smartcontract/service/native/ontid"
+	"github.com/ontio/ontology/smartcontract/service/native/scheduler"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	"github.com/ontio/ontology/smartcontract/service/neovm"
 	vm "github.com/ontio/ontology/vm/neovm"
 )
 
 var (
-	COMMIT_DPOS_BYTES = InitBytes(utils.GovernanceContractAddress, governance.COMMIT_DPOS)
+	COMMIT_DPOS_BYTES       = InitBytes(utils.GovernanceContractAddress, governance.COMMIT_DPOS)
+	EXECUTE_SCHEDULED_BYTES = InitBytes(utils.SchedulerContractAddress, scheduler.EXECUTE_SCHEDULED)
 )
 
 func init() {
@@ -45,6 +53,22 @@ func init() {
 	ontid.Init()
 	auth.Init()
 	governance.InitGovernance()
+	credential.InitCredential()
+	multisig.InitMultisig()
+	contract_abi.InitContractAbi()
+	scheduler.InitScheduler()
+	governance.RegisterSystemTx(utils.SchedulerContractAddress, scheduler.EXECUTE_SCHEDULED)
+}
+
+//IsExecuteScheduledTx return whether tx is the system transaction built by consensus to execute the
+//scheduled callbacks of block at height, which has no signature and the height as nonce. There is
+//no such transaction before the native upgrade height
+func IsExecuteScheduledTx(tx *types.Transaction, height uint32) bool {
+	if height < config.GetNativeUpgradeHeight() {
+		return false
+	}
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	return ok && bytes.Equal(invoke.Code, EXECUTE_SCHEDULED_BYTES) && len(tx.Sigs) == 0 && tx.Nonce == height
 }
 
 func InitBytes(addr common.Address, method string) []byte {
@@ -0,0 +1,418 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package multisig
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+const (
+	//function name
+	CREATE_WALLET = "createWallet"
+	CHANGE_OWNERS = "changeOwners"
+	PROPOSE       = "propose"
+	APPROVE       = "approve"
+	GET_WALLET    = "getWallet"
+	GET_PROPOSAL  = "getProposal"
+
+	//key prefix
+	WALLET_INDEX = "walletIndex"
+	WALLET       = "wallet"
+	PROPOSAL     = "proposal"
+
+	MAX_OWNERS = 32
+)
+
+//Init multisig wallet contract address
+func InitMultisig() {
+	native.Contracts[utils.MultisigContractAddress] = RegisterMultisigContract
+}
+
+//Register methods of multisig wallet contract
+func RegisterMultisigContract(native *native.NativeService) {
+	native.Register(CREATE_WALLET, CreateWallet)
+	native.Register(CHANGE_OWNERS, ChangeOwners)
+	native.Register(PROPOSE, Propose)
+	native.Register(APPROVE, Approve)
+	native.Register(GET_WALLET, GetWallet)
+	native.Register(GET_PROPOSAL, GetProposal)
+}
+
+//WalletAddress return the address holding the assets of wallet id. The preimage
+//starts with THROW, so the address can't be the one of any runnable NeoVM code
+func WalletAddress(id uint64) common.Address {
+	bf := new(bytes.Buffer)
+	bf.WriteByte(byte(vm.THROW))
+	bf.Write(utils.MultisigContractAddress[:])
+	serialization.WriteUint64(bf, id)
+	return common.AddressFromVmCode(bf.Bytes())
+}
+
+//CreateWallet create a wallet of owners and threshold, return the wallet address
+func CreateWallet(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, block num is not reached for this func")
+	}
+	params := new(CreateWalletParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, deserialize params error: %v", err)
+	}
+	if err := checkOwners(params.Owners, params.Threshold); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, %v", err)
+	}
+	wallet := &Wallet{Owners: params.Owners, Threshold: params.Threshold}
+	if !wallet.isOwner(params.Creator) {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, creator is not an owner")
+	}
+	if err := utils.ValidateOwner(native, params.Creator); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, %v", err)
+	}
+
+	id, err := getWalletIndex(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, %v", err)
+	}
+	wallet.Id = id + 1
+	addr := WalletAddress(wallet.Id)
+	if err := putWallet(native, addr, wallet); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createWallet, %v", err)
+	}
+	putWalletIndex(native, wallet.Id)
+	pushEvent(native, []interface{}{"CreateWallet", addr.ToBase58(), wallet.Id, wallet.Threshold})
+	return addr[:], nil
+}
+
+//ChangeOwners replace the owners and threshold of a wallet, it is called by an
+//executed proposal of the wallet
+func ChangeOwners(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("changeOwners, block num is not reached for this func")
+	}
+	params := new(ChangeOwnersParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("changeOwners, deserialize params error: %v", err)
+	}
+	if err := utils.ValidateOwner(native, params.Wallet); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("changeOwners, %v", err)
+	}
+	wallet, err := getWallet(native, params.Wallet)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("changeOwners, %v", err)
+	}
+	if err := checkOwners(params.Owners, params.Threshold); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("changeOwners, %v", err)
+	}
+	wallet.Owners = params.Owners
+	wallet.Threshold = params.Threshold
+	if err := putWallet(native, params.Wallet, wallet); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("changeOwners, %v", err)
+	}
+	pushEvent(native, []interface{}{"ChangeOwners", params.Wallet.ToBase58(), len(wallet.Owners), wallet.Threshold})
+	return utils.BYTE_TRUE, nil
+}
+
+//Propose an invocation of a wallet, the approval of proposer is counted
+func Propose(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, block num is not reached for this func")
+	}
+	params := new(ProposeParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, deserialize params error: %v", err)
+	}
+	wallet, err := getWallet(native, params.Wallet)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, %v", err)
+	}
+	if !wallet.isOwner(params.Proposer) {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, proposer is not an owner")
+	}
+	if err := utils.ValidateOwner(native, params.Proposer); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, %v", err)
+	}
+
+	proposal := &Proposal{
+		Id:         wallet.Proposals,
+		Proposer:   params.Proposer,
+		Invocation: params.Invocation,
+		Approvals:  []common.Address{params.Proposer},
+		Status:     STATUS_PENDING,
+	}
+	wallet.Proposals++
+	if err := putWallet(native, params.Wallet, wallet); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, %v", err)
+	}
+	pushEvent(native, []interface{}{"Propose", params.Wallet.ToBase58(), proposal.Id, params.Proposer.ToBase58()})
+	if err := tryExecute(native, params.Wallet, wallet, proposal); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("propose, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//Approve a pending proposal, it is executed once the approvals reach the threshold
+func Approve(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, block num is not reached for this func")
+	}
+	params := new(ApproveParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, deserialize params error: %v", err)
+	}
+	wallet, err := getWallet(native, params.Wallet)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, %v", err)
+	}
+	if !wallet.isOwner(params.Approver) {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, approver is not an owner")
+	}
+	if err := utils.ValidateOwner(native, params.Approver); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, %v", err)
+	}
+	proposal, err := getProposal(native, params.Wallet, params.Id)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, %v", err)
+	}
+	if proposal == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, proposal %d not exists", params.Id)
+	}
+	if proposal.Status != STATUS_PENDING {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, proposal %d is already executed", params.Id)
+	}
+	if proposal.approved(params.Approver) {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, proposal %d is already approved by %s", params.Id,
+			params.Approver.ToBase58())
+	}
+
+	proposal.Approvals = append(proposal.Approvals, params.Approver)
+	pushEvent(native, []interface{}{"Approve", params.Wallet.ToBase58(), proposal.Id, params.Approver.ToBase58()})
+	if err := tryExecute(native, params.Wallet, wallet, proposal); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//GetWallet return the serialized wallet, empty if not exists
+func GetWallet(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil, fmt.Errorf("getWallet, block num is not reached for this func")
+	}
+	addr, err := utils.ReadAddress(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("getWallet, deserialize wallet address error: %v", err)
+	}
+	item, err := utils.GetStorageItem(native, walletKey(addr))
+	if err != nil {
+		return nil, fmt.Errorf("getWallet, get wallet error: %v", err)
+	}
+	if item == nil {
+		return []byte{}, nil
+	}
+	return item.Value, nil
+}
+
+//GetProposal return the serialized proposal of a wallet, empty if not exists
+func GetProposal(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil, fmt.Errorf("getProposal, block num is not reached for this func")
+	}
+	bf := bytes.NewBuffer(native.Input)
+	addr, err := utils.ReadAddress(bf)
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, deserialize wallet address error: %v", err)
+	}
+	id, err := utils.ReadVarUint(bf)
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, deserialize proposal id error: %v", err)
+	}
+	item, err := utils.GetStorageItem(native, proposalKey(addr, id))
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, get proposal error: %v", err)
+	}
+	if item == nil {
+		return []byte{}, nil
+	}
+	return item.Value, nil
+}
+
+//tryExecute save the proposal, and execute it if approvals of current owners reach the threshold.
+//A failed execution fails the whole transaction, the proposal stays pending
+func tryExecute(native *native.NativeService, addr common.Address, wallet *Wallet, proposal *Proposal) error {
+	approvals := uint64(0)
+	for _, a := range proposal.Approvals {
+		if wallet.isOwner(a) {
+			approvals++
+		}
+	}
+	if approvals >= wallet.Threshold {
+		proposal.Status = STATUS_EXECUTED
+		proposal.ExecuteHeight = native.Height
+	}
+	// saved before execution, so that the proposal can't be executed again by reentrance
+	if err := putProposal(native, addr, proposal); err != nil {
+		return err
+	}
+	if proposal.Status != STATUS_EXECUTED {
+		return nil
+	}
+	if err := invoke(native, addr, &proposal.Invocation); err != nil {
+		return fmt.Errorf("execute proposal %d error: %v", proposal.Id, err)
+	}
+	pushEvent(native, []interface{}{"Execute", addr.ToBase58(), proposal.Id})
+	return nil
+}
+
+//invoke call the contract with the wallet address as the calling context,
+//so that CheckWitness of the wallet address passes in the callee
+func invoke(native *native.NativeService, addr common.Address, invocation *Invocation) error {
+	switch invocation.VmType {
+	case VM_TYPE_NATIVE:
+		native.ContextRef.PushContext(&context.Context{ContractAddress: addr})
+		if _, err := native.NativeCall(invocation.Contract, invocation.Method, invocation.Args); err != nil {
+			return err
+		}
+		native.ContextRef.PopContext()
+	case VM_TYPE_NEOVM:
+		dep, err := native.CacheDB.GetContract(invocation.Contract)
+		if err != nil {
+			return fmt.Errorf("get contract error: %v", err)
+		}
+		if dep == nil {
+			return fmt.Errorf("contract %s not exists", invocation.Contract.ToHexString())
+		}
+		if _, err := neovm.CallContract(native.ContextRef, addr, invocation.Contract, dep.Code, invocation.Method,
+			invocation.Args); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid vm type %d", invocation.VmType)
+	}
+	return nil
+}
+
+func checkOwners(owners []common.Address, threshold uint64) error {
+	if len(owners) == 0 || len(owners) > MAX_OWNERS {
+		return fmt.Errorf("number of owners should be between 1 and %d", MAX_OWNERS)
+	}
+	if threshold == 0 || threshold > uint64(len(owners)) {
+		return fmt.Errorf("threshold should be between 1 and number of owners")
+	}
+	set := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		if set[owner] {
+			return fmt.Errorf("duplicated owner %s", owner.ToBase58())
+		}
+		set[owner] = true
+	}
+	return nil
+}
+
+func walletKey(addr common.Address) []byte {
+	return utils.ConcatKey(utils.MultisigContractAddress, []byte(WALLET), addr[:])
+}
+
+func proposalKey(addr common.Address, id uint64) []byte {
+	bf := new(bytes.Buffer)
+	serialization.WriteUint64(bf, id)
+	return utils.ConcatKey(utils.MultisigContractAddress, []byte(PROPOSAL), addr[:], bf.Bytes())
+}
+
+func getWalletIndex(native *native.NativeService) (uint64, error) {
+	item, err := utils.GetStorageItem(native, utils.ConcatKey(utils.MultisigContractAddress, []byte(WALLET_INDEX)))
+	if err != nil {
+		return 0, fmt.Errorf("get wallet index error: %v", err)
+	}
+	if item == nil {
+		return 0, nil
+	}
+	return serialization.ReadUint64(bytes.NewBuffer(item.Value))
+}
+
+func putWalletIndex(native *native.NativeService, index uint64) {
+	bf := new(bytes.Buffer)
+	serialization.WriteUint64(bf, index)
+	native.CacheDB.Put(utils.ConcatKey(utils.MultisigContractAddress, []byte(WALLET_INDEX)),
+		cstates.GenRawStorageItem(bf.Bytes()))
+}
+
+func getWallet(native *native.NativeService, addr common.Address) (*Wallet, error) {
+	item, err := utils.GetStorageItem(native, walletKey(addr))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet error: %v", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("wallet %s not exists", addr.ToBase58())
+	}
+	wallet := new(Wallet)
+	if err := wallet.Deserialize(bytes.NewBuffer(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize wallet error: %v", err)
+	}
+	return wallet, nil
+}
+
+func putWallet(native *native.NativeService, addr common.Address, wallet *Wallet) error {
+	bf := new(bytes.Buffer)
+	if err := wallet.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize wallet error: %v", err)
+	}
+	native.CacheDB.Put(walletKey(addr), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func getProposal(native *native.NativeService, addr common.Address, id uint64) (*Proposal, error) {
+	item, err := utils.GetStorageItem(native, proposalKey(addr, id))
+	if err != nil {
+		return nil, fmt.Errorf("get proposal error: %v", err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+	proposal := new(Proposal)
+	if err := proposal.Deserialize(bytes.NewBuffer(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize proposal error: %v", err)
+	}
+	return proposal, nil
+}
+
+func putProposal(native *native.NativeService, addr common.Address, proposal *Proposal) error {
+	bf := new(bytes.Buffer)
+	if err := proposal.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize proposal error: %v", err)
+	}
+	native.CacheDB.Put(proposalKey(addr, proposal.Id), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func pushEvent(native *native.NativeService, s interface{}) {
+	native.Notifications = append(native.Notifications, &event.NotifyEventInfo{
+		ContractAddress: native.ContextRef.CurrentContext().ContractAddress,
+		States:          s,
+	})
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package multisig
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+//newTestService return a native service whose transaction is signed by signers, CheckWitness
+//of contracts is done by the smart contract context as it is on chain
+func newTestService(t *testing.T, cache *storage.CacheDB, signers ...common.Address) *native.NativeService {
+	InitMultisig()
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: config.GetNativeUpgradeHeight(), Tx: &types.Transaction{SignedAddr: signers}},
+		CacheDB: cache,
+		Gas:     math.MaxUint64,
+	}
+	service, err := sc.NewNativeService()
+	assert.Nil(t, err)
+	return service
+}
+
+func call(native *native.NativeService, method string, param interface{}) ([]byte, error) {
+	bf := new(bytes.Buffer)
+	var err error
+	switch p := param.(type) {
+	case *CreateWalletParam:
+		err = p.Serialize(bf)
+	case *ChangeOwnersParam:
+		err = p.Serialize(bf)
+	case *ProposeParam:
+		err = p.Serialize(bf)
+	case *ApproveParam:
+		err = p.Serialize(bf)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret, err := native.NativeCall(utils.MultisigContractAddress, method, bf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return ret.([]byte), nil
+}
+
+func createTestWallet(t *testing.T, cache *storage.CacheDB, owners []common.Address, threshold uint64) common.Address {
+	ret, err := call(newTestService(t, cache, owners[0]), CREATE_WALLET,
+		&CreateWalletParam{Creator: owners[0], Owners: owners, Threshold: threshold})
+	assert.Nil(t, err)
+	addr, err := common.AddressParseFromBytes(ret)
+	assert.Nil(t, err)
+	return addr
+}
+
+func changeOwnersInvocation(t *testing.T, wallet common.Address, owners []common.Address, threshold uint64) Invocation {
+	bf := new(bytes.Buffer)
+	param := &ChangeOwnersParam{Wallet: wallet, Owners: owners, Threshold: threshold}
+	assert.Nil(t, param.Serialize(bf))
+	return Invocation{
+		VmType:   VM_TYPE_NATIVE,
+		Contract: utils.MultisigContractAddress,
+		Method:   CHANGE_OWNERS,
+		Args:     bf.Bytes(),
+	}
+}
+
+func TestCreateWallet(t *testing.T) {
+	cache := testsuite.NewCacheDB(t)
+	owners := testAddresses(3)
+	if upgrade := config.GetNativeUpgradeHeight(); upgrade > 0 {
+		native := newTestService(t, cache, owners[0])
+		native.Height = upgrade - 1
+		_, err := call(native, CREATE_WALLET, &CreateWalletParam{Creator: owners[0], Owners: owners, Threshold: 2})
+		assert.NotNil(t, err)
+	}
+	_, err := call(newTestService(t, cache, owners[0]), CREATE_WALLET,
+		&CreateWalletParam{Creator: owners[1], Owners: owners, Threshold: 2})
+	assert.NotNil(t, err)
+	_, err = call(newTestService(t, cache, owners[0]), CREATE_WALLET,
+		&CreateWalletParam{Creator: owners[0], Owners: owners, Threshold: 4})
+	assert.NotNil(t, err)
+
+	addr := createTestWallet(t, cache, owners, 2)
+	assert.Equal(t, WalletAddress(1), addr)
+	wallet, err := getWallet(newTestService(t, cache), addr)
+	assert.Nil(t, err)
+	assert.Equal(t, &Wallet{Id: 1, Owners: owners, Threshold: 2}, wallet)
+	assert.Equal(t, WalletAddress(2), createTestWallet(t, cache, owners, 1))
+}
+
+func TestProposeAndApprove(t *testing.T) {
+	cache := testsuite.NewCacheDB(t)
+	owners := testAddresses(4)
+	addr := createTestWallet(t, cache, owners[:3], 2)
+	invocation := changeOwnersInvocation(t, addr, owners[1:], 3)
+
+	//only owners with witness can propose
+	_, err := call(newTestService(t, cache, owners[3]), PROPOSE,
+		&ProposeParam{Wallet: addr, Proposer: owners[3], Invocation: invocation})
+	assert.NotNil(t, err)
+	_, err = call(newTestService(t, cache, owners[1]), PROPOSE,
+		&ProposeParam{Wallet: addr, Proposer: owners[0], Invocation: invocation})
+	assert.NotNil(t, err)
+
+	//threshold is not reached with the approval of proposer
+	_, err = call(newTestService(t, cache, owners[0]), PROPOSE,
+		&ProposeParam{Wallet: addr, Proposer: owners[0], Invocation: invocation})
+	assert.Nil(t, err)
+	native := newTestService(t, cache)
+	proposal, err := getProposal(native, addr, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, STATUS_PENDING, proposal.Status)
+	assert.Equal(t, []common.Address{owners[0]}, proposal.Approvals)
+	wallet, err := getWallet(native, addr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), wallet.Proposals)
+
+	_, err = call(newTestService(t, cache, owners[0]), APPROVE,
+		&ApproveParam{Wallet: addr, Id: 0, Approver: owners[0]})
+	assert.NotNil(t, err)
+	_, err = call(newTestService(t, cache, owners[1]), APPROVE,
+		&ApproveParam{Wallet: addr, Id: 1, Approver: owners[1]})
+	assert.NotNil(t, err)
+	_, err = call(newTestService(t, cache, owners[2]), APPROVE,
+		&ApproveParam{Wallet: addr, Id: 0, Approver: owners[1]})
+	assert.NotNil(t, err)
+
+	//threshold is reached, the proposal is executed in the context of wallet
+	_, err = call(newTestService(t, cache, owners[1]), APPROVE,
+		&ApproveParam{Wallet: addr, Id: 0, Approver: owners[1]})
+	assert.Nil(t, err)
+	proposal, err = getProposal(native, addr, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, STATUS_EXECUTED, proposal.Status)
+	assert.Equal(t, config.GetNativeUpgradeHeight(), proposal.ExecuteHeight)
+	wallet, err = getWallet(native, addr)
+	assert.Nil(t, err)
+	assert.Equal(t, owners[1:], wallet.Owners)
+	assert.Equal(t, uint64(3), wallet.Threshold)
+
+	_, err = call(newTestService(t, cache, owners[2]), APPROVE,
+		&ApproveParam{Wallet: addr, Id: 0, Approver: owners[2]})
+	assert.NotNil(t, err)
+}
+
+func TestPropose_ExecuteAtOnce(t *testing.T) {
+	cache := testsuite.NewCacheDB(t)
+	owners := testAddresses(2)
+	addr := createTestWallet(t, cache, owners, 1)
+	invocation := changeOwnersInvocation(t, addr, owners[1:], 1)
+	_, err := call(newTestService(t, cache, owners[1]), PROPOSE,
+		&ProposeParam{Wallet: addr, Proposer: owners[1], Invocation: invocation})
+	assert.Nil(t, err)
+	native := newTestService(t, cache)
+	proposal, err := getProposal(native, addr, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, STATUS_EXECUTED, proposal.Status)
+	wallet, err := getWallet(native, addr)
+	assert.Nil(t, err)
+	assert.Equal(t, owners[1:], wallet.Owners)
+
+	//approvals of removed owners are not counted
+	invocation = changeOwnersInvocation(t, addr, owners, 1)
+	proposal = &Proposal{Id: 1, Proposer: owners[0], Invocation: invocation, Approvals: owners[:1]}
+	assert.Nil(t, tryExecute(native, addr, wallet, proposal))
+	assert.Equal(t, STATUS_PENDING, proposal.Status)
+}
+
+func TestChangeOwners_Witness(t *testing.T) {
+	cache := testsuite.NewCacheDB(t)
+	owners := testAddresses(2)
+	addr := createTestWallet(t, cache, owners, 1)
+
+	//owners can't change the wallet without a proposal, even if they sign the transaction
+	_, err := call(newTestService(t, cache, owners...), CHANGE_OWNERS,
+		&ChangeOwnersParam{Wallet: addr, Owners: owners[:1], Threshold: 1})
+	assert.NotNil(t, err)
+
+	//a failed execution fails the transaction
+	invocation := changeOwnersInvocation(t, addr, owners, 3)
+	_, err = call(newTestService(t, cache, owners[0]), PROPOSE,
+		&ProposeParam{Wallet: addr, Proposer: owners[0], Invocation: invocation})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,359 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package multisig
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	VM_TYPE_NATIVE byte = 0
+	VM_TYPE_NEOVM  byte = 1
+
+	STATUS_PENDING  byte = 0
+	STATUS_EXECUTED byte = 1
+)
+
+//Wallet is the owners and threshold of a multisig wallet
+type Wallet struct {
+	Id        uint64
+	Owners    []common.Address
+	Threshold uint64
+	Proposals uint64 //number of proposals ever made, the next proposal id
+}
+
+func (this *Wallet) isOwner(addr common.Address) bool {
+	for _, owner := range this.Owners {
+		if owner == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *Wallet) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, this.Id); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	if err := serializeAddresses(w, this.Owners); err != nil {
+		return fmt.Errorf("serializeAddresses, serialize owners error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Threshold); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize threshold error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Proposals); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize proposals error: %v", err)
+	}
+	return nil
+}
+
+func (this *Wallet) Deserialize(r io.Reader) error {
+	var err error
+	if this.Id, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	if this.Owners, err = deserializeAddresses(r); err != nil {
+		return fmt.Errorf("deserializeAddresses, deserialize owners error: %v", err)
+	}
+	if this.Threshold, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize threshold error: %v", err)
+	}
+	if this.Proposals, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize proposals error: %v", err)
+	}
+	return nil
+}
+
+//Invocation is the contract call made by a wallet. For VM_TYPE_NATIVE Args is the
+//input of the native method, for VM_TYPE_NEOVM Args is a script pushing the
+//parameters of the method, which is pushed on the top of them before calling
+type Invocation struct {
+	VmType   byte
+	Contract common.Address
+	Method   string
+	Args     []byte
+}
+
+func (this *Invocation) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(this.VmType)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize vm type error: %v", err)
+	}
+	if err := utils.WriteAddress(w, this.Contract); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize contract error: %v", err)
+	}
+	if err := serialization.WriteString(w, this.Method); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize method error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Args); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize args error: %v", err)
+	}
+	return nil
+}
+
+func (this *Invocation) Deserialize(r io.Reader) error {
+	vmType, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize vm type error: %v", err)
+	}
+	if vmType != uint64(VM_TYPE_NATIVE) && vmType != uint64(VM_TYPE_NEOVM) {
+		return fmt.Errorf("invalid vm type %d", vmType)
+	}
+	this.VmType = byte(vmType)
+	if this.Contract, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize contract error: %v", err)
+	}
+	if this.Method, err = serialization.ReadString(r); err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize method error: %v", err)
+	}
+	if this.Args, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize args error: %v", err)
+	}
+	return nil
+}
+
+//Proposal is an invocation waiting for approvals of wallet owners
+type Proposal struct {
+	Id            uint64
+	Proposer      common.Address
+	Invocation    Invocation
+	Approvals     []common.Address
+	Status        byte
+	ExecuteHeight uint32
+}
+
+func (this *Proposal) approved(addr common.Address) bool {
+	for _, a := range this.Approvals {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *Proposal) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, this.Id); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	if err := utils.WriteAddress(w, this.Proposer); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize proposer error: %v", err)
+	}
+	if err := this.Invocation.Serialize(w); err != nil {
+		return err
+	}
+	if err := serializeAddresses(w, this.Approvals); err != nil {
+		return fmt.Errorf("serializeAddresses, serialize approvals error: %v", err)
+	}
+	if err := serialization.WriteByte(w, this.Status); err != nil {
+		return fmt.Errorf("serialization.WriteByte, serialize status error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.ExecuteHeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize execute height error: %v", err)
+	}
+	return nil
+}
+
+func (this *Proposal) Deserialize(r io.Reader) error {
+	var err error
+	if this.Id, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	if this.Proposer, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize proposer error: %v", err)
+	}
+	if err := this.Invocation.Deserialize(r); err != nil {
+		return err
+	}
+	if this.Approvals, err = deserializeAddresses(r); err != nil {
+		return fmt.Errorf("deserializeAddresses, deserialize approvals error: %v", err)
+	}
+	if this.Status, err = serialization.ReadByte(r); err != nil {
+		return fmt.Errorf("serialization.ReadByte, deserialize status error: %v", err)
+	}
+	if this.ExecuteHeight, err = serialization.ReadUint32(r); err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize execute height error: %v", err)
+	}
+	return nil
+}
+
+//CreateWalletParam is the param of createWallet, Creator must be one of the owners
+type CreateWalletParam struct {
+	Creator   common.Address
+	Owners    []common.Address
+	Threshold uint64
+}
+
+func (this *CreateWalletParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Creator); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize creator error: %v", err)
+	}
+	if err := serializeAddresses(w, this.Owners); err != nil {
+		return fmt.Errorf("serializeAddresses, serialize owners error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Threshold); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize threshold error: %v", err)
+	}
+	return nil
+}
+
+func (this *CreateWalletParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Creator, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize creator error: %v", err)
+	}
+	if this.Owners, err = deserializeAddresses(r); err != nil {
+		return fmt.Errorf("deserializeAddresses, deserialize owners error: %v", err)
+	}
+	if this.Threshold, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize threshold error: %v", err)
+	}
+	return nil
+}
+
+//ChangeOwnersParam is the param of changeOwners, it can only be called by the wallet itself
+type ChangeOwnersParam struct {
+	Wallet    common.Address
+	Owners    []common.Address
+	Threshold uint64
+}
+
+func (this *ChangeOwnersParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Wallet); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize wallet error: %v", err)
+	}
+	if err := serializeAddresses(w, this.Owners); err != nil {
+		return fmt.Errorf("serializeAddresses, serialize owners error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Threshold); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize threshold error: %v", err)
+	}
+	return nil
+}
+
+func (this *ChangeOwnersParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Wallet, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize wallet error: %v", err)
+	}
+	if this.Owners, err = deserializeAddresses(r); err != nil {
+		return fmt.Errorf("deserializeAddresses, deserialize owners error: %v", err)
+	}
+	if this.Threshold, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize threshold error: %v", err)
+	}
+	return nil
+}
+
+//ProposeParam is the param of propose, Proposer must be one of the owners
+type ProposeParam struct {
+	Wallet     common.Address
+	Proposer   common.Address
+	Invocation Invocation
+}
+
+func (this *ProposeParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Wallet); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize wallet error: %v", err)
+	}
+	if err := utils.WriteAddress(w, this.Proposer); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize proposer error: %v", err)
+	}
+	return this.Invocation.Serialize(w)
+}
+
+func (this *ProposeParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Wallet, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize wallet error: %v", err)
+	}
+	if this.Proposer, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize proposer error: %v", err)
+	}
+	return this.Invocation.Deserialize(r)
+}
+
+//ApproveParam is the param of approve, Approver must be one of the owners
+type ApproveParam struct {
+	Wallet   common.Address
+	Id       uint64
+	Approver common.Address
+}
+
+func (this *ApproveParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Wallet); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize wallet error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Id); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	if err := utils.WriteAddress(w, this.Approver); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize approver error: %v", err)
+	}
+	return nil
+}
+
+func (this *ApproveParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Wallet, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize wallet error: %v", err)
+	}
+	if this.Id, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	if this.Approver, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize approver error: %v", err)
+	}
+	return nil
+}
+
+func serializeAddresses(w io.Writer, addrs []common.Address) error {
+	if err := utils.WriteVarUint(w, uint64(len(addrs))); err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := utils.WriteAddress(w, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deserializeAddresses(r io.Reader) ([]common.Address, error) {
+	n, err := utils.ReadVarUint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > math.MaxUint16 {
+		return nil, fmt.Errorf("too many addresses: %d", n)
+	}
+	addrs := make([]common.Address, 0, n)
+	for i := uint64(0); i < n; i++ {
+		addr, err := utils.ReadAddress(r)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package multisig
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAddresses(n int) []common.Address {
+	addrs := make([]common.Address, 0, n)
+	for i := 0; i < n; i++ {
+		addrs = append(addrs, common.AddressFromVmCode([]byte{byte(i)}))
+	}
+	return addrs
+}
+
+func TestProposal_Serialize(t *testing.T) {
+	owners := testAddresses(3)
+	proposal := &Proposal{
+		Id:       7,
+		Proposer: owners[0],
+		Invocation: Invocation{
+			VmType:   VM_TYPE_NATIVE,
+			Contract: utils.OntContractAddress,
+			Method:   "transfer",
+			Args:     []byte{1, 2, 3},
+		},
+		Approvals:     owners[:2],
+		Status:        STATUS_EXECUTED,
+		ExecuteHeight: 100,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, proposal.Serialize(bf))
+	proposal2 := new(Proposal)
+	assert.Nil(t, proposal2.Deserialize(bf))
+	assert.Equal(t, proposal, proposal2)
+	assert.True(t, proposal2.approved(owners[1]))
+	assert.False(t, proposal2.approved(owners[2]))
+
+	wallet := &Wallet{Id: 1, Owners: owners, Threshold: 2, Proposals: 8}
+	bf.Reset()
+	assert.Nil(t, wallet.Serialize(bf))
+	wallet2 := new(Wallet)
+	assert.Nil(t, wallet2.Deserialize(bf))
+	assert.Equal(t, wallet, wallet2)
+
+	bf.Reset()
+	invalid := proposal.Invocation
+	invalid.VmType = 2
+	assert.Nil(t, invalid.Serialize(bf))
+	assert.NotNil(t, new(Invocation).Deserialize(bf))
+}
+
+func TestCheckOwners(t *testing.T) {
+	owners := testAddresses(MAX_OWNERS + 1)
+	assert.Nil(t, checkOwners(owners[:3], 1))
+	assert.Nil(t, checkOwners(owners[:3], 3))
+	assert.NotNil(t, checkOwners(owners[:3], 0))
+	assert.NotNil(t, checkOwners(owners[:3], 4))
+	assert.NotNil(t, checkOwners(nil, 1))
+	assert.NotNil(t, checkOwners(owners, 1))
+	assert.NotNil(t, checkOwners([]common.Address{owners[0], owners[0]}, 1))
+}
+
+func TestWalletAddress(t *testing.T) {
+	assert.Equal(t, WalletAddress(1), WalletAddress(1))
+	assert.NotEqual(t, WalletAddress(1), WalletAddress(2))
+}
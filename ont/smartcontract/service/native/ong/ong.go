@@ -23,6 +23,7 @@ import (
 
 	"fmt"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/smartcontract/service/native"
@@ -46,6 +47,7 @@ func RegisterOngContract(native *native.NativeService) {
 	native.Register(ont.TOTALSUPPLY_NAME, OngTotalSupply)
 	native.Register(ont.BALANCEOF_NAME, OngBalanceOf)
 	native.Register(ont.ALLOWANCE_NAME, OngAllowance)
+	native.Register(ont.TRANSFER_BATCH_NAME, OngTransferBatch)
 }
 
 func OngInit(native *native.NativeService) ([]byte, error) {
@@ -88,6 +90,23 @@ func OngTransfer(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+func OngTransferBatch(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.NewErr("[OngTransferBatch] block num is not reached for this func")
+	}
+	var batch ont.TransferBatch
+	source := common.NewZeroCopySource(native.Input)
+	if err := batch.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[OngTransferBatch] TransferBatch deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	statuses, err := ont.BatchTransfer(native, contract, &batch, constants.ONG_TOTAL_SUPPLY, nil)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return statuses, nil
+}
+
 func OngApprove(native *native.NativeService) ([]byte, error) {
 	var state ont.State
 	source := common.NewZeroCopySource(native.Input)
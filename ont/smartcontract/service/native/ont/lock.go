@@ -0,0 +1,206 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ont
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/vm/neovm/types"
+)
+
+const (
+	LOCK_NAME                    = "lock"
+	LOCKED_BALANCEOF_NAME        = "lockedBalanceOf"
+	LOCK_BY_HEIGHT        uint64 = 1
+	LOCK_BY_TIME          uint64 = 2
+	MAX_LOCKS_PER_ADDRESS        = 64
+)
+
+func genLockKey(contract, address common.Address) []byte {
+	temp := append(contract[:], LOCK_NAME...)
+	return append(temp, address[:]...)
+}
+
+func getLocks(native *native.NativeService, contract, address common.Address) (Locks, error) {
+	item, err := utils.GetStorageItem(native, genLockKey(contract, address))
+	if err != nil {
+		return nil, err
+	}
+	locks := make(Locks, 0)
+	if item == nil {
+		return locks, nil
+	}
+	if err := locks.Deserialization(common.NewZeroCopySource(item.Value)); err != nil {
+		return nil, fmt.Errorf("[Lock] deserialize locks error:%v", err)
+	}
+	return locks, nil
+}
+
+func putLocks(native *native.NativeService, contract, address common.Address, locks Locks) {
+	if len(locks) == 0 {
+		native.CacheDB.Delete(genLockKey(contract, address))
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	locks.Serialization(sink)
+	native.CacheDB.Put(genLockKey(contract, address), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// locked amount of the lock at current block
+func (this *Lock) locked(native *native.NativeService) uint64 {
+	now := native.Height
+	if this.LockType == LOCK_BY_TIME {
+		now = native.Time
+	}
+	if now < this.Cliff {
+		return this.Value
+	}
+	if now >= this.End {
+		return 0
+	}
+	//value is not more than ont total supply, so it will not overflow
+	released := this.Value * uint64(now-this.Start) / uint64(this.End-this.Start)
+	return this.Value - released
+}
+
+func (this *Lock) validate() error {
+	if this.LockType != LOCK_BY_HEIGHT && this.LockType != LOCK_BY_TIME {
+		return fmt.Errorf("unknown lock type:%d", this.LockType)
+	}
+	if this.Value == 0 || this.Value > constants.ONT_TOTAL_SUPPLY {
+		return fmt.Errorf("invalid lock amount:%d", this.Value)
+	}
+	if this.Start > this.Cliff || this.Cliff > this.End {
+		return fmt.Errorf("lock schedule not match start <= cliff <= end")
+	}
+	return nil
+}
+
+// getLockedBalance return ont of address locked at current block
+func getLockedBalance(native *native.NativeService, contract, address common.Address) (uint64, error) {
+	locks, err := getLocks(native, contract, address)
+	if err != nil {
+		return 0, err
+	}
+	locked := uint64(0)
+	for i := range locks {
+		locked += locks[i].locked(native)
+	}
+	return locked, nil
+}
+
+// checkUnlocked check the unlocked ont of address is enough to spend value
+func checkUnlocked(native *native.NativeService, contract, address common.Address, value uint64) error {
+	if contract != utils.OntContractAddress || native.Height < config.GetNativeUpgradeHeight() {
+		return nil
+	}
+	locked, err := getLockedBalance(native, contract, address)
+	if err != nil {
+		return err
+	}
+	if locked == 0 {
+		return nil
+	}
+	balance, err := utils.GetStorageUInt64(native, GenBalanceKey(contract, address))
+	if err != nil {
+		return err
+	}
+	if balance < locked || balance-locked < value {
+		return fmt.Errorf("[Transfer] unlocked balance insufficient. account:%s, balance:%d, locked:%d, transfer amount:%d",
+			address.ToBase58(), balance, locked, value)
+	}
+	return nil
+}
+
+// OntLock transfer ont to an address and lock it by a vesting schedule, locked ont still
+// counts in balanceOf and earns unbound ong. Both the sender and the beneficiary must sign
+func OntLock(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.NewErr("[Lock] block num is not reached for this func")
+	}
+	var param LockParam
+	source := common.NewZeroCopySource(native.Input)
+	if err := param.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Lock] LockParam deserialize error!")
+	}
+	if err := param.Lock.validate(); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("[Lock] %v", err)
+	}
+	//the lock slots of an address are limited, so only the beneficiary can accept a lock
+	if !native.ContextRef.CheckWitness(param.To) {
+		return utils.BYTE_FALSE, errors.NewErr("[Lock] beneficiary authentication failed!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	locks, err := getLocks(native, contract, param.To)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	//remove released locks
+	remain := make(Locks, 0, len(locks)+1)
+	for i := range locks {
+		if locks[i].locked(native) != 0 {
+			remain = append(remain, locks[i])
+		}
+	}
+	if len(remain) >= MAX_LOCKS_PER_ADDRESS {
+		return utils.BYTE_FALSE, fmt.Errorf("[Lock] locks of address reach the limit %d", MAX_LOCKS_PER_ADDRESS)
+	}
+
+	state := &State{From: param.From, To: param.To, Value: param.Value}
+	fromBalance, toBalance, err := Transfer(native, contract, state)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if err := grantOng(native, contract, state.From, fromBalance); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if err := grantOng(native, contract, state.To, toBalance); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+
+	putLocks(native, contract, param.To, append(remain, param.Lock))
+	AddNotifications(native, contract, state)
+	return utils.BYTE_TRUE, nil
+}
+
+func OntLockedBalanceOf(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.NewErr("[LockedBalanceOf] block num is not reached for this func")
+	}
+	source := common.NewZeroCopySource(native.Input)
+	address, err := utils.DecodeAddress(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[LockedBalanceOf] get address error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	locked, err := getLockedBalance(native, contract, address)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[LockedBalanceOf] get locked balance error!")
+	}
+	return types.BigIntToBytes(big.NewInt(int64(locked))), nil
+}
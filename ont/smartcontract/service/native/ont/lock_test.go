@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ont
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock_Locked(t *testing.T) {
+	lock := &Lock{LockType: LOCK_BY_HEIGHT, Value: 1000, Start: 100, Cliff: 150, End: 200}
+	assert.Nil(t, lock.validate())
+
+	cases := map[uint32]uint64{0: 1000, 149: 1000, 150: 500, 175: 250, 199: 10, 200: 0, 300: 0}
+	for height, locked := range cases {
+		assert.Equal(t, locked, lock.locked(&native.NativeService{Height: height}), "height %d", height)
+	}
+
+	lock.LockType = LOCK_BY_TIME
+	assert.Equal(t, uint64(1000), lock.locked(&native.NativeService{Height: 300, Time: 120}))
+	assert.Equal(t, uint64(0), lock.locked(&native.NativeService{Height: 0, Time: 200}))
+
+	//pure time lock
+	lock = &Lock{LockType: LOCK_BY_TIME, Value: 10, Start: 100, Cliff: 100, End: 100}
+	assert.Nil(t, lock.validate())
+	assert.Equal(t, uint64(10), lock.locked(&native.NativeService{Time: 99}))
+	assert.Equal(t, uint64(0), lock.locked(&native.NativeService{Time: 100}))
+
+	assert.NotNil(t, (&Lock{LockType: 3, Value: 1}).validate())
+	assert.NotNil(t, (&Lock{LockType: LOCK_BY_HEIGHT, Value: 0}).validate())
+	assert.NotNil(t, (&Lock{LockType: LOCK_BY_HEIGHT, Value: 1, Start: 10, Cliff: 5, End: 20}).validate())
+}
+
+func TestLockParam_Serialization(t *testing.T) {
+	param := LockParam{
+		From: common.AddressFromVmCode([]byte{1}),
+		To:   common.AddressFromVmCode([]byte{2}),
+		Lock: Lock{LockType: LOCK_BY_TIME, Value: 100, Start: 1, Cliff: 2, End: 3},
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	param2 := LockParam{}
+	assert.Nil(t, param2.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, param, param2)
+
+	locks := Locks{param.Lock, {LockType: LOCK_BY_HEIGHT, Value: 1, End: 10}}
+	sink = common.NewZeroCopySink(nil)
+	locks.Serialization(sink)
+	var locks2 Locks
+	assert.Nil(t, locks2.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, locks, locks2)
+}
+
+func TestOntLock_Witness(t *testing.T) {
+	contract := utils.OntContractAddress
+	from, to := common.Address{1}, common.Address{2}
+	upgrade := config.GetNativeUpgradeHeight()
+	lockParam := func() []byte {
+		param := LockParam{From: from, To: to,
+			Lock: Lock{LockType: LOCK_BY_HEIGHT, Value: 10, Start: upgrade, Cliff: upgrade, End: upgrade + 100}}
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes()
+	}
+
+	//the beneficiary does not sign
+	native := testsuite.NewNativeService(t, contract, upgrade, from)
+	putBalance(native, contract, from, 100)
+	native.Input = lockParam()
+	_, err := OntLock(native)
+	assert.NotNil(t, err)
+	locked, err := getLockedBalance(native, contract, to)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), locked)
+
+	native = testsuite.NewNativeService(t, contract, upgrade, from, to)
+	putBalance(native, contract, from, 100)
+	if upgrade > 0 {
+		native.Height = upgrade - 1
+		native.Input = lockParam()
+		_, err = OntLock(native)
+		assert.NotNil(t, err)
+	}
+	native.Height = upgrade
+	native.Input = lockParam()
+	_, err = OntLock(native)
+	assert.Nil(t, err)
+	locked, err = getLockedBalance(native, contract, to)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), locked)
+}
@@ -24,6 +24,7 @@ import (
 	"math/big"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/errors"
@@ -52,6 +53,9 @@ func RegisterOntContract(native *native.NativeService) {
 	native.Register(TOTALSUPPLY_NAME, OntTotalSupply)
 	native.Register(BALANCEOF_NAME, OntBalanceOf)
 	native.Register(ALLOWANCE_NAME, OntAllowance)
+	native.Register(TRANSFER_BATCH_NAME, OntTransferBatch)
+	native.Register(LOCK_NAME, OntLock)
+	native.Register(LOCKED_BALANCEOF_NAME, OntLockedBalanceOf)
 }
 
 func OntInit(native *native.NativeService) ([]byte, error) {
@@ -143,6 +147,29 @@ func OntTransfer(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+func OntTransferBatch(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.NewErr("[TransferBatch] block num is not reached for this func")
+	}
+	var batch TransferBatch
+	source := common.NewZeroCopySource(native.Input)
+	if err := batch.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[TransferBatch] TransferBatch deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	statuses, err := BatchTransfer(native, contract, &batch, constants.ONT_TOTAL_SUPPLY,
+		func(state *State, fromBalance, toBalance uint64) error {
+			if err := grantOng(native, contract, state.From, fromBalance); err != nil {
+				return err
+			}
+			return grantOng(native, contract, state.To, toBalance)
+		})
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return statuses, nil
+}
+
 func OntTransferFrom(native *native.NativeService) ([]byte, error) {
 	var state TransferFrom
 	source := common.NewZeroCopySource(native.Input)
@@ -21,6 +21,7 @@ package ont
 import (
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/errors"
@@ -213,3 +214,144 @@ func (this *TransferFrom) Deserialization(source *common.ZeroCopySource) error {
 
 	return err
 }
+
+// TransferBatch is the param of transferBatch, failed items are skipped instead of
+// aborting the call when ContinueOnError is set
+type TransferBatch struct {
+	ContinueOnError bool
+	States          []State
+}
+
+func (this *TransferBatch) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteBool(this.ContinueOnError)
+	utils.EncodeVarUint(sink, uint64(len(this.States)))
+	for _, v := range this.States {
+		v.Serialization(sink)
+	}
+}
+
+func (this *TransferBatch) Deserialization(source *common.ZeroCopySource) error {
+	continueOnError, irregular, eof := source.NextBool()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	n, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	this.ContinueOnError = continueOnError
+	for i := 0; uint64(i) < n; i++ {
+		var state State
+		if err := state.Deserialization(source); err != nil {
+			return err
+		}
+		this.States = append(this.States, state)
+	}
+	return nil
+}
+
+// Lock is a vesting schedule of ONT locked to an address, nothing is released before
+// Cliff, after that it is released linearly from Start and fully released at End
+type Lock struct {
+	LockType uint64 //LOCK_BY_HEIGHT or LOCK_BY_TIME
+	Value    uint64
+	Start    uint32
+	Cliff    uint32
+	End      uint32
+}
+
+func (this *Lock) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, this.LockType)
+	utils.EncodeVarUint(sink, this.Value)
+	utils.EncodeVarUint(sink, uint64(this.Start))
+	utils.EncodeVarUint(sink, uint64(this.Cliff))
+	utils.EncodeVarUint(sink, uint64(this.End))
+}
+
+func (this *Lock) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	this.LockType, err = utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	this.Value, err = utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	this.Start, err = decodeUint32(source)
+	if err != nil {
+		return err
+	}
+	this.Cliff, err = decodeUint32(source)
+	if err != nil {
+		return err
+	}
+	this.End, err = decodeUint32(source)
+	return err
+}
+
+// LockParam is the param of lock, Value of From is transferred to To and locked by schedule
+type LockParam struct {
+	From common.Address
+	To   common.Address
+	Lock
+}
+
+func (this *LockParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeAddress(sink, this.From)
+	utils.EncodeAddress(sink, this.To)
+	this.Lock.Serialization(sink)
+}
+
+func (this *LockParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	this.From, err = utils.DecodeAddress(source)
+	if err != nil {
+		return err
+	}
+	this.To, err = utils.DecodeAddress(source)
+	if err != nil {
+		return err
+	}
+	return this.Lock.Deserialization(source)
+}
+
+type Locks []Lock
+
+func (this Locks) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, uint64(len(this)))
+	for _, v := range this {
+		v.Serialization(sink)
+	}
+}
+
+func (this *Locks) Deserialization(source *common.ZeroCopySource) error {
+	n, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	locks := make(Locks, 0)
+	for i := 0; uint64(i) < n; i++ {
+		var lock Lock
+		if err := lock.Deserialization(source); err != nil {
+			return err
+		}
+		locks = append(locks, lock)
+	}
+	*this = locks
+	return nil
+}
+
+func decodeUint32(source *common.ZeroCopySource) (uint32, error) {
+	value, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return 0, err
+	}
+	if value > math.MaxUint32 {
+		return 0, fmt.Errorf("value %d larger than max of uint32", value)
+	}
+	return uint32(value), nil
+}
@@ -44,3 +44,23 @@ func TestState_Serialize(t *testing.T) {
 
 	assert.Equal(t, state, state2)
 }
+
+func TestTransferBatch_Serialization(t *testing.T) {
+	batch := TransferBatch{
+		ContinueOnError: true,
+		States: []State{
+			{From: common.AddressFromVmCode([]byte{1}), To: common.AddressFromVmCode([]byte{2}), Value: 1},
+			{From: common.AddressFromVmCode([]byte{3}), To: common.AddressFromVmCode([]byte{4}), Value: 100},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	batch.Serialization(sink)
+
+	batch2 := TransferBatch{}
+	err := batch2.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, batch, batch2)
+
+	err = new(TransferBatch).Deserialization(common.NewZeroCopySource(sink.Bytes()[:10]))
+	assert.NotNil(t, err)
+}
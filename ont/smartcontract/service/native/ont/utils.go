@@ -20,6 +20,7 @@ package ont
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/ontio/ontology/common"
@@ -45,6 +46,16 @@ const (
 	TOTALSUPPLY_NAME    = "totalSupply"
 	BALANCEOF_NAME      = "balanceOf"
 	ALLOWANCE_NAME      = "allowance"
+	TRANSFER_BATCH_NAME = "transferBatch"
+)
+
+const (
+	//gas charged for each item of transferBatch
+	TRANSFER_BATCH_ITEM_GAS uint64 = 1000
+
+	//status of each item returned by transferBatch
+	TRANSFER_BATCH_FAILED  byte = 0
+	TRANSFER_BATCH_SUCCESS byte = 1
 )
 
 func AddNotifications(native *native.NativeService, contract common.Address, state *State) {
@@ -58,6 +69,18 @@ func AddNotifications(native *native.NativeService, contract common.Address, sta
 		})
 }
 
+func AddBatchNotifications(native *native.NativeService, contract common.Address, statuses []byte, succeeded uint64) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States: []interface{}{TRANSFER_BATCH_NAME, succeeded, uint64(len(statuses)) - succeeded,
+				hex.EncodeToString(statuses)},
+		})
+}
+
 func GetToUInt64StorageItem(toBalance, value uint64) *cstates.StorageItem {
 	bf := new(bytes.Buffer)
 	serialization.WriteUint64(bf, toBalance+value)
@@ -77,6 +100,10 @@ func Transfer(native *native.NativeService, contract common.Address, state *Stat
 		return 0, 0, errors.NewErr("authentication failed!")
 	}
 
+	if err := checkUnlocked(native, contract, state.From, state.Value); err != nil {
+		return 0, 0, err
+	}
+
 	fromBalance, err := fromTransfer(native, GenBalanceKey(contract, state.From), state.Value)
 	if err != nil {
 		return 0, 0, err
@@ -89,6 +116,65 @@ func Transfer(native *native.NativeService, contract common.Address, state *Stat
 	return fromBalance, toBalance, nil
 }
 
+// BatchTransfer execute transfers of batch one by one and return status of each item,
+// onTransfer is called after each successful transfer with balances before transfer.
+// Only a summary event of the batch is notified, the transfers are known from the batch and statuses
+func BatchTransfer(native *native.NativeService, contract common.Address, batch *TransferBatch, totalSupply uint64,
+	onTransfer func(state *State, fromBalance, toBalance uint64) error) ([]byte, error) {
+	statuses := make([]byte, len(batch.States))
+	succeeded := uint64(0)
+	for i := range batch.States {
+		state := &batch.States[i]
+		if !native.ContextRef.CheckUseGas(TRANSFER_BATCH_ITEM_GAS) {
+			return nil, errors.NewErr("[TransferBatch] gas insufficient!")
+		}
+		if err := checkTransfer(native, contract, state, totalSupply); err != nil {
+			if !batch.ContinueOnError {
+				return nil, fmt.Errorf("[TransferBatch] item %d error: %v", i, err)
+			}
+			statuses[i] = TRANSFER_BATCH_FAILED
+			continue
+		}
+		if state.Value != 0 {
+			fromBalance, toBalance, err := Transfer(native, contract, state)
+			if err != nil {
+				return nil, err
+			}
+			if onTransfer != nil {
+				if err := onTransfer(state, fromBalance, toBalance); err != nil {
+					return nil, err
+				}
+			}
+		}
+		statuses[i] = TRANSFER_BATCH_SUCCESS
+		succeeded++
+	}
+	AddBatchNotifications(native, contract, statuses, succeeded)
+	return statuses, nil
+}
+
+// check a transfer can be done without modifying storage, so that a failed item leaves no trace
+func checkTransfer(native *native.NativeService, contract common.Address, state *State, totalSupply uint64) error {
+	if state.Value == 0 {
+		return nil
+	}
+	if state.Value > totalSupply {
+		return fmt.Errorf("transfer amount:%d over totalSupply:%d", state.Value, totalSupply)
+	}
+	if !native.ContextRef.CheckWitness(state.From) {
+		return errors.NewErr("authentication failed!")
+	}
+	fromBalance, err := utils.GetStorageUInt64(native, GenBalanceKey(contract, state.From))
+	if err != nil {
+		return err
+	}
+	if fromBalance < state.Value {
+		return fmt.Errorf("balance insufficient. account:%s, balance:%d, transfer amount:%d",
+			state.From.ToBase58(), fromBalance, state.Value)
+	}
+	return checkUnlocked(native, contract, state.From, state.Value)
+}
+
 func GenApproveKey(contract, from, to common.Address) []byte {
 	temp := append(contract[:], from[:]...)
 	return append(temp, to[:]...)
@@ -99,6 +185,10 @@ func TransferedFrom(native *native.NativeService, currentContract common.Address
 		return 0, 0, errors.NewErr("authentication failed!")
 	}
 
+	if err := checkUnlocked(native, currentContract, state.From, state.Value); err != nil {
+		return 0, 0, err
+	}
+
 	if err := fromApprove(native, genTransferFromKey(currentContract, state), state.Value); err != nil {
 		return 0, 0, err
 	}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ont
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func putBalance(native *native.NativeService, contract, address common.Address, balance uint64) {
+	native.CacheDB.Put(GenBalanceKey(contract, address), GetToUInt64StorageItem(0, balance).ToArray())
+}
+
+func TestBatchTransfer_Notifications(t *testing.T) {
+	contract := utils.OngContractAddress
+	from, to := common.Address{1}, common.Address{2}
+	native := testsuite.NewNativeService(t, contract, 0, from)
+	putBalance(native, contract, from, 100)
+
+	batch := &TransferBatch{
+		ContinueOnError: true,
+		States: []State{
+			{From: from, To: to, Value: 30},
+			{From: to, To: from, Value: 10}, //no witness
+			{From: from, To: to, Value: 0},
+			{From: from, To: to, Value: 80}, //balance insufficient
+			{From: from, To: to, Value: 70},
+		},
+	}
+	statuses, err := BatchTransfer(native, contract, batch, 1000, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{TRANSFER_BATCH_SUCCESS, TRANSFER_BATCH_FAILED, TRANSFER_BATCH_SUCCESS,
+		TRANSFER_BATCH_FAILED, TRANSFER_BATCH_SUCCESS}, statuses)
+
+	//only the summary event of the batch
+	assert.Equal(t, 1, len(native.Notifications))
+	assert.Equal(t, []interface{}{TRANSFER_BATCH_NAME, uint64(3), uint64(2), "0100010001"},
+		native.Notifications[0].States)
+	assert.Equal(t, contract, native.Notifications[0].ContractAddress)
+
+	balance, err := utils.GetStorageUInt64(native, GenBalanceKey(contract, to))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), balance)
+}
@@ -0,0 +1,585 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package ontid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const MAX_CONTROLLER_MEMBERS = 64
+
+// controller of an ID is either a single ONT ID, or a group of ONT IDs of
+// which at least threshold members must sign
+type controller struct {
+	members   [][]byte
+	threshold uint64
+}
+
+func (this *controller) isGroup() bool {
+	return this.threshold > 0
+}
+
+// parseController parses a single ONT ID, or a serialized group
+func parseController(data []byte) (*controller, error) {
+	if account.VerifyID(string(data)) {
+		return &controller{members: [][]byte{data}}, nil
+	}
+	buf := bytes.NewBuffer(data)
+	n, err := utils.ReadVarUint(buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid controller: %s", err)
+	}
+	if n == 0 || n > MAX_CONTROLLER_MEMBERS {
+		return nil, fmt.Errorf("invalid controller: group size %d out of range", n)
+	}
+	members := make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, err := serialization.ReadVarBytes(buf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid controller: %s", err)
+		}
+		if !account.VerifyID(string(v)) {
+			return nil, fmt.Errorf("invalid controller: invalid member %s", string(v))
+		}
+		for _, m := range members {
+			if bytes.Equal(m, v) {
+				return nil, fmt.Errorf("invalid controller: duplicated member %s", string(v))
+			}
+		}
+		members = append(members, v)
+	}
+	threshold, err := utils.ReadVarUint(buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid controller: %s", err)
+	}
+	if threshold == 0 || threshold > n {
+		return nil, fmt.Errorf("invalid controller: threshold %d out of range", threshold)
+	}
+	if buf.Len() > 0 {
+		return nil, errors.New("invalid controller: redundant data")
+	}
+	return &controller{members: members, threshold: threshold}, nil
+}
+
+// signer is a key of an ONT ID which signs the transaction
+type signer struct {
+	id    []byte
+	index uint32
+}
+
+func deserializeSigners(data []byte) ([]signer, error) {
+	buf := bytes.NewBuffer(data)
+	n, err := utils.ReadVarUint(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n > MAX_CONTROLLER_MEMBERS {
+		return nil, fmt.Errorf("too many signers: %d", n)
+	}
+	signers := make([]signer, 0, n)
+	for i := uint64(0); i < n; i++ {
+		id, err := serialization.ReadVarBytes(buf)
+		if err != nil {
+			return nil, err
+		}
+		index, err := utils.ReadVarUint(buf)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer{id, uint32(index)})
+	}
+	return signers, nil
+}
+
+// verifySigner checks the signer's key is in use and has signed the transaction
+func verifySigner(srvc *native.NativeService, s signer) bool {
+	key, err := encodeID(s.id)
+	if err != nil {
+		return false
+	}
+	pk, err := getPk(srvc, key, s.index)
+	if err != nil || pk == nil || pk.revoked {
+		return false
+	}
+	return checkWitness(srvc, pk.key) == nil
+}
+
+// verifyControllerSigners checks the signers satisfy the controller
+func verifyControllerSigners(srvc *native.NativeService, ctrl *controller, signers []signer) error {
+	signed := 0
+	for _, m := range ctrl.members {
+		for _, s := range signers {
+			if bytes.Equal(m, s.id) && verifySigner(srvc, s) {
+				signed += 1
+				break
+			}
+		}
+	}
+	if ctrl.isGroup() && uint64(signed) < ctrl.threshold {
+		return fmt.Errorf("only %d of %d group members signed", signed, ctrl.threshold)
+	}
+	if signed == 0 {
+		return errors.New("controller did not sign")
+	}
+	return nil
+}
+
+func getController(srvc *native.NativeService, encID []byte) (*controller, error) {
+	item, err := utils.GetStorageItem(srvc, fieldKey(encID, FIELD_CONTROLLER))
+	if err != nil {
+		return nil, fmt.Errorf("get controller error: %s", err)
+	} else if item == nil {
+		return nil, nil
+	}
+	return parseController(item.Value)
+}
+
+// checkController checks the ID is managed by a controller, and the signers
+// satisfy it
+func checkController(srvc *native.NativeService, encID, signers []byte) error {
+	ctrl, err := getController(srvc, encID)
+	if err != nil {
+		return err
+	} else if ctrl == nil {
+		return errors.New("ID has no controller")
+	}
+	s, err := deserializeSigners(signers)
+	if err != nil {
+		return fmt.Errorf("invalid signers: %s", err)
+	}
+	return verifyControllerSigners(srvc, ctrl, s)
+}
+
+func regIdWithController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("register ID with controller error: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: argument 0 error, %s", err)
+	}
+	if !account.VerifyID(string(arg0)) {
+		return utils.BYTE_FALSE, errors.New("register ID with controller error: invalid ID")
+	}
+	// arg1: controller
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: argument 1 error, %s", err)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: %s", err)
+	}
+	if checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("register ID with controller error: already registered")
+	}
+	ctrl, err := parseController(arg1)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: %s", err)
+	}
+	for _, m := range ctrl.members {
+		if bytes.Equal(m, arg0) {
+			return utils.BYTE_FALSE, errors.New("register ID with controller error: ID can not control itself")
+		}
+	}
+	signers, err := deserializeSigners(arg2)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: invalid signers, %s", err)
+	}
+	if err = verifyControllerSigners(srvc, ctrl, signers); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: %s", err)
+	}
+
+	srvc.CacheDB.Put(fieldKey(key, FIELD_CONTROLLER), states.GenRawStorageItem(arg1))
+	srvc.CacheDB.Put(key, states.GenRawStorageItem([]byte{flag_exist}))
+	if err = recordHistory(srvc, key, true); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("register ID with controller error: record history error, %s", err)
+	}
+	triggerRegisterEvent(srvc, arg0)
+	return utils.BYTE_TRUE, nil
+}
+
+func verifyController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("verify controller error: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("verify controller error: argument 0 error, %s", err)
+	}
+	// arg1: signers of the controller
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("verify controller error: argument 1 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("verify controller error: %s", err)
+	}
+	if err = checkController(srvc, key, arg1); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("verify controller failed: %s", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+func addKeyByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("add key by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: public key
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: argument 1 error, %s", err)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("add key by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: %s", err)
+	}
+	if item, _ := findPk(srvc, key, arg1); item != 0 {
+		return utils.BYTE_FALSE, errors.New("add key by controller failed: already exists")
+	}
+	keyID, err := insertPk(srvc, key, arg1)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: insert public key error, %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add key by controller failed: record history error, %s", err)
+	}
+
+	triggerPublicEvent(srvc, "add", arg0, arg1, keyID)
+	return utils.BYTE_TRUE, nil
+}
+
+func removeKeyByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("remove key by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: public key
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: argument 1 error, %s", err)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("remove key by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: %s", err)
+	}
+	keyID, err := revokePk(srvc, key, arg1)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key by controller failed: record history error, %s", err)
+	}
+
+	triggerPublicEvent(srvc, "remove", arg0, arg1, keyID)
+	return utils.BYTE_TRUE, nil
+}
+
+func addServiceByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("add service by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: service
+	var arg1 service
+	if err := arg1.Deserialize(args); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: argument 1 error, %s", err)
+	}
+	if len(arg1.id) == 0 || len(arg1.endpoint) == 0 {
+		return utils.BYTE_FALSE, errors.New("add service by controller failed: argument 1 error, empty service id or endpoint")
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("add service by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: %s", err)
+	}
+	if err = insertService(srvc, key, &arg1); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service by controller failed: record history error, %s", err)
+	}
+
+	triggerServiceEvent(srvc, "add", arg0, arg1.id)
+	return utils.BYTE_TRUE, nil
+}
+
+func removeServiceByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("remove service by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: service id
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: argument 1 error, %s", err)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("remove service by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: %s", err)
+	}
+	if err = deleteService(srvc, key, arg1); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove service by controller failed: record history error, %s", err)
+	}
+
+	triggerServiceEvent(srvc, "remove", arg0, arg1)
+	return utils.BYTE_TRUE, nil
+}
+
+func addAttributesByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("add attributes by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: attributes
+	num, err := utils.ReadVarUint(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: argument 1 error, %s", err)
+	}
+	var arg1 = make([]attribute, 0)
+	for i := 0; i < int(num); i++ {
+		var v attribute
+		if err = v.Deserialize(args); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: argument 1 error, %s", err)
+		}
+		arg1 = append(arg1, v)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("add attributes by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: %s", err)
+	}
+	if err = batchInsertAttr(srvc, key, arg1); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes by controller failed: record history error, %s", err)
+	}
+
+	var paths = make([][]byte, 0)
+	for _, v := range arg1 {
+		paths = append(paths, v.key)
+	}
+	triggerAttributeEvent(srvc, "add", arg0, paths)
+	return utils.BYTE_TRUE, nil
+}
+
+func removeAttributeByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("remove attribute by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: path
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: argument 1 error, %s", err)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("remove attribute by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: %s", err)
+	}
+	ok, err := utils.LinkedlistDelete(srvc, fieldKey(key, FIELD_ATTR), arg1)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: delete error, %s", err)
+	} else if !ok {
+		return utils.BYTE_FALSE, errors.New("remove attribute by controller failed: attribute not exist")
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove attribute by controller failed: record history error, %s", err)
+	}
+
+	triggerAttributeEvent(srvc, "remove", arg0, [][]byte{arg1})
+	return utils.BYTE_TRUE, nil
+}
+
+// setRecoveryByController adds the recovery of the ID, or replaces the
+// existing one
+func setRecoveryByController(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("set recovery by controller failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: argument 0 error, %s", err)
+	}
+	// arg1: recovery address
+	arg1, err := utils.ReadAddress(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: argument 1 error, %s", err)
+	}
+	// arg2: signers of the controller
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: argument 2 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("set recovery by controller failed: ID not registered")
+	}
+	if err = checkController(srvc, key, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: %s", err)
+	}
+	re, err := getRecovery(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: %s", err)
+	}
+	if err = setRecovery(srvc, key, arg1); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery by controller failed: record history error, %s", err)
+	}
+
+	if len(re) > 0 {
+		triggerRecoveryEvent(srvc, "change", arg0, arg1)
+	} else {
+		triggerRecoveryEvent(srvc, "add", arg0, arg1)
+	}
+	return utils.BYTE_TRUE, nil
+}
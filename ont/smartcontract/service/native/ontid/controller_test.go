@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func serializeSigners(signers ...signer) []byte {
+	var buf bytes.Buffer
+	utils.WriteVarUint(&buf, uint64(len(signers)))
+	for _, s := range signers {
+		serialization.WriteVarBytes(&buf, s.id)
+		utils.WriteVarUint(&buf, uint64(s.index))
+	}
+	return buf.Bytes()
+}
+
+func TestController_Manage(t *testing.T) {
+	height := config.GetNativeUpgradeHeight()
+	acc := account.NewAccount("")
+	srvc := newTestService(t, acc)
+
+	ctrlID, _ := registerTestID(t, srvc, height, acc)
+	id, err := account.GenerateID()
+	assert.Nil(t, err)
+	key, err := encodeID([]byte(id))
+	assert.Nil(t, err)
+	signers := serializeSigners(signer{[]byte(ctrlID), 1})
+	assert.Nil(t, invoke(srvc, height, regIdWithController, id, ctrlID, signers))
+
+	// signers not satisfying the controller
+	otherID, _ := registerTestID(t, srvc, height, acc)
+	others := serializeSigners(signer{[]byte(otherID), 1})
+	svc := &service{id: []byte("hub"), typ: []byte("IdentityHub"), endpoint: []byte("https://hub.example")}
+	assert.NotNil(t, invoke(srvc, height, addServiceByController, id, svc, others))
+	assert.NotNil(t, invoke(srvc, height-1, addServiceByController, id, svc, signers))
+
+	assert.Nil(t, invoke(srvc, height, addServiceByController, id, svc, signers))
+	assert.NotNil(t, invoke(srvc, height, addServiceByController, id, svc, signers))
+	attrs := []*attribute{{key: []byte("name"), valueType: []byte("string"), value: []byte("bob")}}
+	assert.Nil(t, invoke(srvc, height, addAttributesByController, id, attrs, signers))
+	assert.NotNil(t, invoke(srvc, height, addAttributesByController, id, attrs, others))
+	recovery := common.AddressFromVmCode([]byte{1})
+	assert.Nil(t, invoke(srvc, height, setRecoveryByController, id, recovery, signers))
+
+	st, err := getIDState(srvc, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(st.services))
+	assert.Equal(t, []byte("bob"), st.attrs[0].value)
+	assert.Equal(t, recovery[:], st.recovery)
+
+	recovery2 := common.AddressFromVmCode([]byte{2})
+	assert.NotNil(t, invoke(srvc, height, setRecoveryByController, id, recovery2, others))
+	assert.Nil(t, invoke(srvc, height, setRecoveryByController, id, recovery2, signers))
+	assert.NotNil(t, invoke(srvc, height, removeServiceByController, id, []byte("hub"), others))
+	assert.Nil(t, invoke(srvc, height, removeServiceByController, id, []byte("hub"), signers))
+	assert.NotNil(t, invoke(srvc, height, removeServiceByController, id, []byte("hub"), signers))
+	assert.NotNil(t, invoke(srvc, height, removeAttributeByController, id, []byte("name"), others))
+	assert.Nil(t, invoke(srvc, height, removeAttributeByController, id, []byte("name"), signers))
+	assert.NotNil(t, invoke(srvc, height, removeAttributeByController, id, []byte("name"), signers))
+
+	st, err = getIDState(srvc, key)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(st.services))
+	assert.Equal(t, 0, len(st.attrs))
+	assert.Equal(t, recovery2[:], st.recovery)
+}
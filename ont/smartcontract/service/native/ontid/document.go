@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package ontid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+)
+
+const (
+	DID_PREFIX      = "did:ont:"
+	DID_CONTEXT     = "https://www.w3.org/ns/did/v1"
+	KEY_STATE_INUSE = "in use"
+	KEY_STATE_REVOK = "revoked"
+)
+
+//Document is the W3C DID document of an ONT ID, Threshold is set when a group
+//of controllers manages the ID
+type Document struct {
+	Context            []string              `json:"@context"`
+	Id                 string                `json:"id"`
+	Controller         []string              `json:"controller,omitempty"`
+	Threshold          uint64                `json:"controllerThreshold,omitempty"`
+	VerificationMethod []*VerificationMethod `json:"verificationMethod"`
+	Authentication     []string              `json:"authentication"`
+	Service            []*Service            `json:"service,omitempty"`
+	Attribute          []*Attribute          `json:"attribute,omitempty"`
+}
+
+//VerificationMethod is a public key of the ID, KeyState is the state reported by getKeyState
+type VerificationMethod struct {
+	Id           string `json:"id"`
+	Type         string `json:"type"`
+	Controller   string `json:"controller"`
+	PublicKeyHex string `json:"publicKeyHex"`
+	KeyState     string `json:"keyState"`
+}
+
+//Service is a service endpoint of the ID
+type Service struct {
+	Id              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+//Attribute is an ONT ID attribute, which has no counterpart in DID core
+type Attribute struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+//VerifyDID check the did is an ONT ID
+func VerifyDID(did string) error {
+	if !strings.HasPrefix(did, DID_PREFIX) || len(did) == len(DID_PREFIX) {
+		return fmt.Errorf("invalid ONT ID: %s", did)
+	}
+	return nil
+}
+
+//NewDocument build the DID document from the ID state returned by getIDState
+func NewDocument(did string, data []byte) (*Document, error) {
+	st := new(idState)
+	if err := st.Deserialize(bytes.NewBuffer(data)); err != nil {
+		return nil, fmt.Errorf("deserialize ID state error: %s", err)
+	}
+	doc := &Document{
+		Context:            []string{DID_CONTEXT},
+		Id:                 did,
+		VerificationMethod: make([]*VerificationMethod, 0, len(st.owners)),
+		Authentication:     make([]string, 0, len(st.owners)),
+	}
+	for i, v := range st.owners {
+		keyType, err := verificationKeyType(v.key)
+		if err != nil {
+			return nil, err
+		}
+		method := &VerificationMethod{
+			Id:           fmt.Sprintf("%s#keys-%d", did, i+1),
+			Type:         keyType,
+			Controller:   did,
+			PublicKeyHex: hex.EncodeToString(v.key),
+			KeyState:     KEY_STATE_INUSE,
+		}
+		if v.revoked {
+			method.KeyState = KEY_STATE_REVOK
+		} else {
+			doc.Authentication = append(doc.Authentication, method.Id)
+		}
+		doc.VerificationMethod = append(doc.VerificationMethod, method)
+	}
+	// the ID controls itself with its own keys, the recovery and the
+	// controllers are listed beside it
+	if len(st.controller) > 0 || len(st.recovery) > 0 {
+		if len(doc.Authentication) > 0 {
+			doc.Controller = append(doc.Controller, did)
+		}
+	}
+	if len(st.controller) > 0 {
+		ctrl, err := parseController(st.controller)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range ctrl.members {
+			doc.Controller = append(doc.Controller, string(m))
+		}
+		doc.Threshold = ctrl.threshold
+	}
+	if len(st.recovery) > 0 {
+		recovery, err := common.AddressParseFromBytes(st.recovery)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recovery: %s", err)
+		}
+		doc.Controller = append(doc.Controller, DID_PREFIX+recovery.ToBase58())
+	}
+	for _, v := range st.services {
+		doc.Service = append(doc.Service, &Service{
+			Id:              fmt.Sprintf("%s#%s", did, string(v.id)),
+			Type:            string(v.typ),
+			ServiceEndpoint: string(v.endpoint),
+		})
+	}
+	for _, v := range st.attrs {
+		doc.Attribute = append(doc.Attribute, &Attribute{
+			Key:   string(v.key),
+			Type:  string(v.valueType),
+			Value: string(v.value),
+		})
+	}
+	return doc, nil
+}
+
+func verificationKeyType(key []byte) (string, error) {
+	pk, err := keypair.DeserializePublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %s", err)
+	}
+	switch keypair.GetKeyType(pk) {
+	case keypair.PK_ECDSA:
+		return "EcdsaSecp256r1VerificationKey2019", nil
+	case keypair.PK_SM2:
+		return "SM2VerificationKey2019", nil
+	case keypair.PK_EDDSA:
+		return "Ed25519VerificationKey2018", nil
+	default:
+		return "", fmt.Errorf("unsupported key type")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDocument(t *testing.T) {
+	key1 := keypair.SerializePublicKey(account.NewAccount("").PublicKey)
+	key2 := keypair.SerializePublicKey(account.NewAccount("").PublicKey)
+	member1, _ := account.GenerateID()
+	member2, _ := account.GenerateID()
+	var ctrl bytes.Buffer
+	utils.WriteVarUint(&ctrl, 2)
+	serialization.WriteVarBytes(&ctrl, []byte(member1))
+	serialization.WriteVarBytes(&ctrl, []byte(member2))
+	utils.WriteVarUint(&ctrl, 1)
+	recovery := common.AddressFromVmCode([]byte{1})
+
+	st := &idState{
+		owners:     []*owner{{key: key1}, {key: key2, revoked: true}},
+		attrs:      []*attribute{{key: []byte("name"), valueType: []byte("string"), value: []byte("alice")}},
+		recovery:   recovery[:],
+		services:   []*service{{id: []byte("hub"), typ: []byte("IdentityHub"), endpoint: []byte("https://hub.example")}},
+		controller: ctrl.Bytes(),
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, st.Serialize(&buf))
+	st2 := new(idState)
+	assert.Nil(t, st2.Deserialize(bytes.NewBuffer(buf.Bytes())))
+	assert.Equal(t, st, st2)
+
+	did := "did:ont:AXjJnU3a6W9ZR6tdDZSAzuwG2tUeGamaTn"
+	doc, err := NewDocument(did, buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{DID_CONTEXT}, doc.Context)
+	assert.Equal(t, did, doc.Id)
+	assert.Equal(t, 2, len(doc.VerificationMethod))
+	assert.Equal(t, did+"#keys-1", doc.VerificationMethod[0].Id)
+	assert.Equal(t, hex.EncodeToString(key1), doc.VerificationMethod[0].PublicKeyHex)
+	assert.Equal(t, KEY_STATE_INUSE, doc.VerificationMethod[0].KeyState)
+	assert.Equal(t, KEY_STATE_REVOK, doc.VerificationMethod[1].KeyState)
+	assert.Equal(t, []string{did + "#keys-1"}, doc.Authentication)
+	assert.Equal(t, []string{did, member1, member2, DID_PREFIX + recovery.ToBase58()}, doc.Controller)
+	assert.Equal(t, uint64(1), doc.Threshold)
+	assert.Equal(t, did+"#hub", doc.Service[0].Id)
+	assert.Equal(t, "https://hub.example", doc.Service[0].ServiceEndpoint)
+	assert.Equal(t, "alice", doc.Attribute[0].Value)
+
+	// an ID managed only by its controller
+	st = &idState{controller: []byte(member1)}
+	buf.Reset()
+	assert.Nil(t, st.Serialize(&buf))
+	doc, err = NewDocument(did, buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{member1}, doc.Controller)
+	assert.Equal(t, uint64(0), doc.Threshold)
+	assert.Equal(t, 0, len(doc.Authentication))
+
+	_, err = NewDocument(did, []byte{1})
+	assert.NotNil(t, err)
+}
+
+func TestVerifyDID(t *testing.T) {
+	assert.Nil(t, VerifyDID("did:ont:AXjJnU3a6W9ZR6tdDZSAzuwG2tUeGamaTn"))
+	assert.NotNil(t, VerifyDID("did:ont:"))
+	assert.NotNil(t, VerifyDID("did:example:123"))
+}
@@ -57,6 +57,11 @@ func triggerAttributeEvent(srvc *native.NativeService, op string, id []byte, pat
 	newEvent(srvc, st)
 }
 
+func triggerServiceEvent(srvc *native.NativeService, op string, id, serviceID []byte) {
+	st := []interface{}{"Service", op, string(id), hex.EncodeToString(serviceID)}
+	newEvent(srvc, st)
+}
+
 func triggerRecoveryEvent(srvc *native.NativeService, op string, id []byte, addr common.Address) {
 	st := []string{"Recovery", op, string(id), addr.ToHexString()}
 	newEvent(srvc, st)
@@ -0,0 +1,345 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package ontid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+// idState is the full state of an ID, including the revoked keys
+type idState struct {
+	owners     []*owner
+	attrs      []*attribute
+	recovery   []byte
+	services   []*service
+	controller []byte
+}
+
+func (this *idState) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(len(this.owners))); err != nil {
+		return fmt.Errorf("serialize owners length error: %s", err)
+	}
+	for _, v := range this.owners {
+		if err := v.Serialize(w); err != nil {
+			return fmt.Errorf("serialize owner error: %s", err)
+		}
+	}
+	if err := utils.WriteVarUint(w, uint64(len(this.attrs))); err != nil {
+		return fmt.Errorf("serialize attributes length error: %s", err)
+	}
+	for _, v := range this.attrs {
+		if err := v.Serialize(w); err != nil {
+			return fmt.Errorf("serialize attribute error: %s", err)
+		}
+	}
+	if err := serialization.WriteVarBytes(w, this.recovery); err != nil {
+		return fmt.Errorf("serialize recovery error: %s", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(len(this.services))); err != nil {
+		return fmt.Errorf("serialize services length error: %s", err)
+	}
+	for _, v := range this.services {
+		if err := v.Serialize(w); err != nil {
+			return fmt.Errorf("serialize service error: %s", err)
+		}
+	}
+	if err := serialization.WriteVarBytes(w, this.controller); err != nil {
+		return fmt.Errorf("serialize controller error: %s", err)
+	}
+	return nil
+}
+
+func (this *idState) Deserialize(r io.Reader) error {
+	n, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("deserialize owners length error: %s", err)
+	}
+	this.owners = make([]*owner, 0)
+	for i := uint64(0); i < n; i++ {
+		v := new(owner)
+		if err := v.Deserialize(r); err != nil {
+			return fmt.Errorf("deserialize owner error: %s", err)
+		}
+		this.owners = append(this.owners, v)
+	}
+	n, err = utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("deserialize attributes length error: %s", err)
+	}
+	this.attrs = make([]*attribute, 0)
+	for i := uint64(0); i < n; i++ {
+		v := new(attribute)
+		if err := v.Deserialize(r); err != nil {
+			return fmt.Errorf("deserialize attribute error: %s", err)
+		}
+		this.attrs = append(this.attrs, v)
+	}
+	this.recovery, err = serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("deserialize recovery error: %s", err)
+	}
+	n, err = utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("deserialize services length error: %s", err)
+	}
+	this.services = make([]*service, 0)
+	for i := uint64(0); i < n; i++ {
+		v := new(service)
+		if err := v.Deserialize(r); err != nil {
+			return fmt.Errorf("deserialize service error: %s", err)
+		}
+		this.services = append(this.services, v)
+	}
+	this.controller, err = serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("deserialize controller error: %s", err)
+	}
+	return nil
+}
+
+func getIDState(srvc *native.NativeService, encID []byte) (*idState, error) {
+	owners, err := getAllPk(srvc, fieldKey(encID, FIELD_PK))
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := getAllAttr(srvc, encID)
+	if err != nil {
+		return nil, err
+	}
+	recovery, err := getRecovery(srvc, encID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := getAllService(srvc, encID)
+	if err != nil {
+		return nil, err
+	}
+	st := &idState{owners: owners, recovery: recovery, services: services}
+	ctrl, err := utils.GetStorageItem(srvc, fieldKey(encID, FIELD_CONTROLLER))
+	if err != nil {
+		return nil, err
+	} else if ctrl != nil {
+		st.controller = ctrl.Value
+	}
+	buf := bytes.NewBuffer(attrs)
+	for buf.Len() > 0 {
+		v := new(attribute)
+		if err := v.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize attribute error: %s", err)
+		}
+		st.attrs = append(st.attrs, v)
+	}
+	return st, nil
+}
+
+// MAX_HISTORY_RECORDS is the number of state records kept for an ID, the
+// records of older heights are overwritten
+const MAX_HISTORY_RECORDS = 256
+
+// historyHead counts the state records of an ID. complete is false when
+// the ID was registered before its history was recorded.
+type historyHead struct {
+	count    uint64
+	complete bool
+}
+
+// first returns the index of the oldest record kept
+func (this *historyHead) first() uint64 {
+	if this.count > MAX_HISTORY_RECORDS {
+		return this.count - MAX_HISTORY_RECORDS
+	}
+	return 0
+}
+
+func fieldKey(encID []byte, field byte) []byte {
+	key := make([]byte, 0, len(encID)+1)
+	key = append(key, encID...)
+	return append(key, field)
+}
+
+func historyItemKey(encID []byte, index uint64) []byte {
+	var buf bytes.Buffer
+	buf.Write(fieldKey(encID, FIELD_HISTORY))
+	serialization.WriteUint64(&buf, index%MAX_HISTORY_RECORDS)
+	return buf.Bytes()
+}
+
+func getHistoryHead(srvc *native.NativeService, encID []byte) (*historyHead, error) {
+	item, err := utils.GetStorageItem(srvc, fieldKey(encID, FIELD_HISTORY))
+	if err != nil {
+		return nil, err
+	}
+	head := new(historyHead)
+	if item == nil {
+		return head, nil
+	}
+	buf := bytes.NewBuffer(item.Value)
+	head.count, err = serialization.ReadUint64(buf)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize history count error: %s", err)
+	}
+	head.complete, err = serialization.ReadBool(buf)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize history flag error: %s", err)
+	}
+	return head, nil
+}
+
+func getHistoryItem(srvc *native.NativeService, encID []byte, index uint64) (uint32, []byte, error) {
+	item, err := utils.GetStorageItem(srvc, historyItemKey(encID, index))
+	if err != nil {
+		return 0, nil, err
+	} else if item == nil {
+		return 0, nil, fmt.Errorf("history item %d not found", index)
+	}
+	buf := bytes.NewBuffer(item.Value)
+	height, err := serialization.ReadUint32(buf)
+	if err != nil {
+		return 0, nil, fmt.Errorf("deserialize history height error: %s", err)
+	}
+	return height, buf.Bytes(), nil
+}
+
+// recordHistory saves the current state of the ID at the current block
+// height, it must be called after every change of the ID. Changes in one
+// block share one record, and only the latest MAX_HISTORY_RECORDS records
+// are kept.
+func recordHistory(srvc *native.NativeService, encID []byte, register bool) error {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return nil
+	}
+	head, err := getHistoryHead(srvc, encID)
+	if err != nil {
+		return err
+	}
+	index := head.count
+	if head.count == 0 {
+		head.complete = register
+	} else {
+		height, _, err := getHistoryItem(srvc, encID, head.count-1)
+		if err != nil {
+			return err
+		}
+		if height == srvc.Height {
+			index = head.count - 1
+		}
+	}
+	st, err := getIDState(srvc, encID)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	serialization.WriteUint32(&buf, srvc.Height)
+	if err := st.Serialize(&buf); err != nil {
+		return err
+	}
+	srvc.CacheDB.Put(historyItemKey(encID, index), states.GenRawStorageItem(buf.Bytes()))
+	if index < head.count {
+		return nil
+	}
+
+	head.count += 1
+	buf.Reset()
+	serialization.WriteUint64(&buf, head.count)
+	serialization.WriteBool(&buf, head.complete)
+	srvc.CacheDB.Put(fieldKey(encID, FIELD_HISTORY), states.GenRawStorageItem(buf.Bytes()))
+	return nil
+}
+
+// getHistoryState returns the serialized state of the ID after the block
+// at height was executed, or nil if the ID was not registered yet.
+func getHistoryState(srvc *native.NativeService, encID []byte, height uint32) ([]byte, error) {
+	head, err := getHistoryHead(srvc, encID)
+	if err != nil {
+		return nil, err
+	}
+	// binary search the last record not later than height
+	var lo, hi uint64 = head.first(), head.count
+	var state []byte
+	for lo < hi {
+		mid := (lo + hi) / 2
+		h, st, err := getHistoryItem(srvc, encID, mid)
+		if err != nil {
+			return nil, err
+		}
+		if h <= height {
+			state = st
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if state == nil && head.first() > 0 {
+		return nil, errors.New("history of the ID at this height is overwritten")
+	}
+	if state == nil && !head.complete && checkIDExistence(srvc, encID) {
+		return nil, errors.New("history of the ID is not recorded at this height")
+	}
+	return state, nil
+}
+
+func GetIDState(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return nil, errors.New("get ID state error: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return nil, fmt.Errorf("get ID state error: argument 0 error, %s", err)
+	}
+	// arg1: block height, 0 for the latest state
+	arg1, err := utils.ReadVarUint(args)
+	if err != nil {
+		return nil, fmt.Errorf("get ID state error: argument 1 error, %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return nil, fmt.Errorf("get ID state error: %s", err)
+	}
+	if arg1 != 0 && arg1+1 < uint64(srvc.Height) {
+		res, err := getHistoryState(srvc, key, uint32(arg1))
+		if err != nil {
+			return nil, fmt.Errorf("get ID state error: %s", err)
+		}
+		return res, nil
+	}
+
+	if !checkIDExistence(srvc, key) {
+		return nil, nil
+	}
+	st, err := getIDState(srvc, key)
+	if err != nil {
+		return nil, fmt.Errorf("get ID state error: %s", err)
+	}
+	var buf bytes.Buffer
+	if err := st.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("get ID state error: %s", err)
+	}
+	return buf.Bytes(), nil
+}
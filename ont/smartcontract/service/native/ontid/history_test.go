@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontid
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+//newTestService return an ontid native service signed by signers
+func newTestService(t *testing.T, signers ...*account.Account) *native.NativeService {
+	addrs := make([]common.Address, 0, len(signers))
+	for _, acc := range signers {
+		addrs = append(addrs, acc.Address)
+	}
+	return testsuite.NewNativeService(t, utils.OntIDContractAddress, 0, addrs...)
+}
+
+//invoke call method at height, args are serialized as var bytes unless they are already serialized
+func invoke(srvc *native.NativeService, height uint32, method native.Handler, args ...interface{}) error {
+	var buf bytes.Buffer
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case []byte:
+			serialization.WriteVarBytes(&buf, v)
+		case string:
+			serialization.WriteVarBytes(&buf, []byte(v))
+		case *service:
+			v.Serialize(&buf)
+		case []*attribute:
+			utils.WriteVarUint(&buf, uint64(len(v)))
+			for _, attr := range v {
+				attr.Serialize(&buf)
+			}
+		case common.Address:
+			utils.WriteAddress(&buf, v)
+		case uint64:
+			utils.WriteVarUint(&buf, v)
+		default:
+			panic(fmt.Sprintf("unsupported argument %T", arg))
+		}
+	}
+	srvc.Height = height
+	srvc.Input = buf.Bytes()
+	_, err := method(srvc)
+	return err
+}
+
+func registerTestID(t *testing.T, srvc *native.NativeService, height uint32, acc *account.Account) (string, []byte) {
+	id, err := account.GenerateID()
+	assert.Nil(t, err)
+	assert.Nil(t, invoke(srvc, height, regIdWithPublicKey, id, keypair.SerializePublicKey(acc.PublicKey)))
+	key, err := encodeID([]byte(id))
+	assert.Nil(t, err)
+	return id, key
+}
+
+func historyIDState(t *testing.T, srvc *native.NativeService, key []byte, height uint32) *idState {
+	data, err := getHistoryState(srvc, key, height)
+	assert.Nil(t, err)
+	if data == nil {
+		return nil
+	}
+	st := new(idState)
+	assert.Nil(t, st.Deserialize(bytes.NewBuffer(data)))
+	return st
+}
+
+func TestHistory_UpgradeHeight(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	acc := account.NewAccount("")
+	srvc := newTestService(t, acc)
+
+	id, key := registerTestID(t, srvc, upgrade-1, acc)
+	head, err := getHistoryHead(srvc, key)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), head.count)
+	assert.NotNil(t, invoke(srvc, upgrade-1, GetIDState, id, uint64(0)))
+
+	acc2 := account.NewAccount("")
+	pk := keypair.SerializePublicKey(acc.PublicKey)
+	assert.Nil(t, invoke(srvc, upgrade, addKey, id, keypair.SerializePublicKey(acc2.PublicKey), pk))
+	head, err = getHistoryHead(srvc, key)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), head.count)
+	assert.False(t, head.complete)
+
+	// the state before the first record is unknown
+	_, err = getHistoryState(srvc, key, upgrade-1)
+	assert.NotNil(t, err)
+	st := historyIDState(t, srvc, key, upgrade)
+	assert.Equal(t, 2, len(st.owners))
+	assert.Nil(t, invoke(srvc, upgrade, GetIDState, id, uint64(0)))
+}
+
+func TestHistory_OneRecordPerBlock(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	acc := account.NewAccount("")
+	srvc := newTestService(t, acc)
+	pk := keypair.SerializePublicKey(acc.PublicKey)
+
+	id, key := registerTestID(t, srvc, upgrade+1, acc)
+	for _, name := range []string{"hub", "mail"} {
+		svc := &service{id: []byte(name), typ: []byte("Endpoint"), endpoint: []byte("https://" + name)}
+		assert.Nil(t, invoke(srvc, upgrade+3, addService, id, svc, pk))
+	}
+	head, err := getHistoryHead(srvc, key)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), head.count)
+	assert.True(t, head.complete)
+
+	assert.Nil(t, historyIDState(t, srvc, key, upgrade))
+	assert.Equal(t, 0, len(historyIDState(t, srvc, key, upgrade+2).services))
+	assert.Equal(t, 2, len(historyIDState(t, srvc, key, upgrade+3).services))
+}
+
+func TestHistory_Bounded(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	acc := account.NewAccount("")
+	srvc := newTestService(t, acc)
+	pk := keypair.SerializePublicKey(acc.PublicKey)
+
+	id, key := registerTestID(t, srvc, upgrade, acc)
+	for i := uint32(1); i <= MAX_HISTORY_RECORDS; i++ {
+		attr := []*attribute{{key: []byte("count"), valueType: []byte("string"), value: []byte(fmt.Sprint(i))}}
+		assert.Nil(t, invoke(srvc, upgrade+i, addAttributes, id, attr, pk))
+	}
+	head, err := getHistoryHead(srvc, key)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(MAX_HISTORY_RECORDS+1), head.count)
+	assert.Equal(t, uint64(1), head.first())
+
+	// the registration record is overwritten
+	_, err = getHistoryState(srvc, key, upgrade)
+	assert.NotNil(t, err)
+	st := historyIDState(t, srvc, key, upgrade+1)
+	assert.Equal(t, []byte("1"), st.attrs[0].value)
+	st = historyIDState(t, srvc, key, upgrade+MAX_HISTORY_RECORDS+5)
+	assert.Equal(t, []byte(fmt.Sprint(MAX_HISTORY_RECORDS)), st.attrs[0].value)
+}
@@ -36,9 +36,21 @@ func RegisterIDContract(srvc *native.NativeService) {
 	srvc.Register("addAttributes", addAttributes)
 	srvc.Register("removeAttribute", removeAttribute)
 	srvc.Register("verifySignature", verifySignature)
+	srvc.Register("addService", addService)
+	srvc.Register("removeService", removeService)
+	srvc.Register("regIDWithController", regIdWithController)
+	srvc.Register("verifyController", verifyController)
+	srvc.Register("addKeyByController", addKeyByController)
+	srvc.Register("removeKeyByController", removeKeyByController)
+	srvc.Register("addServiceByController", addServiceByController)
+	srvc.Register("removeServiceByController", removeServiceByController)
+	srvc.Register("addAttributesByController", addAttributesByController)
+	srvc.Register("removeAttributeByController", removeAttributeByController)
+	srvc.Register("setRecoveryByController", setRecoveryByController)
 	srvc.Register("getPublicKeys", GetPublicKeys)
 	srvc.Register("getKeyState", GetKeyState)
 	srvc.Register("getAttributes", GetAttributes)
 	srvc.Register("getDDO", GetDDO)
+	srvc.Register("getIDState", GetIDState)
 	return
 }
@@ -88,6 +88,9 @@ func regIdWithPublicKey(srvc *native.NativeService) ([]byte, error) {
 	}
 	// set flags
 	srvc.CacheDB.Put(key, states.GenRawStorageItem([]byte{flag_exist}))
+	if err = recordHistory(srvc, key, true); err != nil {
+		return utils.BYTE_FALSE, errors.New("register ONT ID error: record history error, " + err.Error())
+	}
 
 	triggerRegisterEvent(srvc, arg0)
 
@@ -158,6 +161,9 @@ func regIdWithAttributes(srvc *native.NativeService) ([]byte, error) {
 	}
 
 	srvc.CacheDB.Put(key, states.GenRawStorageItem([]byte{flag_exist}))
+	if err = recordHistory(srvc, key, true); err != nil {
+		return utils.BYTE_FALSE, errors.New("register ID with attributes error: record history error: " + err.Error())
+	}
 	triggerRegisterEvent(srvc, arg0)
 	return utils.BYTE_TRUE, nil
 }
@@ -217,6 +223,9 @@ func addKey(srvc *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, errors.New("add key failed: insert public key error, " + err.Error())
 	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, errors.New("add key failed: record history error, " + err.Error())
+	}
 
 	triggerPublicEvent(srvc, "add", arg0, arg1, keyID)
 
@@ -268,6 +277,9 @@ func removeKey(srvc *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("remove key failed: %s", err)
 	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("remove key failed: record history error, %s", err)
+	}
 
 	triggerPublicEvent(srvc, "remove", arg0, arg1, keyID)
 
@@ -317,6 +329,9 @@ func addRecovery(srvc *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, errors.New("add recovery failed: " + err.Error())
 	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, errors.New("add recovery failed: record history error, " + err.Error())
+	}
 
 	triggerRecoveryEvent(srvc, "add", arg0, arg1)
 
@@ -363,6 +378,9 @@ func changeRecovery(srvc *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, errors.New("change recovery failed: " + err.Error())
 	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, errors.New("change recovery failed: record history error, " + err.Error())
+	}
 
 	triggerRecoveryEvent(srvc, "change", arg0, arg1)
 	return utils.BYTE_TRUE, nil
@@ -416,6 +434,9 @@ func addAttributes(srvc *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("add attributes failed, %s", err)
 	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add attributes failed, record history error: %s", err)
+	}
 
 	var paths = make([][]byte, 0)
 	for _, v := range arg1 {
@@ -465,6 +486,9 @@ func removeAttribute(srvc *native.NativeService) ([]byte, error) {
 	} else if !ok {
 		return utils.BYTE_FALSE, errors.New("remove attribute failed: attribute not exist")
 	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, errors.New("remove attribute failed: record history error, " + err.Error())
+	}
 
 	triggerAttributeEvent(srvc, "remove", arg0, [][]byte{arg1})
 	return utils.BYTE_TRUE, nil
@@ -0,0 +1,229 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package ontid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+type service struct {
+	id       []byte
+	typ      []byte
+	endpoint []byte
+}
+
+func (this *service) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.id); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.typ); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.endpoint); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *service) Deserialize(r io.Reader) error {
+	id, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return err
+	}
+	typ, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return err
+	}
+	endpoint, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return err
+	}
+	this.id = id
+	this.typ = typ
+	this.endpoint = endpoint
+	return nil
+}
+
+func getAllService(srvc *native.NativeService, encID []byte) ([]*service, error) {
+	val, err := utils.GetStorageItem(srvc, fieldKey(encID, FIELD_SERVICE))
+	if err != nil {
+		return nil, fmt.Errorf("get storage error, %s", err)
+	}
+	if val == nil {
+		return nil, nil
+	}
+	buf := bytes.NewBuffer(val.Value)
+	services := make([]*service, 0)
+	for buf.Len() > 0 {
+		var t = new(service)
+		err = t.Deserialize(buf)
+		if err != nil {
+			return nil, fmt.Errorf("deserialize services error, %s", err)
+		}
+		services = append(services, t)
+	}
+	return services, nil
+}
+
+func putAllService(srvc *native.NativeService, encID []byte, val []*service) error {
+	key := fieldKey(encID, FIELD_SERVICE)
+	if len(val) == 0 {
+		srvc.CacheDB.Delete(key)
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, i := range val {
+		err := i.Serialize(&buf)
+		if err != nil {
+			return fmt.Errorf("serialize service error, %s", err)
+		}
+	}
+	var v states.StorageItem
+	v.Value = buf.Bytes()
+	srvc.CacheDB.Put(key, v.ToArray())
+	return nil
+}
+
+func insertService(srvc *native.NativeService, encID []byte, svc *service) error {
+	services, err := getAllService(srvc, encID)
+	if err != nil {
+		return err
+	}
+	for _, v := range services {
+		if bytes.Equal(v.id, svc.id) {
+			return errors.New("service already exists")
+		}
+	}
+	return putAllService(srvc, encID, append(services, svc))
+}
+
+func deleteService(srvc *native.NativeService, encID, serviceID []byte) error {
+	services, err := getAllService(srvc, encID)
+	if err != nil {
+		return err
+	}
+	for i, v := range services {
+		if bytes.Equal(v.id, serviceID) {
+			return putAllService(srvc, encID, append(services[:i], services[i+1:]...))
+		}
+	}
+	return errors.New("service not exist")
+}
+
+func addService(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("add service failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed, argument 0 error: %s", err)
+	}
+	// arg1: service
+	var arg1 service
+	if err := arg1.Deserialize(args); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed, argument 1 error: %s", err)
+	}
+	if len(arg1.id) == 0 || len(arg1.endpoint) == 0 {
+		return utils.BYTE_FALSE, errors.New("add service failed, argument 1 error: empty service id or endpoint")
+	}
+	// arg2: operator's public key
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed, argument 2 error: %s", err)
+	}
+
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("add service failed, ID not registered")
+	}
+	if !isOwner(srvc, key, arg2) {
+		return utils.BYTE_FALSE, errors.New("add service failed, no authorization")
+	}
+	if err = checkWitness(srvc, arg2); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed, %s", err)
+	}
+
+	if err = insertService(srvc, key, &arg1); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed, %s", err)
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("add service failed, record history error: %s", err)
+	}
+
+	triggerServiceEvent(srvc, "add", arg0, arg1.id)
+	return utils.BYTE_TRUE, nil
+}
+
+func removeService(srvc *native.NativeService) ([]byte, error) {
+	if srvc.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, errors.New("remove service failed: block num is not reached for this func")
+	}
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: argument 0 error")
+	}
+	// arg1: service id
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: argument 1 error")
+	}
+	// arg2: operator's public key
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: argument 2 error")
+	}
+
+	if err = checkWitness(srvc, arg2); err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: " + err.Error())
+	}
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: " + err.Error())
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("remove service failed: ID not registered")
+	}
+	if !isOwner(srvc, key, arg2) {
+		return utils.BYTE_FALSE, errors.New("remove service failed: no authorization")
+	}
+
+	if err = deleteService(srvc, key, arg1); err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: " + err.Error())
+	}
+	if err = recordHistory(srvc, key, false); err != nil {
+		return utils.BYTE_FALSE, errors.New("remove service failed: record history error, " + err.Error())
+	}
+	triggerServiceEvent(srvc, "remove", arg0, arg1)
+	return utils.BYTE_TRUE, nil
+}
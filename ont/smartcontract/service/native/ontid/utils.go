@@ -48,9 +48,12 @@ const (
 	FIELD_VERSION byte = 0
 	FLAG_VERSION  byte = 0x01
 
-	FIELD_PK       byte = 1
-	FIELD_ATTR     byte = 2
-	FIELD_RECOVERY byte = 3
+	FIELD_PK         byte = 1
+	FIELD_ATTR       byte = 2
+	FIELD_RECOVERY   byte = 3
+	FIELD_HISTORY    byte = 4
+	FIELD_SERVICE    byte = 5
+	FIELD_CONTROLLER byte = 6
 )
 
 func encodeID(id []byte) ([]byte, error) {
@@ -64,6 +67,12 @@ func encodeID(id []byte) ([]byte, error) {
 	return enc, nil
 }
 
+//CheckID return error if id is malformed and rejected by the ONT ID contract
+func CheckID(id []byte) error {
+	_, err := encodeID(id)
+	return err
+}
+
 func decodeID(data []byte) ([]byte, error) {
 	prefix := len(utils.OntIDContractAddress)
 	size := len(data)
@@ -0,0 +1,385 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/common/serialization"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/vm/neovm/types"
+)
+
+const (
+	//function name
+	SCHEDULE          = "schedule"
+	CANCEL            = "cancel"
+	GET_CALLBACK      = "getCallback"
+	EXECUTE_SCHEDULED = "executeScheduled"
+
+	//key prefix
+	CALLBACK_INDEX = "callbackIndex"
+	CALLBACK       = "callback"
+	HEIGHT         = "height"
+
+	MAX_CALLBACKS_PER_HEIGHT        = 32
+	MAX_GAS_LIMIT            uint64 = 20000000
+)
+
+//Init scheduler contract address
+func InitScheduler() {
+	native.Contracts[utils.SchedulerContractAddress] = RegisterSchedulerContract
+}
+
+//Register methods of scheduler contract
+func RegisterSchedulerContract(native *native.NativeService) {
+	native.Register(SCHEDULE, Schedule)
+	native.Register(CANCEL, Cancel)
+	native.Register(GET_CALLBACK, GetCallback)
+	native.Register(EXECUTE_SCHEDULED, ExecuteScheduled)
+}
+
+//GenHeightKey return the storage key of callbacks due at height, without the contract address
+func GenHeightKey(height uint32) []byte {
+	bf := new(bytes.Buffer)
+	serialization.WriteUint32(bf, height)
+	return append([]byte(HEIGHT), bf.Bytes()...)
+}
+
+//Schedule register a callback at a future height, the ONG of gas limit is transferred from
+//the registrant to the scheduler contract. Return the callback id
+func Schedule(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, block num is not reached for this func")
+	}
+	params := new(ScheduleParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, deserialize params error: %v", err)
+	}
+	if params.Height <= native.Height {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, height %d is not after current height %d", params.Height,
+			native.Height)
+	}
+	if params.GasLimit < neovm.MIN_TRANSACTION_GAS || params.GasLimit > MAX_GAS_LIMIT {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, gas limit should be between %d and %d",
+			neovm.MIN_TRANSACTION_GAS, MAX_GAS_LIMIT)
+	}
+	if params.GasPrice == 0 || params.GasPrice > math.MaxUint64/params.GasLimit {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, invalid gas price %d", params.GasPrice)
+	}
+	dep, err := native.CacheDB.GetContract(params.Contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, get contract error: %v", err)
+	}
+	if dep == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, contract %s not exists", params.Contract.ToHexString())
+	}
+	if err := utils.ValidateOwner(native, params.Registrant); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, %v", err)
+	}
+
+	ids, err := getHeightIds(native, params.Height)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, %v", err)
+	}
+	if len(ids) >= MAX_CALLBACKS_PER_HEIGHT {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, callbacks of height %d reach the limit %d", params.Height,
+			MAX_CALLBACKS_PER_HEIGHT)
+	}
+	index, err := utils.GetStorageUInt64(native, utils.ConcatKey(utils.SchedulerContractAddress, []byte(CALLBACK_INDEX)))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, get callback index error: %v", err)
+	}
+	callback := &Callback{
+		Id:         index + 1,
+		Registrant: params.Registrant,
+		Contract:   params.Contract,
+		Method:     params.Method,
+		Args:       params.Args,
+		Height:     params.Height,
+		GasLimit:   params.GasLimit,
+		GasPrice:   params.GasPrice,
+		CodeHash:   common.AddressFromVmCode(dep.Code),
+	}
+	if err := putCallback(native, callback); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, %v", err)
+	}
+	if err := putHeightIds(native, callback.Height, append(ids, callback.Id)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(utils.SchedulerContractAddress, []byte(CALLBACK_INDEX)),
+		utils.GenUInt64StorageItem(callback.Id))
+
+	if err := prepay(native, callback); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("schedule, prepay gas error: %v", err)
+	}
+	pushEvent(native, []interface{}{"Schedule", callback.Id, callback.Contract.ToHexString(), callback.Height})
+	return types.BigIntToBytes(new(big.Int).SetUint64(callback.Id)), nil
+}
+
+//Cancel remove a pending callback, the prepaid ONG is refunded to the registrant
+func Cancel(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, block num is not reached for this func")
+	}
+	params := new(CancelParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, deserialize params error: %v", err)
+	}
+	callback, err := getCallback(native, params.Id)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, %v", err)
+	}
+	if callback == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, callback %d not exists", params.Id)
+	}
+	if err := utils.ValidateOwner(native, callback.Registrant); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, %v", err)
+	}
+
+	ids, err := getHeightIds(native, callback.Height)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, %v", err)
+	}
+	for i, id := range ids {
+		if id == callback.Id {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+	if err := putHeightIds(native, callback.Height, ids); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, %v", err)
+	}
+	native.CacheDB.Delete(callbackKey(callback.Id))
+	if err := transferOng(native, utils.SchedulerContractAddress, callback.Registrant, callback.prepaid()); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel, refund gas error: %v", err)
+	}
+	pushEvent(native, []interface{}{"Cancel", callback.Id})
+	return utils.BYTE_TRUE, nil
+}
+
+//GetCallback return the serialized pending callback, empty if not exists or already executed
+func GetCallback(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return nil, fmt.Errorf("getCallback, block num is not reached for this func")
+	}
+	id, err := utils.ReadVarUint(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("getCallback, deserialize id error: %v", err)
+	}
+	item, err := utils.GetStorageItem(native, callbackKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("getCallback, get callback error: %v", err)
+	}
+	if item == nil {
+		return []byte{}, nil
+	}
+	return item.Value, nil
+}
+
+//ExecuteScheduled execute the callbacks due at current height. It is only invoked directly by the system
+//transaction at the beginning of block, which has no signature and the block height as nonce. Each callback
+//is executed as a transaction on its own, a failed callback is reverted without failing the others, the
+//consumed gas is charged in both cases
+func ExecuteScheduled(native *native.NativeService) ([]byte, error) {
+	if native.Height < config.GetNativeUpgradeHeight() {
+		return utils.BYTE_FALSE, fmt.Errorf("executeScheduled, block num is not reached for this func")
+	}
+	if len(native.Tx.Sigs) != 0 || native.Tx.Nonce != native.Height ||
+		native.ContextRef.CallingContext() != native.ContextRef.EntryContext() {
+		return utils.BYTE_FALSE, fmt.Errorf("executeScheduled, only invoked by system transaction")
+	}
+	ids, err := getHeightIds(native, native.Height)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("executeScheduled, %v", err)
+	}
+	// removed before execution, so that the callbacks can't be executed again by reentrance
+	native.CacheDB.Delete(heightKey(native.Height))
+	for _, id := range ids {
+		callback, err := getCallback(native, id)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("executeScheduled, %v", err)
+		}
+		if callback == nil {
+			continue
+		}
+		native.CacheDB.Delete(callbackKey(id))
+		if err := execute(native, callback); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("executeScheduled, callback %d: %v", id, err)
+		}
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//execute invoke the callback with its own gas and step limit, then pay the consumed gas to governance
+//contract and refund the rest
+func execute(native *native.NativeService, callback *Callback) error {
+	gas := native.ContextRef.GetRemainGas()
+	_, err := native.ContextRef.InvokeAsTransaction(&callbackEngine{native: native, callback: callback},
+		callback.GasLimit)
+	if err != nil {
+		log.Debugf("[executeScheduled] callback %d of contract %s error: %s", callback.Id,
+			callback.Contract.ToHexString(), err)
+	}
+	consumed := gas - native.ContextRef.GetRemainGas()
+	if consumed < neovm.MIN_TRANSACTION_GAS {
+		consumed = neovm.MIN_TRANSACTION_GAS
+	}
+	if consumed > callback.GasLimit {
+		consumed = callback.GasLimit
+	}
+	fee := consumed * callback.GasPrice
+	if err := transferOng(native, utils.SchedulerContractAddress, utils.GovernanceContractAddress, fee); err != nil {
+		return fmt.Errorf("charge gas error: %v", err)
+	}
+	if refund := callback.prepaid() - fee; refund > 0 {
+		if err := transferOng(native, utils.SchedulerContractAddress, callback.Registrant, refund); err != nil {
+			return fmt.Errorf("refund gas error: %v", err)
+		}
+	}
+	pushEvent(native, []interface{}{"Execute", callback.Id, err == nil, consumed})
+	return nil
+}
+
+//callbackEngine execute the args script and the callee of callback as a whole, so that both of them
+//are limited by the gas limit of callback and reverted on failure
+type callbackEngine struct {
+	native   *native.NativeService
+	callback *Callback
+}
+
+//Invoke call the contract with the registrant as the calling context, so that CheckWitness
+//of the registrant passes in the callee. The callback is skipped if the contract code changed
+//after schedule, e.g. by upgrade
+func (this *callbackEngine) Invoke() (interface{}, error) {
+	native, callback := this.native, this.callback
+	dep, err := native.CacheDB.GetContract(callback.Contract)
+	if err != nil {
+		return nil, fmt.Errorf("get contract error: %v", err)
+	}
+	if dep == nil {
+		return nil, fmt.Errorf("contract %s not exists", callback.Contract.ToHexString())
+	}
+	if common.AddressFromVmCode(dep.Code) != callback.CodeHash {
+		return nil, fmt.Errorf("code of contract %s changed", callback.Contract.ToHexString())
+	}
+	return neovm.CallContract(native.ContextRef, callback.Registrant, callback.Contract, dep.Code, callback.Method,
+		callback.Args)
+}
+
+//prepay transfer the ONG of gas limit from the registrant, the registrant is the calling context
+//of ong contract, so that a contract can pay for its callback
+func prepay(native *native.NativeService, callback *Callback) error {
+	native.ContextRef.PushContext(&context.Context{ContractAddress: callback.Registrant})
+	defer native.ContextRef.PopContext()
+	return transferOng(native, callback.Registrant, utils.SchedulerContractAddress, callback.prepaid())
+}
+
+func transferOng(native *native.NativeService, from, to common.Address, amount uint64) error {
+	transfers := ont.Transfers{States: []ont.State{{From: from, To: to, Value: amount}}}
+	sink := common.NewZeroCopySink(nil)
+	transfers.Serialization(sink)
+	if _, err := native.NativeCall(utils.OngContractAddress, ont.TRANSFER_NAME, sink.Bytes()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func callbackKey(id uint64) []byte {
+	bf := new(bytes.Buffer)
+	serialization.WriteUint64(bf, id)
+	return utils.ConcatKey(utils.SchedulerContractAddress, []byte(CALLBACK), bf.Bytes())
+}
+
+func heightKey(height uint32) []byte {
+	return utils.ConcatKey(utils.SchedulerContractAddress, GenHeightKey(height))
+}
+
+func getCallback(native *native.NativeService, id uint64) (*Callback, error) {
+	item, err := utils.GetStorageItem(native, callbackKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("get callback error: %v", err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+	callback := new(Callback)
+	if err := callback.Deserialize(bytes.NewBuffer(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize callback error: %v", err)
+	}
+	return callback, nil
+}
+
+func putCallback(native *native.NativeService, callback *Callback) error {
+	bf := new(bytes.Buffer)
+	if err := callback.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize callback error: %v", err)
+	}
+	native.CacheDB.Put(callbackKey(callback.Id), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func getHeightIds(native *native.NativeService, height uint32) ([]uint64, error) {
+	item, err := utils.GetStorageItem(native, heightKey(height))
+	if err != nil {
+		return nil, fmt.Errorf("get callbacks of height %d error: %v", height, err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+	ids, err := deserializeIds(bytes.NewBuffer(item.Value))
+	if err != nil {
+		return nil, fmt.Errorf("deserialize callbacks of height %d error: %v", height, err)
+	}
+	return ids, nil
+}
+
+//putHeightIds save the callbacks of height, the key is deleted when there is none, so that
+//consensus can tell whether a block has due callbacks by the existence of key
+func putHeightIds(native *native.NativeService, height uint32, ids []uint64) error {
+	if len(ids) == 0 {
+		native.CacheDB.Delete(heightKey(height))
+		return nil
+	}
+	bf := new(bytes.Buffer)
+	if err := serializeIds(bf, ids); err != nil {
+		return fmt.Errorf("serialize callbacks of height %d error: %v", height, err)
+	}
+	native.CacheDB.Put(heightKey(height), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func pushEvent(native *native.NativeService, s interface{}) {
+	native.Notifications = append(native.Notifications, &event.NotifyEventInfo{
+		ContractAddress: native.ContextRef.CurrentContext().ContractAddress,
+		States:          s,
+	})
+}
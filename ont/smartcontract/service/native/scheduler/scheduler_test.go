@@ -0,0 +1,267 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scheduler
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	vmtypes "github.com/ontio/ontology/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testBalance  uint64 = 1000000
+	testGasLimit uint64 = 100000
+)
+
+func newTestCache(t *testing.T, registrant common.Address) *storage.CacheDB {
+	InitScheduler()
+	ong.InitOng()
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+	cache.Put(ont.GenBalanceKey(utils.OngContractAddress, registrant), utils.GenUInt64StorageItem(testBalance).ToArray())
+	return cache
+}
+
+func newNativeService(t *testing.T, cache *storage.CacheDB, height uint32, tx *types.Transaction) *native.NativeService {
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: height, Tx: tx},
+		CacheDB: cache,
+		Gas:     math.MaxUint64,
+	}
+	service, err := sc.NewNativeService()
+	assert.Nil(t, err)
+	return service
+}
+
+func balanceOf(t *testing.T, cache *storage.CacheDB, addr common.Address) uint64 {
+	service := newNativeService(t, cache, 0, &types.Transaction{})
+	balance, err := utils.GetStorageUInt64(service, ont.GenBalanceKey(utils.OngContractAddress, addr))
+	assert.Nil(t, err)
+	return balance
+}
+
+//deployCode deploy a contract putting "k":"v" to its storage, followed by tail
+func deployCode(t *testing.T, cache *storage.CacheDB, tail ...byte) common.Address {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushByteArray([]byte("v"))
+	builder.EmitPushByteArray([]byte("k"))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GETCONTEXT_NAME))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_PUT_NAME))
+	code := append(builder.ToArray(), tail...)
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: code}))
+	return common.AddressFromVmCode(code)
+}
+
+func storedValue(t *testing.T, cache *storage.CacheDB, contract common.Address) []byte {
+	service := newNativeService(t, cache, 0, &types.Transaction{})
+	item, err := utils.GetStorageItem(service, utils.ConcatKey(contract, []byte("k")))
+	assert.Nil(t, err)
+	if item == nil {
+		return nil
+	}
+	return item.Value
+}
+
+//call invoke method of scheduler at height, which is relative to the native upgrade height
+func call(t *testing.T, cache *storage.CacheDB, height uint32, signer common.Address, method string,
+	input []byte) ([]byte, error) {
+	service := newNativeService(t, cache, config.GetNativeUpgradeHeight()+height,
+		&types.Transaction{SignedAddr: []common.Address{signer}})
+	ret, err := service.NativeCall(utils.SchedulerContractAddress, method, input)
+	if err != nil {
+		return nil, err
+	}
+	return ret.([]byte), nil
+}
+
+func schedule(t *testing.T, cache *storage.CacheDB, registrant, contract common.Address, height uint32) (uint64, error) {
+	params := &ScheduleParam{
+		Registrant: registrant,
+		Contract:   contract,
+		Method:     "run",
+		Height:     config.GetNativeUpgradeHeight() + height,
+		GasLimit:   testGasLimit,
+		GasPrice:   1,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, params.Serialize(bf))
+	ret, err := call(t, cache, 10, registrant, SCHEDULE, bf.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return vmtypes.BigIntFromBytes(ret).Uint64(), nil
+}
+
+//executeScheduled run the system transaction of block height relative to the native upgrade height,
+//execStep is the steps executed before
+func executeScheduled(t *testing.T, cache *storage.CacheDB, height uint32, tx *types.Transaction, execStep int) error {
+	height += config.GetNativeUpgradeHeight()
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushByteArray([]byte{})
+	builder.EmitPushByteArray([]byte(EXECUTE_SCHEDULED))
+	builder.EmitPushByteArray(utils.SchedulerContractAddress[:])
+	builder.EmitPushInteger(big.NewInt(0))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.NATIVE_INVOKE_NAME))
+	sc := &smartcontract.SmartContract{
+		Config:   &smartcontract.Config{Height: height, Tx: tx},
+		CacheDB:  cache,
+		Gas:      math.MaxUint64,
+		ExecStep: execStep,
+	}
+	engine, err := sc.NewExecuteEngine(builder.ToArray())
+	assert.Nil(t, err)
+	_, err = engine.Invoke()
+	return err
+}
+
+func TestScheduleAndCancel(t *testing.T) {
+	registrant := common.AddressFromVmCode([]byte("registrant"))
+	cache := newTestCache(t, registrant)
+	contract := deployCode(t, cache)
+
+	_, err := schedule(t, cache, registrant, contract, 10)
+	assert.NotNil(t, err)
+	_, err = schedule(t, cache, registrant, common.AddressFromVmCode([]byte("none")), 20)
+	assert.NotNil(t, err)
+
+	id, err := schedule(t, cache, registrant, contract, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, testBalance-testGasLimit, balanceOf(t, cache, registrant))
+	assert.Equal(t, testGasLimit, balanceOf(t, cache, utils.SchedulerContractAddress))
+
+	bf := new(bytes.Buffer)
+	assert.Nil(t, (&CancelParam{Id: id}).Serialize(bf))
+	_, err = call(t, cache, 10, common.AddressFromVmCode([]byte("other")), CANCEL, bf.Bytes())
+	assert.NotNil(t, err)
+	_, err = call(t, cache, 10, registrant, CANCEL, bf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, testBalance, balanceOf(t, cache, registrant))
+	assert.Equal(t, uint64(0), balanceOf(t, cache, utils.SchedulerContractAddress))
+}
+
+func TestExecuteScheduled(t *testing.T) {
+	registrant := common.AddressFromVmCode([]byte("registrant"))
+	cache := newTestCache(t, registrant)
+	succeed := deployCode(t, cache)
+	fail := deployCode(t, cache, byte(vm.THROW))
+	_, err := schedule(t, cache, registrant, fail, 20)
+	assert.Nil(t, err)
+	_, err = schedule(t, cache, registrant, succeed, 20)
+	assert.Nil(t, err)
+
+	//only the unsigned transaction with block height as nonce
+	assert.NotNil(t, executeScheduled(t, cache, 20, &types.Transaction{Nonce: config.GetNativeUpgradeHeight() + 19}, 0))
+	assert.NotNil(t, executeScheduled(t, cache, 20, &types.Transaction{Nonce: config.GetNativeUpgradeHeight() + 20, Sigs: []types.Sig{{M: 1}}}, 0))
+
+	assert.Nil(t, executeScheduled(t, cache, 20, &types.Transaction{Nonce: config.GetNativeUpgradeHeight() + 20}, 0))
+	//the failed callback is reverted without failing the other one
+	assert.Nil(t, storedValue(t, cache, fail))
+	assert.Equal(t, []byte("v"), storedValue(t, cache, succeed))
+
+	//the consumed gas is charged in both cases and the rest is refunded
+	fee := balanceOf(t, cache, utils.GovernanceContractAddress)
+	assert.True(t, fee >= 2*neovm.MIN_TRANSACTION_GAS && fee <= 2*testGasLimit)
+	assert.Equal(t, testBalance-fee, balanceOf(t, cache, registrant))
+	assert.Equal(t, uint64(0), balanceOf(t, cache, utils.SchedulerContractAddress))
+
+	height := config.GetNativeUpgradeHeight() + 20
+	ids, err := getHeightIds(newNativeService(t, cache, height, &types.Transaction{}), height)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(ids))
+}
+
+func TestExecuteScheduled_StepLimit(t *testing.T) {
+	registrant := common.AddressFromVmCode([]byte("registrant"))
+	cache := newTestCache(t, registrant)
+	//loop 100 times before putting storage, more than the steps left of the system transaction
+	loop := []byte{byte(vm.PUSHBYTES1), 100, byte(vm.DEC), byte(vm.DUP), byte(vm.JMPIF), 0xfe, 0xff, byte(vm.DROP)}
+	dep, err := cache.GetContract(deployCode(t, cache))
+	assert.Nil(t, err)
+	code := append(loop, dep.Code...)
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: code}))
+	contract := common.AddressFromVmCode(code)
+	_, err = schedule(t, cache, registrant, contract, 20)
+	assert.Nil(t, err)
+
+	//the callback has its own step limit
+	assert.Nil(t, executeScheduled(t, cache, 20, &types.Transaction{Nonce: config.GetNativeUpgradeHeight() + 20}, neovm.VM_STEP_LIMIT-100))
+	assert.Equal(t, []byte("v"), storedValue(t, cache, contract))
+}
+
+func TestExecuteScheduled_CodeChanged(t *testing.T) {
+	registrant := common.AddressFromVmCode([]byte("registrant"))
+	cache := newTestCache(t, registrant)
+	contract := deployCode(t, cache)
+	_, err := schedule(t, cache, registrant, contract, 20)
+	assert.Nil(t, err)
+	dep, err := cache.GetContract(contract)
+	assert.Nil(t, err)
+	assert.Nil(t, cache.ReplaceContract(contract, &payload.DeployCode{Code: append(dep.Code, byte(vm.NOP))}))
+
+	//the callback is skipped and charged the minimum gas
+	assert.Nil(t, executeScheduled(t, cache, 20, &types.Transaction{Nonce: config.GetNativeUpgradeHeight() + 20}, 0))
+	assert.Nil(t, storedValue(t, cache, contract))
+	assert.Equal(t, neovm.MIN_TRANSACTION_GAS, balanceOf(t, cache, utils.GovernanceContractAddress))
+	assert.Equal(t, testBalance-neovm.MIN_TRANSACTION_GAS, balanceOf(t, cache, registrant))
+}
+
+func TestScheduleBeforeUpgrade(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	if upgrade == 0 {
+		return
+	}
+	registrant := common.AddressFromVmCode([]byte("registrant"))
+	cache := newTestCache(t, registrant)
+	params := &ScheduleParam{
+		Registrant: registrant,
+		Contract:   deployCode(t, cache),
+		Method:     "run",
+		Height:     upgrade + 10,
+		GasLimit:   testGasLimit,
+		GasPrice:   1,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, params.Serialize(bf))
+	service := newNativeService(t, cache, upgrade-1, &types.Transaction{SignedAddr: []common.Address{registrant}})
+	_, err := service.NativeCall(utils.SchedulerContractAddress, SCHEDULE, bf.Bytes())
+	assert.NotNil(t, err)
+	assert.Equal(t, testBalance, balanceOf(t, cache, registrant))
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scheduler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//Callback is a NeoVM contract call executed at the beginning of block Height. Args is a script
+//pushing the parameters of the method, which is pushed on the top of them before calling.
+//The gas is prepaid by Registrant at GasPrice, the unused part is refunded after execution.
+//CodeHash is the hash of contract code at schedule, the callback is skipped if the code changed
+type Callback struct {
+	Id         uint64
+	Registrant common.Address
+	Contract   common.Address
+	Method     string
+	Args       []byte
+	Height     uint32
+	GasLimit   uint64
+	GasPrice   uint64
+	CodeHash   common.Address
+}
+
+func (this *Callback) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, this.Id); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	if err := utils.WriteAddress(w, this.Registrant); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize registrant error: %v", err)
+	}
+	if err := serializeCall(w, this.Contract, this.Method, this.Args, this.Height, this.GasLimit,
+		this.GasPrice); err != nil {
+		return err
+	}
+	if err := utils.WriteAddress(w, this.CodeHash); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize code hash error: %v", err)
+	}
+	return nil
+}
+
+func (this *Callback) Deserialize(r io.Reader) error {
+	var err error
+	if this.Id, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	if this.Registrant, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize registrant error: %v", err)
+	}
+	this.Contract, this.Method, this.Args, this.Height, this.GasLimit, this.GasPrice, err = deserializeCall(r)
+	if err != nil {
+		return err
+	}
+	if this.CodeHash, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize code hash error: %v", err)
+	}
+	return nil
+}
+
+//prepaid return the ONG paid by registrant for the gas limit
+func (this *Callback) prepaid() uint64 {
+	return this.GasLimit * this.GasPrice
+}
+
+//ScheduleParam is the param of schedule, Height must be after the current block
+type ScheduleParam struct {
+	Registrant common.Address
+	Contract   common.Address
+	Method     string
+	Args       []byte
+	Height     uint32
+	GasLimit   uint64
+	GasPrice   uint64
+}
+
+func (this *ScheduleParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Registrant); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize registrant error: %v", err)
+	}
+	return serializeCall(w, this.Contract, this.Method, this.Args, this.Height, this.GasLimit, this.GasPrice)
+}
+
+func (this *ScheduleParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Registrant, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize registrant error: %v", err)
+	}
+	this.Contract, this.Method, this.Args, this.Height, this.GasLimit, this.GasPrice, err = deserializeCall(r)
+	return err
+}
+
+func serializeCall(w io.Writer, contract common.Address, method string, args []byte, height uint32,
+	gasLimit, gasPrice uint64) error {
+	if err := utils.WriteAddress(w, contract); err != nil {
+		return fmt.Errorf("utils.WriteAddress, serialize contract error: %v", err)
+	}
+	if err := serialization.WriteString(w, method); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize method error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, args); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize args error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, height); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize height error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, gasLimit); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize gas limit error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, gasPrice); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize gas price error: %v", err)
+	}
+	return nil
+}
+
+func deserializeCall(r io.Reader) (contract common.Address, method string, args []byte, height uint32,
+	gasLimit, gasPrice uint64, err error) {
+	if contract, err = utils.ReadAddress(r); err != nil {
+		err = fmt.Errorf("utils.ReadAddress, deserialize contract error: %v", err)
+		return
+	}
+	if method, err = serialization.ReadString(r); err != nil {
+		err = fmt.Errorf("serialization.ReadString, deserialize method error: %v", err)
+		return
+	}
+	if args, err = serialization.ReadVarBytes(r); err != nil {
+		err = fmt.Errorf("serialization.ReadVarBytes, deserialize args error: %v", err)
+		return
+	}
+	if height, err = serialization.ReadUint32(r); err != nil {
+		err = fmt.Errorf("serialization.ReadUint32, deserialize height error: %v", err)
+		return
+	}
+	if gasLimit, err = utils.ReadVarUint(r); err != nil {
+		err = fmt.Errorf("utils.ReadVarUint, deserialize gas limit error: %v", err)
+		return
+	}
+	if gasPrice, err = utils.ReadVarUint(r); err != nil {
+		err = fmt.Errorf("utils.ReadVarUint, deserialize gas price error: %v", err)
+		return
+	}
+	return
+}
+
+//CancelParam is the param of cancel, it is called by the registrant of the callback
+type CancelParam struct {
+	Id uint64
+}
+
+func (this *CancelParam) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, this.Id); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	return nil
+}
+
+func (this *CancelParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Id, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	return nil
+}
+
+func serializeIds(w io.Writer, ids []uint64) error {
+	if err := utils.WriteVarUint(w, uint64(len(ids))); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := utils.WriteVarUint(w, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deserializeIds(r io.Reader) ([]uint64, error) {
+	n, err := utils.ReadVarUint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > MAX_CALLBACKS_PER_HEIGHT {
+		return nil, fmt.Errorf("too many callbacks: %d", n)
+	}
+	ids := make([]uint64, 0, n)
+	for i := uint64(0); i < n; i++ {
+		id, err := utils.ReadVarUint(r)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
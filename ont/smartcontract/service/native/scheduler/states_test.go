@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scheduler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallback_Serialize(t *testing.T) {
+	callback := &Callback{
+		Id:         3,
+		Registrant: common.AddressFromVmCode([]byte{1}),
+		Contract:   common.AddressFromVmCode([]byte{2}),
+		Method:     "closeAuction",
+		Args:       []byte{0x51, 0xc1},
+		Height:     1000,
+		GasLimit:   20000,
+		GasPrice:   500,
+		CodeHash:   common.AddressFromVmCode([]byte{3}),
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, callback.Serialize(bf))
+	callback2 := new(Callback)
+	assert.Nil(t, callback2.Deserialize(bf))
+	assert.Equal(t, callback, callback2)
+	assert.Equal(t, uint64(20000*500), callback2.prepaid())
+}
+
+func TestScheduleParam_Serialize(t *testing.T) {
+	params := &ScheduleParam{
+		Registrant: common.AddressFromVmCode([]byte{1}),
+		Contract:   common.AddressFromVmCode([]byte{2}),
+		Method:     "release",
+		Height:     7,
+		GasLimit:   30000,
+		GasPrice:   1,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, params.Serialize(bf))
+	params2 := new(ScheduleParam)
+	assert.Nil(t, params2.Deserialize(bf))
+	assert.Equal(t, params.Contract, params2.Contract)
+	assert.Equal(t, params.Method, params2.Method)
+	assert.Equal(t, 0, len(params2.Args))
+	assert.Equal(t, params.Height, params2.Height)
+	assert.Equal(t, params.GasLimit, params2.GasLimit)
+}
+
+func TestIds_Serialize(t *testing.T) {
+	ids := []uint64{1, 5, 300}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, serializeIds(bf, ids))
+	ids2, err := deserializeIds(bf)
+	assert.Nil(t, err)
+	assert.Equal(t, ids, ids2)
+
+	bf.Reset()
+	assert.Nil(t, serializeIds(bf, make([]uint64, MAX_CALLBACKS_PER_HEIGHT+1)))
+	_, err = deserializeIds(bf)
+	assert.NotNil(t, err)
+}
+
+func TestGenHeightKey(t *testing.T) {
+	assert.NotEqual(t, GenHeightKey(1), GenHeightKey(256))
+	assert.Equal(t, []byte(HEIGHT), GenHeightKey(0)[:len(HEIGHT)])
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package testsuite provides the native service fixture shared by the tests of native contracts
+package testsuite
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/context"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+//NewCacheDB return an empty contract cache backed by memory
+func NewCacheDB(t *testing.T) *storage.CacheDB {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	return storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+}
+
+//NewNativeService return a native service running contract at height, in a transaction signed by signers.
+//CheckWitness and gas are handled by the smart contract as on chain, with unlimited gas
+func NewNativeService(t *testing.T, contract common.Address, height uint32,
+	signers ...common.Address) *native.NativeService {
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: height, Tx: &types.Transaction{SignedAddr: signers}},
+		CacheDB: NewCacheDB(t),
+		Gas:     math.MaxUint64,
+	}
+	sc.PushContext(&context.Context{ContractAddress: contract})
+	service, err := sc.NewNativeService()
+	assert.Nil(t, err)
+	return service
+}
@@ -29,6 +29,7 @@ var (
 	BLOCKCHAIN_GETCONTRACT_GAS    uint64 = 100
 	CONTRACT_CREATE_GAS           uint64 = 20000000
 	CONTRACT_MIGRATE_GAS          uint64 = 20000000
+	CONTRACT_UPGRADE_GAS          uint64 = 20000000
 	UINT_DEPLOY_CODE_LEN_GAS      uint64 = 200000
 	UINT_INVOKE_CODE_LEN_GAS      uint64 = 20000
 	NATIVE_INVOKE_GAS             uint64 = 1000
@@ -39,6 +40,11 @@ var (
 	RUNTIME_ADDRESSTOBASE58_GAS   uint64 = 40
 	RUNTIME_BASE58TOADDRESS_GAS   uint64 = 30
 	APPCALL_GAS                   uint64 = 10
+	CONTRACT_TRYCALL_GAS          uint64 = 10
+	CRYPTO_VERIFY_GAS             uint64 = 200
+	CRYPTO_VERIFYMULTISIG_GAS     uint64 = 200 // per public key
+	CRYPTO_VERIFYONTID_GAS        uint64 = 400
+	RUNTIME_GETRANDOMNESS_GAS     uint64 = 1000
 	TAILCALL_GAS                  uint64 = 10
 	SHA1_GAS                      uint64 = 10
 	SHA256_GAS                    uint64 = 10
@@ -80,9 +86,11 @@ var (
 
 	CONTRACT_CREATE_NAME            = "Ontology.Contract.Create"
 	CONTRACT_MIGRATE_NAME           = "Ontology.Contract.Migrate"
+	CONTRACT_UPGRADE_NAME           = "Ontology.Contract.Upgrade"
 	CONTRACT_GETSTORAGECONTEXT_NAME = "System.Contract.GetStorageContext"
 	CONTRACT_DESTROY_NAME           = "System.Contract.Destroy"
 	CONTRACT_GETSCRIPT_NAME         = "Ontology.Contract.GetScript"
+	CONTRACT_TRYCALL_NAME           = "Ontology.Contract.TryCall"
 
 	STORAGE_GET_NAME                = "System.Storage.Get"
 	STORAGE_PUT_NAME                = "System.Storage.Put"
@@ -102,9 +110,14 @@ var (
 	RUNTIME_BASE58TOADDRESS_NAME     = "Ontology.Runtime.Base58ToAddress"
 	RUNTIME_ADDRESSTOBASE58_NAME     = "Ontology.Runtime.AddressToBase58"
 	RUNTIME_GETCURRENTBLOCKHASH_NAME = "Ontology.Runtime.GetCurrentBlockHash"
+	RUNTIME_GETRANDOMNESS_NAME       = "Ontology.Runtime.GetRandomness"
 
 	NATIVE_INVOKE_NAME = "Ontology.Native.Invoke"
 
+	CRYPTO_VERIFY_NAME         = "Ontology.Crypto.Verify"
+	CRYPTO_VERIFYMULTISIG_NAME = "Ontology.Crypto.VerifyMultiSig"
+	CRYPTO_VERIFYONTID_NAME    = "Ontology.Crypto.VerifyOntId"
+
 	GETSCRIPTCONTAINER_NAME     = "System.ExecutionEngine.GetScriptContainer"
 	GETEXECUTINGSCRIPTHASH_NAME = "System.ExecutionEngine.GetExecutingScriptHash"
 	GETCALLINGSCRIPTHASH_NAME   = "System.ExecutionEngine.GetCallingScriptHash"
@@ -128,6 +141,7 @@ var (
 		BLOCKCHAIN_GETCONTRACT_NAME,
 		CONTRACT_CREATE_NAME,
 		CONTRACT_MIGRATE_NAME,
+		CONTRACT_UPGRADE_NAME,
 		STORAGE_GET_NAME,
 		STORAGE_PUT_NAME,
 		STORAGE_DELETE_NAME,
@@ -141,6 +155,10 @@ var (
 		HASH256_NAME,
 		UINT_DEPLOY_CODE_LEN_NAME,
 		UINT_INVOKE_CODE_LEN_NAME,
+		CRYPTO_VERIFY_NAME,
+		CRYPTO_VERIFYMULTISIG_NAME,
+		CRYPTO_VERIFYONTID_NAME,
+		RUNTIME_GETRANDOMNESS_NAME,
 	}
 
 	INIT_GAS_TABLE = map[string]uint64{
@@ -174,6 +192,7 @@ func initGAS_TABLE() *sync.Map {
 	m.Store(BLOCKCHAIN_GETCONTRACT_NAME, BLOCKCHAIN_GETCONTRACT_GAS)
 	m.Store(CONTRACT_CREATE_NAME, CONTRACT_CREATE_GAS)
 	m.Store(CONTRACT_MIGRATE_NAME, CONTRACT_MIGRATE_GAS)
+	m.Store(CONTRACT_UPGRADE_NAME, CONTRACT_UPGRADE_GAS)
 	m.Store(STORAGE_GET_NAME, STORAGE_GET_GAS)
 	m.Store(STORAGE_PUT_NAME, STORAGE_PUT_GAS)
 	m.Store(STORAGE_DELETE_NAME, STORAGE_DELETE_GAS)
@@ -190,6 +209,11 @@ func initGAS_TABLE() *sync.Map {
 
 	m.Store(RUNTIME_BASE58TOADDRESS_NAME, RUNTIME_BASE58TOADDRESS_GAS)
 	m.Store(RUNTIME_ADDRESSTOBASE58_NAME, RUNTIME_ADDRESSTOBASE58_GAS)
+	m.Store(CONTRACT_TRYCALL_NAME, CONTRACT_TRYCALL_GAS)
+	m.Store(CRYPTO_VERIFY_NAME, CRYPTO_VERIFY_GAS)
+	m.Store(CRYPTO_VERIFYMULTISIG_NAME, CRYPTO_VERIFYMULTISIG_GAS)
+	m.Store(CRYPTO_VERIFYONTID_NAME, CRYPTO_VERIFYONTID_GAS)
+	m.Store(RUNTIME_GETRANDOMNESS_NAME, RUNTIME_GETRANDOMNESS_GAS)
 
 	return &m
 }
@@ -19,12 +19,17 @@
 package neovm
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/smartcontract/states"
 	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/ontio/ontology/vm/neovm/types"
 )
 
 // ContractCreate create a new smart contract on blockchain, and put it to vm stack
@@ -63,6 +68,17 @@ func ContractMigrate(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	service.CacheDB.PutContract(contract)
 	service.CacheDB.DeleteContract(oldAddr)
 
+	//the version history of upgraded contract is carried over to the new address
+	history, err := service.CacheDB.GetContractHistory(oldAddr)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] get contract history fail!")
+	}
+	if len(history.Versions) > 0 {
+		history.Versions = append(history.Versions, newContractVersion(contract, service.Height, currentTxHash(service)))
+		service.CacheDB.PutContractHistory(newAddr, history)
+		service.CacheDB.DeleteContractHistory(oldAddr)
+	}
+
 	iter := service.CacheDB.NewIterator(oldAddr[:])
 	for has := iter.First(); has; has = iter.Next() {
 		key := iter.Key()
@@ -81,6 +97,65 @@ func ContractMigrate(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	return nil
 }
 
+// ContractUpgrade replace the code of current contract, the contract address and storage are kept,
+// and the new version is appended to contract history
+func ContractUpgrade(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	contract, err := isContractParamValid(engine)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractUpgrade] contract parameters invalid!")
+	}
+	if service.Height < config.GetNativeUpgradeHeight() {
+		return errors.NewErr("[ContractUpgrade] block num is not reached for this func")
+	}
+	context := service.ContextRef.CurrentContext()
+	if context == nil {
+		return errors.NewErr("[ContractUpgrade] current contract context invalid!")
+	}
+	addr := context.ContractAddress
+	old, err := service.CacheDB.GetContract(addr)
+	if err != nil || old == nil {
+		return errors.NewErr("[ContractUpgrade] get current contract fail!")
+	}
+	if bytes.Equal(old.Code, contract.Code) {
+		return errors.NewErr("[ContractUpgrade] contract code not changed!")
+	}
+	history, err := service.CacheDB.GetContractHistory(addr)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractUpgrade] get contract history fail!")
+	}
+	if len(history.Versions) == 0 {
+		history.Versions = append(history.Versions, newContractVersion(old, 0, common.UINT256_EMPTY))
+	}
+	history.Versions = append(history.Versions, newContractVersion(contract, service.Height, currentTxHash(service)))
+
+	if err := service.CacheDB.ReplaceContract(addr, contract); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractUpgrade] put contract fail!")
+	}
+	service.CacheDB.PutContractHistory(addr, history)
+	vm.PushData(engine, contract)
+	return nil
+}
+
+func currentTxHash(service *NeoVmService) common.Uint256 {
+	if service.Tx == nil {
+		return common.UINT256_EMPTY
+	}
+	return service.Tx.Hash()
+}
+
+func newContractVersion(contract *payload.DeployCode, height uint32, txHash common.Uint256) *states.ContractVersion {
+	return &states.ContractVersion{
+		Height:      height,
+		TxHash:      txHash,
+		CodeHash:    contract.Address(),
+		Name:        contract.Name,
+		Version:     contract.Version,
+		Author:      contract.Author,
+		Email:       contract.Email,
+		Description: contract.Description,
+	}
+}
+
 // ContractDestory destroy a contract
 func ContractDestory(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	context := service.ContextRef.CurrentContext()
@@ -95,6 +170,14 @@ func ContractDestory(service *NeoVmService, engine *vm.ExecutionEngine) error {
 
 	service.CacheDB.DeleteContract(addr)
 
+	history, err := service.CacheDB.GetContractHistory(addr)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractDestory] get contract history fail!")
+	}
+	if len(history.Versions) > 0 {
+		service.CacheDB.DeleteContractHistory(addr)
+	}
+
 	iter := service.CacheDB.NewIterator(addr[:])
 	for has := iter.First(); has; has = iter.Next() {
 		key := iter.Key()
@@ -125,6 +208,14 @@ func ContractGetStorageContext(service *NeoVmService, engine *vm.ExecutionEngine
 		return errors.NewErr("[GetStorageContext] Pop data not contract!")
 	}
 	address := contractState.Address()
+	if current := service.ContextRef.CurrentContext().ContractAddress; address != current {
+		//the code of upgraded contract is deployed at the address of its first version,
+		//accept it only if it is the latest version recorded in the history of current contract
+		history, err := service.CacheDB.GetContractHistory(current)
+		if err == nil && len(history.Versions) > 0 && history.Versions[len(history.Versions)-1].CodeHash == address {
+			address = current
+		}
+	}
 	item, err := service.CacheDB.GetContract(address)
 	if err != nil || item == nil {
 		return errors.NewDetailErr(err, errors.ErrNoCode, "[GetStorageContext] Get StorageContext nil")
@@ -146,6 +237,56 @@ func ContractGetCode(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	return nil
 }
 
+// ContractTryCall invoke a contract with gas limit, and put [success, result] to vm stack.
+// The failure of called contract is not propagated, its state changes and notifications are reverted
+func ContractTryCall(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	if service.Height < config.GetNativeUpgradeHeight() {
+		return errors.NewErr("[ContractTryCall] block num is not reached for this func")
+	}
+	address, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("[ContractTryCall] contract address invalid:%x", address)
+	}
+	gasLimit, err := vm.PopBigInt(engine)
+	if err != nil {
+		return err
+	}
+	if gasLimit.Sign() <= 0 || !gasLimit.IsUint64() {
+		return fmt.Errorf("[ContractTryCall] gas limit invalid:%s", gasLimit.String())
+	}
+	args, err := vm.PopArray(engine)
+	if err != nil {
+		return err
+	}
+	callee, err := service.newContractEngine(addr)
+	if err == CONTRACT_NOT_EXIST {
+		log.Debugf("[ContractTryCall] contract %s not exists", addr.ToHexString())
+		vm.PushData(engine, types.NewArray([]types.StackItems{types.NewBoolean(false), types.NewByteArray(nil)}))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	calleeEngine := callee.Engine
+	for i := len(args) - 1; i >= 0; i-- {
+		vm.Push(calleeEngine, args[i])
+	}
+
+	var item types.StackItems = types.NewByteArray(nil)
+	result, err := service.ContextRef.InvokeWithGasLimit(callee, gasLimit.Uint64())
+	if err != nil {
+		log.Debugf("[ContractTryCall] invoke contract %s error:%s", addr.ToHexString(), err)
+	} else if result != nil {
+		item = result.(types.StackItems)
+	}
+	vm.PushData(engine, types.NewArray([]types.StackItems{types.NewBoolean(err == nil), item}))
+	return nil
+}
+
 func isContractParamValid(engine *vm.ExecutionEngine) (*payload.DeployCode, error) {
 	if vm.EvaluationStackCount(engine) < 7 {
 		return nil, errors.NewErr("[Contract] Too few input parameters")
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+// CryptoVerify verify the raw signature of data with public key under an explicit signature scheme,
+// and put the result to vm stack
+func CryptoVerify(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	if service.Height < config.GetNativeUpgradeHeight() {
+		return fmt.Errorf("[CryptoVerify] block num is not reached for this func")
+	}
+	scheme, err := vm.PopInt(engine)
+	if err != nil {
+		return err
+	}
+	pubKey, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	sig, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	data, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	if scheme < 0 || scheme > 0xff {
+		return fmt.Errorf("[CryptoVerify] invalid signature scheme %d", scheme)
+	}
+	vm.PushData(engine, verifyWithScheme(s.SignatureScheme(scheme), pubKey, sig, data))
+	return nil
+}
+
+// CryptoVerifyMultiSig verify m of the public keys signed data, and put the result to vm stack
+func CryptoVerifyMultiSig(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	if service.Height < config.GetNativeUpgradeHeight() {
+		return fmt.Errorf("[CryptoVerifyMultiSig] block num is not reached for this func")
+	}
+	m, err := vm.PopInt(engine)
+	if err != nil {
+		return err
+	}
+	keyItems, err := vm.PopArray(engine)
+	if err != nil {
+		return err
+	}
+	sigItems, err := vm.PopArray(engine)
+	if err != nil {
+		return err
+	}
+	data, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	n := len(keyItems)
+	if n == 0 || n > constants.MULTI_SIG_MAX_PUBKEY_SIZE || m < 1 || m > n {
+		return fmt.Errorf("[CryptoVerifyMultiSig] invalid m of n: %d of %d", m, n)
+	}
+	if len(sigItems) > n {
+		return fmt.Errorf("[CryptoVerifyMultiSig] too many signatures: %d", len(sigItems))
+	}
+	keys := make([]keypair.PublicKey, 0, n)
+	for _, item := range keyItems {
+		buf, err := item.GetByteArray()
+		if err != nil {
+			return err
+		}
+		key, err := keypair.DeserializePublicKey(buf)
+		if err != nil {
+			vm.PushData(engine, false)
+			return nil
+		}
+		keys = append(keys, key)
+	}
+	sigs := make([][]byte, 0, len(sigItems))
+	for _, item := range sigItems {
+		sig, err := item.GetByteArray()
+		if err != nil {
+			return err
+		}
+		sigs = append(sigs, sig)
+	}
+	vm.PushData(engine, signature.VerifyMultiSignature(data, keys, m, sigs) == nil)
+	return nil
+}
+
+// CryptoVerifyOntId verify the signature of data is signed by the key of ONT ID at index,
+// and put the result to vm stack. The key is resolved by the ONT ID contract, revoked key is invalid
+func CryptoVerifyOntId(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	if service.Height < config.GetNativeUpgradeHeight() {
+		return fmt.Errorf("[CryptoVerifyOntId] block num is not reached for this func")
+	}
+	id, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	index, err := vm.PopBigInt(engine)
+	if err != nil {
+		return err
+	}
+	sig, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	data, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	if index.Sign() <= 0 || !index.IsUint64() || index.Uint64() > uint64(^uint32(0)) {
+		return fmt.Errorf("[CryptoVerifyOntId] invalid key index %s", index.String())
+	}
+	pubKey, err := getOntIdKey(service, id, uint32(index.Uint64()))
+	if err != nil {
+		return err
+	}
+	if pubKey == nil {
+		vm.PushData(engine, false)
+		return nil
+	}
+	key, err := keypair.DeserializePublicKey(pubKey)
+	if err != nil {
+		vm.PushData(engine, false)
+		return nil
+	}
+	vm.PushData(engine, signature.Verify(key, data, sig) == nil)
+	return nil
+}
+
+// getOntIdKey return the key of ONT ID at index by calling getPublicKeys of the ONT ID contract,
+// nil if the ID is invalid or the key doesn't exist or is revoked
+func getOntIdKey(service *NeoVmService, id []byte, index uint32) ([]byte, error) {
+	// failed native call leaves its context on the stack, so reject malformed ID before calling
+	if ontid.CheckID(id) != nil {
+		return nil, nil
+	}
+	input := new(bytes.Buffer)
+	if err := serialization.WriteVarBytes(input, id); err != nil {
+		return nil, err
+	}
+	native := &native.NativeService{
+		CacheDB:    service.CacheDB,
+		Tx:         service.Tx,
+		Height:     service.Height,
+		Time:       service.Time,
+		ContextRef: service.ContextRef,
+		ServiceMap: make(map[string]native.Handler),
+	}
+	result, err := native.NativeCall(utils.OntIDContractAddress, "getPublicKeys", input.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[CryptoVerifyOntId] get public keys error:%s", err)
+	}
+	keys, ok := result.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("[CryptoVerifyOntId] getPublicKeys return non-bytes value")
+	}
+	// keys in use are serialized as index and public key
+	rd := bytes.NewReader(keys)
+	for rd.Len() > 0 {
+		i, err := serialization.ReadUint32(rd)
+		if err != nil {
+			return nil, fmt.Errorf("[CryptoVerifyOntId] read key index error:%s", err)
+		}
+		key, err := serialization.ReadVarBytes(rd)
+		if err != nil {
+			return nil, fmt.Errorf("[CryptoVerifyOntId] read public key error:%s", err)
+		}
+		if i == index {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// VerifyMultiSigGasCost charge gas for each public key of multi-signature
+func VerifyMultiSigGasCost(engine *vm.ExecutionEngine) (uint64, error) {
+	keys, err := vm.PeekNStackItem(1, engine).GetArray()
+	if err != nil {
+		return 0, err
+	}
+	if cost, ok := GAS_TABLE.Load(CRYPTO_VERIFYMULTISIG_NAME); ok {
+		return uint64(len(keys)) * cost.(uint64), nil
+	}
+	return 0, fmt.Errorf("[VerifyMultiSigGasCost] get CRYPTO_VERIFYMULTISIG_NAME gas failed")
+}
+
+func verifyWithScheme(scheme s.SignatureScheme, pubKey, sig, data []byte) bool {
+	key, err := keypair.DeserializePublicKey(pubKey)
+	if err != nil {
+		return false
+	}
+	sigObj, err := s.Deserialize(append([]byte{byte(scheme)}, sig...))
+	if err != nil || sigObj.Scheme != scheme {
+		return false
+	}
+	return s.Verify(key, data, sigObj)
+}
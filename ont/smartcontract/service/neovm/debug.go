@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ontio/ontology/common/serialization"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+//Debugger observe the execution of a NeoVmService, Step is called before every instruction and
+//may block to pause the vm, a non nil error aborts the execution
+type Debugger interface {
+	Enter(service *NeoVmService)
+	Step(service *NeoVmService, offset int) error
+	Exit(service *NeoVmService, err error)
+}
+
+//OpCodeAt return the opcode at offset of code
+func OpCodeAt(code []byte, offset int) vm.OpCode {
+	if offset < 0 || offset >= len(code) {
+		return vm.RET
+	}
+	return vm.OpCode(code[offset])
+}
+
+//OpCodeName return the name of opcode, the PUSHBYTES opcodes are named with their length
+func OpCodeName(opCode vm.OpCode) string {
+	if opCode >= vm.PUSHBYTES1 && opCode <= vm.PUSHBYTES75 {
+		return fmt.Sprintf("PUSHBYTES%d", opCode)
+	}
+	return vm.OpExecList[opCode].Name
+}
+
+//SyscallNameAt return the service name of the SYSCALL instruction at offset of code,
+//empty string returned if the instruction is not a SYSCALL
+func SyscallNameAt(code []byte, offset int) string {
+	if OpCodeAt(code, offset) != vm.SYSCALL {
+		return ""
+	}
+	name, err := serialization.ReadString(bytes.NewReader(code[offset+1:]))
+	if err != nil {
+		return ""
+	}
+	return name
+}
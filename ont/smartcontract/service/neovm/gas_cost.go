@@ -43,6 +43,8 @@ func GasPrice(engine *vm.ExecutionEngine, name string) (uint64, error) {
 	switch name {
 	case STORAGE_PUT_NAME:
 		return StoreGasCost(engine)
+	case CRYPTO_VERIFYMULTISIG_NAME:
+		return VerifyMultiSigGasCost(engine)
 	default:
 		if value, ok := GAS_TABLE.Load(name); ok {
 			return value.(uint64), nil
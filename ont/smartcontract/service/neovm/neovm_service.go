@@ -63,9 +63,11 @@ var (
 		TRANSACTION_GETATTRIBUTES_NAME:       {Execute: TransactionGetAttributes, Validator: validatorTransaction},
 		CONTRACT_CREATE_NAME:                 {Execute: ContractCreate},
 		CONTRACT_MIGRATE_NAME:                {Execute: ContractMigrate},
+		CONTRACT_UPGRADE_NAME:                {Execute: ContractUpgrade},
 		CONTRACT_GETSTORAGECONTEXT_NAME:      {Execute: ContractGetStorageContext},
 		CONTRACT_DESTROY_NAME:                {Execute: ContractDestory},
 		CONTRACT_GETSCRIPT_NAME:              {Execute: ContractGetCode, Validator: validatorGetCode},
+		CONTRACT_TRYCALL_NAME:                {Execute: ContractTryCall, Validator: validatorTryCall},
 		RUNTIME_GETTIME_NAME:                 {Execute: RuntimeGetTime},
 		RUNTIME_CHECKWITNESS_NAME:            {Execute: RuntimeCheckWitness, Validator: validatorCheckWitness},
 		RUNTIME_NOTIFY_NAME:                  {Execute: RuntimeNotify, Validator: validatorNotify},
@@ -74,6 +76,9 @@ var (
 		RUNTIME_SERIALIZE_NAME:               {Execute: RuntimeSerialize, Validator: validatorSerialize},
 		RUNTIME_DESERIALIZE_NAME:             {Execute: RuntimeDeserialize, Validator: validatorDeserialize},
 		NATIVE_INVOKE_NAME:                   {Execute: NativeInvoke},
+		CRYPTO_VERIFY_NAME:                   {Execute: CryptoVerify, Validator: validatorCryptoVerify},
+		CRYPTO_VERIFYMULTISIG_NAME:           {Execute: CryptoVerifyMultiSig, Validator: validatorCryptoVerifyMultiSig},
+		CRYPTO_VERIFYONTID_NAME:              {Execute: CryptoVerifyOntId, Validator: validatorCryptoVerify},
 		STORAGE_GET_NAME:                     {Execute: StorageGet},
 		STORAGE_PUT_NAME:                     {Execute: StoragePut},
 		STORAGE_DELETE_NAME:                  {Execute: StorageDelete},
@@ -88,6 +93,7 @@ var (
 		RUNTIME_BASE58TOADDRESS_NAME:     {Execute: RuntimeBase58ToAddress},
 		RUNTIME_ADDRESSTOBASE58_NAME:     {Execute: RuntimeAddressToBase58},
 		RUNTIME_GETCURRENTBLOCKHASH_NAME: {Execute: RuntimeGetCurrentBlockHash},
+		RUNTIME_GETRANDOMNESS_NAME:       {Execute: RuntimeGetRandomness, Validator: validatorGetRandomness},
 	}
 )
 
@@ -116,26 +122,43 @@ type Service struct {
 }
 
 // NeoVmService is a struct for smart contract provide interop service
+// ContractAddress is the address of executing contract, it differs from the address of Code after upgrade
 type NeoVmService struct {
-	Store         store.LedgerStore
-	CacheDB       *storage.CacheDB
-	ContextRef    context.ContextRef
-	Notifications []*event.NotifyEventInfo
-	Code          []byte
-	Tx            *types.Transaction
-	Time          uint32
-	Height        uint32
-	BlockHash     scommon.Uint256
-	Engine        *vm.ExecutionEngine
-	PreExec       bool
+	Store            store.LedgerStore
+	CacheDB          *storage.CacheDB
+	ContextRef       context.ContextRef
+	Notifications    []*event.NotifyEventInfo
+	Code             []byte
+	ContractAddress  scommon.Address
+	Tx               *types.Transaction
+	Time             uint32
+	Height           uint32
+	BlockHash        scommon.Uint256
+	ConsensusPayload []byte
+	Engine           *vm.ExecutionEngine
+	PreExec          bool
+	Debugger         Debugger
 }
 
 // Invoke a smart contract
 func (this *NeoVmService) Invoke() (interface{}, error) {
+	if this.Debugger == nil {
+		return this.invoke()
+	}
+	this.Debugger.Enter(this)
+	result, err := this.invoke()
+	this.Debugger.Exit(this, err)
+	return result, err
+}
+
+func (this *NeoVmService) invoke() (interface{}, error) {
 	if len(this.Code) == 0 {
 		return nil, ERR_EXECUTE_CODE
 	}
-	this.ContextRef.PushContext(&context.Context{ContractAddress: scommon.AddressFromVmCode(this.Code), Code: this.Code})
+	if this.ContractAddress == scommon.ADDRESS_EMPTY {
+		this.ContractAddress = scommon.AddressFromVmCode(this.Code)
+	}
+	this.ContextRef.PushContext(&context.Context{ContractAddress: this.ContractAddress, Code: this.Code})
 	this.Engine.PushContext(vm.NewExecutionContext(this.Engine, this.Code))
 	for {
 		//check the execution step count
@@ -148,6 +171,11 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 		if this.Engine.Context.GetInstructionPointer() >= len(this.Engine.Context.Code) {
 			break
 		}
+		if this.Debugger != nil {
+			if err := this.Debugger.Step(this, this.Engine.Context.GetInstructionPointer()); err != nil {
+				return nil, err
+			}
+		}
 		if err := this.Engine.ExecuteCode(); err != nil {
 			return nil, err
 		}
@@ -223,15 +251,11 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
-			code, err := this.getContract(addr)
-			if err != nil {
-				return nil, err
-			}
-			service, err := this.ContextRef.NewExecuteEngine(code)
+			service, err := this.newContractEngine(addr)
 			if err != nil {
 				return nil, err
 			}
-			this.Engine.EvaluationStack.CopyTo(service.(*NeoVmService).Engine.EvaluationStack)
+			this.Engine.EvaluationStack.CopyTo(service.Engine.EvaluationStack)
 			result, err := service.Invoke()
 			if err != nil {
 				return nil, err
@@ -296,6 +320,48 @@ func (this *NeoVmService) getContract(address scommon.Address) ([]byte, error) {
 	return dep.Code, nil
 }
 
+//newContractEngine create the execute engine of contract deployed at address
+func (this *NeoVmService) newContractEngine(address scommon.Address) (*NeoVmService, error) {
+	code, err := this.getContract(address)
+	if err != nil {
+		return nil, err
+	}
+	engine, err := this.ContextRef.NewExecuteEngine(code)
+	if err != nil {
+		return nil, err
+	}
+	service := engine.(*NeoVmService)
+	service.ContractAddress = address
+	return service, nil
+}
+
+//CallContract call method of the contract deployed at address with code, args is a script pushing the
+//parameters of method. caller is pushed as the calling context, so that CheckWitness of caller passes
+//in the contract
+func CallContract(ref context.ContextRef, caller, address scommon.Address, code []byte, method string,
+	args []byte) (interface{}, error) {
+	engine, err := ref.NewExecuteEngine(code)
+	if err != nil {
+		return nil, err
+	}
+	service := engine.(*NeoVmService)
+	service.ContractAddress = address
+	if len(args) > 0 {
+		argsEngine, err := ref.NewExecuteEngine(args)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := argsEngine.Invoke(); err != nil {
+			return nil, fmt.Errorf("push args error: %v", err)
+		}
+		argsEngine.(*NeoVmService).Engine.EvaluationStack.CopyTo(service.Engine.EvaluationStack)
+	}
+	vm.PushData(service.Engine, []byte(method))
+	ref.PushContext(&context.Context{ContractAddress: caller})
+	defer ref.PopContext()
+	return service.Invoke()
+}
+
 func checkStackSize(engine *vm.ExecutionEngine) bool {
 	size := 0
 	if engine.OpCode < vm.PUSH16 {
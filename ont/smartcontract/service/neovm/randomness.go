@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ontio/ontology/common/config"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/types"
+	vm "github.com/ontio/ontology/vm/neovm"
+)
+
+// RuntimeGetRandomness put the verified VRF value of the block at the current or a past height to vm stack,
+// see docs/specifications/randomness.md for the bias of the value and how to use it
+func RuntimeGetRandomness(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	if service.Height < config.GetNativeUpgradeHeight() {
+		return fmt.Errorf("[RuntimeGetRandomness] block num is not reached for this func")
+	}
+	height, err := vm.PopInt(engine)
+	if err != nil {
+		return err
+	}
+	if height <= 0 || height > int(service.Height) {
+		return fmt.Errorf("[RuntimeGetRandomness] invalid height %d, current height %d", height, service.Height)
+	}
+	value, err := getVerifiedVrf(service, uint32(height))
+	if err != nil {
+		return fmt.Errorf("[RuntimeGetRandomness] %v", err)
+	}
+	vm.PushData(engine, value)
+	return nil
+}
+
+// getVerifiedVrf return the VRF value of block at height after verifying it with the key of proposer
+func getVerifiedVrf(service *NeoVmService, height uint32) ([]byte, error) {
+	info, err := getVbftBlockInfo(service, height)
+	if err != nil {
+		return nil, err
+	}
+	prevInfo, err := getVbftBlockInfo(service, height-1)
+	if err != nil {
+		return nil, err
+	}
+	// the proposer of block is chosen by the chain config in effect at the previous block
+	cfgInfo := prevInfo
+	if prevInfo.NewChainConfig == nil {
+		if prevInfo.LastConfigBlockNum == math.MaxUint32 {
+			return nil, fmt.Errorf("chain config of block %d not found", height)
+		}
+		if cfgInfo, err = getVbftBlockInfo(service, prevInfo.LastConfigBlockNum); err != nil {
+			return nil, err
+		}
+		if cfgInfo.NewChainConfig == nil {
+			return nil, fmt.Errorf("chain config of block %d not found", height)
+		}
+	}
+	var proposer *vconfig.PeerConfig
+	for _, peer := range cfgInfo.NewChainConfig.Peers {
+		if peer.Index == info.Proposer {
+			proposer = peer
+			break
+		}
+	}
+	if proposer == nil {
+		return nil, fmt.Errorf("proposer %d of block %d not found", info.Proposer, height)
+	}
+	pubKey, err := vconfig.Pubkey(proposer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of proposer %d: %v", info.Proposer, err)
+	}
+	if err := vconfig.VerifyVrf(pubKey, height, prevInfo.VrfValue, info.VrfValue, info.VrfProof); err != nil {
+		return nil, fmt.Errorf("block %d: %v", height, err)
+	}
+	return info.VrfValue, nil
+}
+
+// getVbftBlockInfo return the vbft consensus payload of block at height, the current block is not in
+// the store during execution, its payload is taken from the service
+func getVbftBlockInfo(service *NeoVmService, height uint32) (*vconfig.VbftBlockInfo, error) {
+	header := &types.Header{ConsensusPayload: service.ConsensusPayload}
+	if height == service.Height {
+		if len(service.ConsensusPayload) == 0 {
+			return nil, fmt.Errorf("consensus payload of current block %d is not available", height)
+		}
+	} else {
+		var err error
+		if header, err = service.Store.GetHeaderByHeight(height); err != nil {
+			return nil, fmt.Errorf("get header of block %d error: %v", height, err)
+		}
+		if header == nil {
+			return nil, fmt.Errorf("header of block %d not found", height)
+		}
+	}
+	return vconfig.VbftBlock(header)
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/config"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/store"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+type headerStore struct {
+	store.LedgerStore
+	headers map[uint32]*types.Header
+}
+
+func (this *headerStore) GetHeaderByHeight(height uint32) (*types.Header, error) {
+	return this.headers[height], nil
+}
+
+func vbftHeader(t *testing.T, height uint32, info *vconfig.VbftBlockInfo) *types.Header {
+	payload, err := json.Marshal(info)
+	assert.Nil(t, err)
+	return &types.Header{Height: height, ConsensusPayload: payload}
+}
+
+func nextVbftBlockInfo(t *testing.T, acc *account.Account, height, configHeight uint32,
+	prev *vconfig.VbftBlockInfo) *vconfig.VbftBlockInfo {
+	data, err := vconfig.VrfData(height, prev.VrfValue)
+	assert.Nil(t, err)
+	value, proof, err := vrf.Vrf(acc.PrivateKey, data)
+	assert.Nil(t, err)
+	return &vconfig.VbftBlockInfo{Proposer: 1, VrfValue: value, VrfProof: proof, LastConfigBlockNum: configHeight}
+}
+
+func TestRuntimeGetRandomness(t *testing.T) {
+	acc := account.NewAccount("")
+	genesis := &vconfig.VbftBlockInfo{
+		Proposer:           math.MaxUint32,
+		VrfValue:           make([]byte, vconfig.VRF_SIZE),
+		LastConfigBlockNum: math.MaxUint32,
+		NewChainConfig: &vconfig.ChainConfig{
+			Peers: []*vconfig.PeerConfig{{Index: 1, ID: vconfig.PubkeyID(acc.PublicKey)}},
+		},
+	}
+	//the chain config block is at the upgrade height, so that the syscall is available
+	base := config.GetNativeUpgradeHeight()
+	block1 := nextVbftBlockInfo(t, acc, base+1, base, genesis)
+	block2 := nextVbftBlockInfo(t, acc, base+2, base, block1)
+	service := &NeoVmService{
+		Store: &headerStore{headers: map[uint32]*types.Header{
+			base:     vbftHeader(t, base, genesis),
+			base + 1: vbftHeader(t, base+1, block1),
+		}},
+		Height:           base + 2,
+		ConsensusPayload: vbftHeader(t, base+2, block2).ConsensusPayload,
+	}
+
+	getRandomness := func(height int64) ([]byte, error) {
+		engine := neovm.NewExecutionEngine()
+		neovm.PushData(engine, height)
+		if err := RuntimeGetRandomness(service, engine); err != nil {
+			return nil, err
+		}
+		return neovm.PopByteArray(engine)
+	}
+	value, err := getRandomness(int64(base) + 1)
+	assert.Nil(t, err)
+	assert.Equal(t, block1.VrfValue, value)
+	value, err = getRandomness(int64(base) + 2)
+	assert.Nil(t, err)
+	assert.Equal(t, block2.VrfValue, value)
+	_, err = getRandomness(0)
+	assert.NotNil(t, err)
+	_, err = getRandomness(int64(base) + 3)
+	assert.NotNil(t, err)
+
+	// the value of a block does not match its proof
+	forged := *block2
+	forged.VrfValue = block1.VrfValue
+	service.ConsensusPayload = vbftHeader(t, 2, &forged).ConsensusPayload
+	_, err = getRandomness(int64(base) + 2)
+	assert.NotNil(t, err)
+
+	// the current block is not available in pre-execution
+	service.ConsensusPayload = nil
+	_, err = getRandomness(int64(base) + 2)
+	assert.NotNil(t, err)
+	_, err = getRandomness(int64(base) + 1)
+	assert.Nil(t, err)
+
+	if base > 0 {
+		service.Height = base - 1
+		_, err = getRandomness(int64(base) - 1)
+		assert.NotNil(t, err)
+	}
+}
@@ -153,6 +153,37 @@ func validatorGetCode(engine *vm.ExecutionEngine) error {
 	return nil
 }
 
+func validatorTryCall(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 3 {
+		return errors.NewErr("[validatorTryCall] Too few input parameters ")
+	}
+	return nil
+}
+
+func validatorCryptoVerify(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 4 {
+		return errors.NewErr("[validatorCryptoVerify] Too few input parameters ")
+	}
+	return nil
+}
+
+func validatorCryptoVerifyMultiSig(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 4 {
+		return errors.NewErr("[validatorCryptoVerifyMultiSig] Too few input parameters ")
+	}
+	if _, err := vm.PeekNStackItem(1, engine).GetArray(); err != nil {
+		return errors.NewErr("[validatorCryptoVerifyMultiSig] public keys wrong type!")
+	}
+	return nil
+}
+
+func validatorGetRandomness(engine *vm.ExecutionEngine) error {
+	if vm.EvaluationStackCount(engine) < 1 {
+		return errors.NewErr("[validatorGetRandomness] Too few input parameters ")
+	}
+	return nil
+}
+
 func validatorCheckWitness(engine *vm.ExecutionEngine) error {
 	if vm.EvaluationStackCount(engine) < 1 {
 		return errors.NewErr("[validatorCheckWitness] Too few input parameters ")
@@ -46,14 +46,16 @@ type SmartContract struct {
 	Gas           uint64
 	ExecStep      int
 	PreExec       bool
+	Debugger      neovm.Debugger // optional observer of neovm execution
 }
 
 // Config describe smart contract need parameters configuration
 type Config struct {
-	Time      uint32              // current block timestamp
-	Height    uint32              // current block height
-	BlockHash common.Uint256      // current block hash
-	Tx        *ctypes.Transaction // current transaction
+	Time             uint32              // current block timestamp
+	Height           uint32              // current block height
+	BlockHash        common.Uint256      // current block hash
+	ConsensusPayload []byte              // consensus payload of current block header, empty in pre-execution
+	Tx               *ctypes.Transaction // current transaction
 }
 
 // PushContext push current context to smart contract
@@ -113,6 +115,44 @@ func (this *SmartContract) CheckUseGas(gas uint64) bool {
 	return true
 }
 
+func (this *SmartContract) GetRemainGas() uint64 {
+	return this.Gas
+}
+
+// InvokeWithGasLimit invoke engine with at most gasLimit gas, when the execution fails, the state changes,
+// notifications and contexts of engine are reverted and only the consumed gas is charged
+func (this *SmartContract) InvokeWithGasLimit(engine context.Engine, gasLimit uint64) (interface{}, error) {
+	gas := this.Gas
+	if gasLimit > gas {
+		gasLimit = gas
+	}
+	this.Gas = gasLimit
+	snapshot := this.CacheDB.Snapshot()
+	notifications := len(this.Notifications)
+	contexts := len(this.Contexts)
+
+	result, err := engine.Invoke()
+	this.Gas = gas - (gasLimit - this.Gas)
+	if err != nil {
+		this.CacheDB.RevertToSnapshot(snapshot)
+		this.Notifications = this.Notifications[:notifications]
+		this.Contexts = this.Contexts[:contexts]
+		return nil, err
+	}
+	this.CacheDB.DiscardSnapshot(snapshot)
+	return result, nil
+}
+
+// InvokeAsTransaction invoke engine like InvokeWithGasLimit, but the execution steps of engine are counted
+// from zero as if it were a transaction on its own, and are not added to the steps of current transaction
+func (this *SmartContract) InvokeAsTransaction(engine context.Engine, gasLimit uint64) (interface{}, error) {
+	execStep := this.ExecStep
+	this.ExecStep = 0
+	result, err := this.InvokeWithGasLimit(engine, gasLimit)
+	this.ExecStep = execStep
+	return result, err
+}
+
 func (this *SmartContract) checkContexts() bool {
 	if len(this.Contexts) > MAX_EXECUTE_ENGINE {
 		return false
@@ -127,16 +167,19 @@ func (this *SmartContract) NewExecuteEngine(code []byte) (context.Engine, error)
 		return nil, fmt.Errorf("%s", "engine over max limit!")
 	}
 	service := &neovm.NeoVmService{
-		Store:      this.Store,
-		CacheDB:    this.CacheDB,
-		ContextRef: this,
-		Code:       code,
-		Tx:         this.Config.Tx,
-		Time:       this.Config.Time,
-		Height:     this.Config.Height,
-		BlockHash:  this.Config.BlockHash,
-		Engine:     vm.NewExecutionEngine(),
-		PreExec:    this.PreExec,
+		Store:            this.Store,
+		CacheDB:          this.CacheDB,
+		ContextRef:       this,
+		Code:             code,
+		ContractAddress:  common.AddressFromVmCode(code),
+		Tx:               this.Config.Tx,
+		Time:             this.Config.Time,
+		Height:           this.Config.Height,
+		BlockHash:        this.Config.BlockHash,
+		ConsensusPayload: this.Config.ConsensusPayload,
+		Engine:           vm.NewExecutionEngine(),
+		PreExec:          this.PreExec,
+		Debugger:         this.Debugger,
 	}
 	return service, nil
 }
@@ -111,8 +111,9 @@ func (this *ContractInvokeParam) Deserialization(source *common.ZeroCopySource)
 }
 
 type PreExecResult struct {
-	State  byte
-	Gas    uint64
-	Result interface{}
-	Notify []*event.NotifyEventInfo
+	State   byte
+	Gas     uint64
+	Result  interface{}
+	Notify  []*event.NotifyEventInfo
+	Profile *Profile `json:",omitempty"`
 }
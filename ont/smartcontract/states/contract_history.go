@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"io"
+
+	"github.com/ontio/ontology/common"
+)
+
+//CONTRACT_HISTORY_KEY is the suffix appended to contract address for the key of its version history
+const CONTRACT_HISTORY_KEY = "history"
+
+//ContractVersion is one version of an upgradable contract.
+//Height and TxHash are zero for the version deployed before the first upgrade
+type ContractVersion struct {
+	Height      uint32
+	TxHash      common.Uint256
+	CodeHash    common.Address
+	Name        string
+	Version     string
+	Author      string
+	Email       string
+	Description string
+}
+
+//ContractHistory is the versions of a contract ordered from the oldest to the current one
+type ContractHistory struct {
+	Versions []*ContractVersion
+}
+
+//ContractHistoryKey return the key of contract history under ST_CONTRACT prefix
+func ContractHistoryKey(address common.Address) []byte {
+	return append(address[:], CONTRACT_HISTORY_KEY...)
+}
+
+func (this *ContractVersion) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.Height)
+	sink.WriteHash(this.TxHash)
+	sink.WriteAddress(this.CodeHash)
+	sink.WriteString(this.Name)
+	sink.WriteString(this.Version)
+	sink.WriteString(this.Author)
+	sink.WriteString(this.Email)
+	sink.WriteString(this.Description)
+}
+
+func (this *ContractVersion) Deserialization(source *common.ZeroCopySource) error {
+	var irregular, eof bool
+	this.Height, eof = source.NextUint32()
+	this.TxHash, eof = source.NextHash()
+	this.CodeHash, eof = source.NextAddress()
+	for _, field := range []*string{&this.Name, &this.Version, &this.Author, &this.Email, &this.Description} {
+		*field, _, irregular, eof = source.NextString()
+		if irregular {
+			return common.ErrIrregularData
+		}
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+func (this *ContractHistory) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Versions)))
+	for _, v := range this.Versions {
+		v.Serialization(sink)
+	}
+}
+
+func (this *ContractHistory) Deserialization(source *common.ZeroCopySource) error {
+	n, _, irregular, eof := source.NextVarUint()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Versions = nil
+	for i := uint64(0); i < n; i++ {
+		v := new(ContractVersion)
+		if err := v.Deserialization(source); err != nil {
+			return err
+		}
+		this.Versions = append(this.Versions, v)
+	}
+	return nil
+}
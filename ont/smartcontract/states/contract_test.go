@@ -43,3 +43,24 @@ func TestContract_Serialize_Deserialize(t *testing.T) {
 		t.Fatalf("ContractInvokeParam deserialize error: %v", err)
 	}
 }
+
+func TestContractHistory_Serialization(t *testing.T) {
+	history := &ContractHistory{Versions: []*ContractVersion{
+		{CodeHash: common.AddressFromVmCode([]byte{1}), Name: "name", Version: "1"},
+		{Height: 10, TxHash: common.Uint256{1}, CodeHash: common.AddressFromVmCode([]byte{2}), Name: "name", Version: "2",
+			Author: "author", Email: "email", Description: "desc"},
+	}}
+	sink := common.NewZeroCopySink(nil)
+	history.Serialization(sink)
+
+	v := new(ContractHistory)
+	if err := v.Deserialization(common.NewZeroCopySource(sink.Bytes())); err != nil {
+		t.Fatalf("ContractHistory deserialize error: %v", err)
+	}
+	if len(v.Versions) != 2 || *v.Versions[0] != *history.Versions[0] || *v.Versions[1] != *history.Versions[1] {
+		t.Fatalf("ContractHistory deserialize mismatch: %v", v.Versions)
+	}
+	if err := v.Deserialization(common.NewZeroCopySource(sink.Bytes()[:sink.Size()-1])); err == nil {
+		t.Fatal("ContractHistory deserialize truncated data should fail")
+	}
+}
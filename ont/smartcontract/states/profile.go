@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+//GasCost is the gas consumed by one kind of operation
+type GasCost struct {
+	Name  string
+	Count uint64
+	Gas   uint64
+}
+
+//InstructionCost is the gas consumed by the instruction at Offset of Contract, gas of called contract excluded
+type InstructionCost struct {
+	Contract string
+	Offset   int
+	OpCode   string
+	Syscall  string `json:",omitempty"`
+	Count    uint64
+	Gas      uint64
+}
+
+//Profile is the gas breakdown of a neovm execution
+type Profile struct {
+	Gas          uint64             // gas consumed by all instructions
+	Instructions []*InstructionCost // grouped by contract and code offset
+	OpCodes      []*GasCost         // grouped by opcode name
+	Syscalls     []*GasCost         // grouped by syscall name
+	NativeCalls  []*GasCost         // grouped by native contract address and method
+	Contracts    []*GasCost         // grouped by contract address, gas of called contract included
+}
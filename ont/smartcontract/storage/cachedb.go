@@ -23,6 +23,7 @@ import (
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract/states"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
@@ -32,6 +33,15 @@ type CacheDB struct {
 	memdb      *overlaydb.MemDB
 	backend    *overlaydb.OverlayDB
 	keyScratch []byte
+	journal    []journalEntry // writes since the first alive snapshot
+	snapshots  int            // count of alive snapshots
+}
+
+// journalEntry record the value of key in transaction cache before a write
+type journalEntry struct {
+	key     []byte
+	value   []byte
+	unknown bool // the key is not in transaction cache
 }
 
 const initCap = 16 * 1024
@@ -47,6 +57,50 @@ func NewCacheDB(store *overlaydb.OverlayDB) *CacheDB {
 
 func (self *CacheDB) Reset() {
 	self.memdb.Reset()
+	self.journal = nil
+	self.snapshots = 0
+}
+
+// Snapshot mark the current state of transaction cache, the later writes can be reverted by RevertToSnapshot
+func (self *CacheDB) Snapshot() int {
+	self.snapshots += 1
+	return len(self.journal)
+}
+
+// RevertToSnapshot revert the writes after the snapshot. Only the journaled keys are restored, a key
+// which was not in transaction cache before the snapshot is removed from it, instead of being deleted
+func (self *CacheDB) RevertToSnapshot(snapshot int) {
+	for i := len(self.journal) - 1; i >= snapshot; i-- {
+		entry := self.journal[i]
+		if entry.unknown {
+			self.memdb.Remove(entry.key)
+		} else {
+			self.memdb.Put(entry.key, entry.value)
+		}
+	}
+	self.journal = self.journal[:snapshot]
+	self.DiscardSnapshot(snapshot)
+}
+
+// DiscardSnapshot keep the writes after the snapshot
+func (self *CacheDB) DiscardSnapshot(snapshot int) {
+	self.snapshots -= 1
+	if self.snapshots <= 0 {
+		self.snapshots = 0
+		self.journal = nil
+	}
+}
+
+func (self *CacheDB) record(key []byte) {
+	if self.snapshots == 0 {
+		return
+	}
+	value, unknown := self.memdb.Get(key)
+	self.journal = append(self.journal, journalEntry{
+		key:     append([]byte{}, key...),
+		value:   append([]byte{}, value...),
+		unknown: unknown,
+	})
 }
 
 func ensureBuffer(b []byte, n int) []byte {
@@ -74,12 +128,22 @@ func (self *CacheDB) Commit() {
 	})
 }
 
+// ForEachStorage iterate the uncommitted storage writes of the transaction cache, deleted item has an empty value
+func (self *CacheDB) ForEachStorage(f func(key, val []byte)) {
+	self.memdb.ForEach(func(key, val []byte) {
+		if len(key) != 0 && key[0] == byte(common.ST_STORAGE) {
+			f(key[1:], val)
+		}
+	})
+}
+
 func (self *CacheDB) Put(key []byte, value []byte) {
 	self.put(common.ST_STORAGE, key, value)
 }
 
 func (self *CacheDB) put(prefix common.DataEntryPrefix, key []byte, value []byte) {
 	self.keyScratch = makePrefixedKey(self.keyScratch, byte(prefix), key)
+	self.record(self.keyScratch)
 	self.memdb.Put(self.keyScratch, value)
 }
 
@@ -101,8 +165,11 @@ func (self *CacheDB) GetContract(addr comm.Address) (*payload.DeployCode, error)
 }
 
 func (self *CacheDB) PutContract(contract *payload.DeployCode) error {
-	address := contract.Address()
+	return self.ReplaceContract(contract.Address(), contract)
+}
 
+//ReplaceContract put contract at address, which may differ from the address derived from contract code
+func (self *CacheDB) ReplaceContract(address comm.Address, contract *payload.DeployCode) error {
 	sink := comm.NewZeroCopySink(nil)
 	err := contract.Serialization(sink)
 	if err != nil {
@@ -118,6 +185,31 @@ func (self *CacheDB) DeleteContract(address comm.Address) {
 	self.delete(common.ST_CONTRACT, address[:])
 }
 
+func (self *CacheDB) GetContractHistory(address comm.Address) (*states.ContractHistory, error) {
+	value, err := self.get(common.ST_CONTRACT, states.ContractHistoryKey(address))
+	if err != nil {
+		return nil, err
+	}
+	history := new(states.ContractHistory)
+	if len(value) == 0 {
+		return history, nil
+	}
+	if err := history.Deserialization(comm.NewZeroCopySource(value)); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (self *CacheDB) PutContractHistory(address comm.Address, history *states.ContractHistory) {
+	sink := comm.NewZeroCopySink(nil)
+	history.Serialization(sink)
+	self.put(common.ST_CONTRACT, states.ContractHistoryKey(address), sink.Bytes())
+}
+
+func (self *CacheDB) DeleteContractHistory(address comm.Address) {
+	self.delete(common.ST_CONTRACT, states.ContractHistoryKey(address))
+}
+
 func (self *CacheDB) Get(key []byte) ([]byte, error) {
 	return self.get(common.ST_STORAGE, key)
 }
@@ -143,6 +235,7 @@ func (self *CacheDB) Delete(key []byte) {
 // Delete item from cache
 func (self *CacheDB) delete(prefix common.DataEntryPrefix, key []byte) {
 	self.keyScratch = makePrefixedKey(self.keyScratch, byte(prefix), key)
+	self.record(self.keyScratch)
 	self.memdb.Delete(self.keyScratch)
 }
 
@@ -82,3 +82,62 @@ func TestCacheDB(t *testing.T) {
 	}
 
 }
+
+func TestCacheDBForEachStorage(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	cache := NewCacheDB(overlaydb.NewOverlayDB(memback))
+	cache.Put([]byte("k1"), []byte("v1"))
+	cache.Put([]byte("k2"), []byte("v2"))
+	cache.Delete([]byte("k2"))
+
+	items := make(map[string]string)
+	cache.ForEachStorage(func(key, val []byte) {
+		items[string(key)] = string(val)
+	})
+	assert.Equal(t, map[string]string{"k1": "v1", "k2": ""}, items)
+}
+
+func TestCacheDBSnapshot(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	overlay := overlaydb.NewOverlayDB(memback)
+	overlay.Put(append([]byte{byte(common.ST_STORAGE)}, "k0"...), []byte("v0"))
+	cache := NewCacheDB(overlay)
+	cache.Put([]byte("k1"), []byte("v1"))
+
+	outer := cache.Snapshot()
+	cache.Put([]byte("k1"), []byte("v2"))
+	cache.Delete([]byte("k0"))
+	inner := cache.Snapshot()
+	cache.Put([]byte("k3"), []byte("v3"))
+	cache.DiscardSnapshot(inner)
+	cache.RevertToSnapshot(outer)
+
+	value, _ := cache.Get([]byte("k0"))
+	assert.Equal(t, []byte("v0"), value)
+	value, _ = cache.Get([]byte("k1"))
+	assert.Equal(t, []byte("v1"), value)
+	value, _ = cache.Get([]byte("k3"))
+	assert.Nil(t, value)
+}
+
+func TestCacheDBRevertUntouched(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	overlay := overlaydb.NewOverlayDB(memback)
+	cache := NewCacheDB(overlay)
+	cache.Put([]byte("k1"), []byte("v1"))
+	cache.Delete([]byte("k2"))
+
+	snapshot := cache.Snapshot()
+	cache.Put([]byte("k1"), []byte("v2"))
+	cache.Put([]byte("k2"), []byte("v2"))
+	cache.Put([]byte("k3"), []byte("v3"))
+	cache.Put([]byte("k3"), []byte("v4"))
+	cache.RevertToSnapshot(snapshot)
+
+	//keys untouched before the snapshot are not turned into deletes
+	items := make(map[string]string)
+	cache.ForEachStorage(func(key, val []byte) {
+		items[string(key)] = string(val)
+	})
+	assert.Equal(t, map[string]string{"k1": "v1", "k2": ""}, items)
+}
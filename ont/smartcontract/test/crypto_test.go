@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	ctypes "github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/ontio/ontology/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func runCrypto(t *testing.T, code []byte) (bool, error) {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	return runCryptoWithCache(t, storage.NewCacheDB(overlaydb.NewOverlayDB(memback)), code)
+}
+
+func runCryptoWithCache(t *testing.T, cache *storage.CacheDB, code []byte) (bool, error) {
+	return runCryptoAt(t, cache, config.GetNativeUpgradeHeight(), code)
+}
+
+func runCryptoAt(t *testing.T, cache *storage.CacheDB, height uint32, code []byte) (bool, error) {
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: height},
+		CacheDB: cache,
+		Gas:     100000,
+	}
+	engine, err := sc.NewExecuteEngine(code)
+	assert.Nil(t, err)
+	result, err := engine.Invoke()
+	if err != nil {
+		return false, err
+	}
+	return result.(types.StackItems).GetBoolean()
+}
+
+func emitByteArrays(builder *vm.ParamsBuilder, items [][]byte) {
+	for i := len(items) - 1; i >= 0; i-- {
+		builder.EmitPushByteArray(items[i])
+	}
+	builder.EmitPushInteger(big.NewInt(int64(len(items))))
+	builder.Emit(vm.PACK)
+}
+
+func TestCryptoVerify(t *testing.T) {
+	acc := account.NewAccount("")
+	data := []byte("hello")
+	sig, err := signature.Sign(acc, data)
+	assert.Nil(t, err)
+	sigObj, err := s.Deserialize(sig)
+	assert.Nil(t, err)
+	scheme, raw := int64(sigObj.Scheme), sig[len(sig)-64:]
+
+	verify := func(scheme int64, sig, data []byte) (bool, error) {
+		builder := vm.NewParamsBuilder(new(bytes.Buffer))
+		builder.EmitPushByteArray(data)
+		builder.EmitPushByteArray(sig)
+		builder.EmitPushByteArray(keypair.SerializePublicKey(acc.PublicKey))
+		builder.EmitPushInteger(big.NewInt(scheme))
+		builder.Emit(vm.SYSCALL)
+		builder.EmitPushByteArray([]byte(neovm.CRYPTO_VERIFY_NAME))
+		return runCrypto(t, builder.ToArray())
+	}
+
+	ok, err := verify(scheme, raw, data)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = verify(scheme, raw, []byte("other"))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = verify(int64(s.SM3withSM2), raw, data)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = verify(256, raw, data)
+	assert.NotNil(t, err)
+}
+
+func TestCryptoVerifyBeforeUpgrade(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	if upgrade == 0 {
+		return
+	}
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+	for _, name := range []string{neovm.CRYPTO_VERIFY_NAME, neovm.CRYPTO_VERIFYMULTISIG_NAME, neovm.CRYPTO_VERIFYONTID_NAME} {
+		builder := vm.NewParamsBuilder(new(bytes.Buffer))
+		builder.EmitPushByteArray([]byte("data"))
+		emitByteArrays(builder, [][]byte{[]byte("sig")})
+		emitByteArrays(builder, [][]byte{[]byte("key")})
+		builder.EmitPushInteger(big.NewInt(1))
+		builder.Emit(vm.SYSCALL)
+		builder.EmitPushByteArray([]byte(name))
+		_, err := runCryptoAt(t, cache, upgrade-1, builder.ToArray())
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestCryptoVerifyMultiSig(t *testing.T) {
+	data := []byte("hello")
+	var keys, sigs [][]byte
+	for i := 0; i < 3; i++ {
+		acc := account.NewAccount("")
+		keys = append(keys, keypair.SerializePublicKey(acc.PublicKey))
+		sig, err := signature.Sign(acc, data)
+		assert.Nil(t, err)
+		sigs = append(sigs, sig)
+	}
+
+	verify := func(m int64, sigs [][]byte) (bool, error) {
+		builder := vm.NewParamsBuilder(new(bytes.Buffer))
+		builder.EmitPushByteArray(data)
+		emitByteArrays(builder, sigs)
+		emitByteArrays(builder, keys)
+		builder.EmitPushInteger(big.NewInt(m))
+		builder.Emit(vm.SYSCALL)
+		builder.EmitPushByteArray([]byte(neovm.CRYPTO_VERIFYMULTISIG_NAME))
+		return runCrypto(t, builder.ToArray())
+	}
+
+	ok, err := verify(2, sigs[1:])
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = verify(3, sigs[1:])
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = verify(4, sigs)
+	assert.NotNil(t, err)
+}
+
+//callOntId invoke the ONT ID contract with transaction signed by signer
+func callOntId(t *testing.T, cache *storage.CacheDB, signer *account.Account, method string, args ...[]byte) error {
+	sc := &smartcontract.SmartContract{
+		Config: &smartcontract.Config{Height: config.GetNativeUpgradeHeight(),
+			Tx: &ctypes.Transaction{SignedAddr: []common.Address{signer.Address}}},
+		CacheDB: cache,
+		Gas:     math.MaxUint64,
+	}
+	service, err := sc.NewNativeService()
+	assert.Nil(t, err)
+	bf := new(bytes.Buffer)
+	for _, arg := range args {
+		assert.Nil(t, serialization.WriteVarBytes(bf, arg))
+	}
+	_, err = service.NativeCall(utils.OntIDContractAddress, method, bf.Bytes())
+	return err
+}
+
+func TestCryptoVerifyOntId(t *testing.T) {
+	ontid.Init()
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+
+	acc1, acc2 := account.NewAccount(""), account.NewAccount("")
+	pk1, pk2 := keypair.SerializePublicKey(acc1.PublicKey), keypair.SerializePublicKey(acc2.PublicKey)
+	id, err := account.GenerateID()
+	assert.Nil(t, err)
+	data := []byte("hello")
+	sig1, err := signature.Sign(acc1, data)
+	assert.Nil(t, err)
+	sig2, err := signature.Sign(acc2, data)
+	assert.Nil(t, err)
+
+	verify := func(id string, index int64, sig, data []byte) (bool, error) {
+		builder := vm.NewParamsBuilder(new(bytes.Buffer))
+		builder.EmitPushByteArray(data)
+		builder.EmitPushByteArray(sig)
+		builder.EmitPushInteger(big.NewInt(index))
+		builder.EmitPushByteArray([]byte(id))
+		builder.Emit(vm.SYSCALL)
+		builder.EmitPushByteArray([]byte(neovm.CRYPTO_VERIFYONTID_NAME))
+		return runCryptoWithCache(t, cache, builder.ToArray())
+	}
+
+	ok, err := verify(id, 1, sig1, data)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, callOntId(t, cache, acc1, "regIDWithPublicKey", []byte(id), pk1))
+	ok, err = verify(id, 1, sig1, data)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = verify(id, 1, sig1, []byte("other"))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = verify(id, 2, sig2, data)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = verify("", 1, sig1, data)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = verify(string(make([]byte, 256)), 1, sig1, data)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	//revoked key is invalid
+	assert.Nil(t, callOntId(t, cache, acc1, "addKey", []byte(id), pk2, pk1))
+	assert.Nil(t, callOntId(t, cache, acc2, "removeKey", []byte(id), pk1, pk2))
+	ok, err = verify(id, 1, sig1, data)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = verify(id, 2, sig2, data)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/ontio/ontology/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func tryCallCode(callee common.Address, gasLimit int64, arg int64) []byte {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushInteger(big.NewInt(arg))
+	builder.Emit(vm.PUSH1)
+	builder.Emit(vm.PACK)
+	builder.EmitPushInteger(big.NewInt(gasLimit))
+	builder.EmitPushByteArray(callee[:])
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.CONTRACT_TRYCALL_NAME))
+	return builder.ToArray()
+}
+
+func storagePutCode() []byte {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushByteArray([]byte("v"))
+	builder.EmitPushByteArray([]byte("k"))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GETCONTEXT_NAME))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_PUT_NAME))
+	return builder.ToArray()
+}
+
+func runTryCall(t *testing.T, callee []byte, gasLimit int64) (*smartcontract.SmartContract, []types.StackItems) {
+	return runTryCallAt(t, callee, common.AddressFromVmCode(callee), gasLimit)
+}
+
+func newTryCallContract(t *testing.T, callee []byte, height uint32) *smartcontract.SmartContract {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: callee}))
+	return &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: height},
+		CacheDB: cache,
+		Gas:     100000,
+	}
+}
+
+func runTryCallAt(t *testing.T, callee []byte, addr common.Address, gasLimit int64) (*smartcontract.SmartContract,
+	[]types.StackItems) {
+	sc := newTryCallContract(t, callee, config.GetNativeUpgradeHeight())
+	engine, err := sc.NewExecuteEngine(tryCallCode(addr, gasLimit, 7))
+	assert.Nil(t, err)
+	result, err := engine.Invoke()
+	assert.Nil(t, err)
+	arr, err := result.(types.StackItems).GetArray()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(arr))
+	return sc, arr
+}
+
+func TestTryCallSuccess(t *testing.T) {
+	callee := append(storagePutCode(), byte(vm.NOP))
+	sc, arr := runTryCall(t, callee, 100000)
+	success, _ := arr[0].GetBoolean()
+	assert.True(t, success)
+	value, _ := arr[1].GetBigInteger()
+	assert.Equal(t, int64(7), value.Int64())
+
+	key := common.AddressFromVmCode(callee)
+	raw, err := sc.CacheDB.Get(append(key[:], 'k'))
+	assert.Nil(t, err)
+	assert.NotNil(t, raw)
+}
+
+func TestTryCallFailure(t *testing.T) {
+	callee := append(storagePutCode(), byte(vm.THROW))
+	sc, arr := runTryCall(t, callee, 100000)
+	success, _ := arr[0].GetBoolean()
+	assert.False(t, success)
+
+	key := common.AddressFromVmCode(callee)
+	raw, err := sc.CacheDB.Get(append(key[:], 'k'))
+	assert.Nil(t, err)
+	assert.Nil(t, raw)
+	assert.Equal(t, 1, len(sc.Contexts))
+}
+
+func TestTryCallGasLimit(t *testing.T) {
+	callee := append(storagePutCode(), byte(vm.NOP))
+	sc, arr := runTryCall(t, callee, int64(neovm.STORAGE_PUT_GAS-1))
+	success, _ := arr[0].GetBoolean()
+	assert.False(t, success)
+	assert.True(t, sc.Gas > 100000-neovm.STORAGE_PUT_GAS)
+}
+
+func TestTryCallMissingContract(t *testing.T) {
+	callee := append(storagePutCode(), byte(vm.NOP))
+	sc, arr := runTryCallAt(t, callee, common.Address{1}, 100000)
+	success, _ := arr[0].GetBoolean()
+	assert.False(t, success)
+	value, _ := arr[1].GetByteArray()
+	assert.Equal(t, 0, len(value))
+	assert.Equal(t, 1, len(sc.Contexts))
+}
+
+func TestTryCallBeforeUpgrade(t *testing.T) {
+	upgrade := config.GetNativeUpgradeHeight()
+	if upgrade == 0 {
+		return
+	}
+	callee := append(storagePutCode(), byte(vm.NOP))
+	sc := newTryCallContract(t, callee, upgrade-1)
+	engine, err := sc.NewExecuteEngine(tryCallCode(common.AddressFromVmCode(callee), 100000, 7))
+	assert.Nil(t, err)
+	_, err = engine.Invoke()
+	assert.NotNil(t, err)
+}
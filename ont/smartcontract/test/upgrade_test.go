@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/store/leveldbstore"
+	"github.com/ontio/ontology/core/store/overlaydb"
+	"github.com/ontio/ontology/smartcontract"
+	"github.com/ontio/ontology/smartcontract/service/neovm"
+	"github.com/ontio/ontology/smartcontract/storage"
+	vm "github.com/ontio/ontology/vm/neovm"
+	"github.com/ontio/ontology/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func storageGetCode() []byte {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushByteArray([]byte("k"))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GETCONTEXT_NAME))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.STORAGE_GET_NAME))
+	return builder.ToArray()
+}
+
+func upgradeCode(code []byte, version string) []byte {
+	return deployCode(code, version, neovm.CONTRACT_UPGRADE_NAME)
+}
+
+func destroyCode() []byte {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(neovm.CONTRACT_DESTROY_NAME))
+	return builder.ToArray()
+}
+
+func deployCode(code []byte, version string, api string) []byte {
+	builder := vm.NewParamsBuilder(new(bytes.Buffer))
+	builder.EmitPushByteArray([]byte("desc"))
+	builder.EmitPushByteArray([]byte("email"))
+	builder.EmitPushByteArray([]byte("author"))
+	builder.EmitPushByteArray([]byte(version))
+	builder.EmitPushByteArray([]byte("name"))
+	builder.EmitPushBool(true)
+	builder.EmitPushByteArray(code)
+	builder.Emit(vm.SYSCALL)
+	builder.EmitPushByteArray([]byte(api))
+	return builder.ToArray()
+}
+
+func invokeContract(t *testing.T, cache *storage.CacheDB, addr common.Address) (interface{}, error) {
+	return invokeContractAt(t, cache, addr, config.GetNativeUpgradeHeight())
+}
+
+func invokeContractAt(t *testing.T, cache *storage.CacheDB, addr common.Address, height uint32) (interface{}, error) {
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: height},
+		CacheDB: cache,
+		Gas:     100000000,
+	}
+	engine, err := sc.NewExecuteEngine(append([]byte{byte(vm.APPCALL)}, addr[:]...))
+	assert.Nil(t, err)
+	return engine.Invoke()
+}
+
+func TestContractUpgrade(t *testing.T) {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+
+	v2 := storageGetCode()
+	v1 := append(storagePutCode(), upgradeCode(v2, "2")...)
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: v1, Name: "name", Version: "1"}))
+	addr := common.AddressFromVmCode(v1)
+
+	_, err = invokeContract(t, cache, addr)
+	assert.Nil(t, err)
+
+	dep, err := cache.GetContract(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, v2, dep.Code)
+	history, err := cache.GetContractHistory(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(history.Versions))
+	assert.Equal(t, "1", history.Versions[0].Version)
+	assert.Equal(t, uint32(0), history.Versions[0].Height)
+	assert.Equal(t, addr, history.Versions[0].CodeHash)
+	assert.Equal(t, "2", history.Versions[1].Version)
+	assert.Equal(t, config.GetNativeUpgradeHeight(), history.Versions[1].Height)
+	assert.Equal(t, common.AddressFromVmCode(v2), history.Versions[1].CodeHash)
+
+	//storage is kept at the same address and read by the new code
+	result, err := invokeContract(t, cache, addr)
+	assert.Nil(t, err)
+	val, err := result.(types.StackItems).GetByteArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v"), val)
+}
+
+func TestContractUpgradeNotDeployed(t *testing.T) {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	sc := &smartcontract.SmartContract{
+		Config:  &smartcontract.Config{Height: config.GetNativeUpgradeHeight()},
+		CacheDB: storage.NewCacheDB(overlaydb.NewOverlayDB(memback)),
+		Gas:     100000000,
+	}
+	engine, err := sc.NewExecuteEngine(upgradeCode(storageGetCode(), "2"))
+	assert.Nil(t, err)
+	_, err = engine.Invoke()
+	assert.NotNil(t, err)
+}
+
+func TestContractUpgradeHeight(t *testing.T) {
+	if config.GetNativeUpgradeHeight() == 0 {
+		return
+	}
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+
+	v1 := upgradeCode(storageGetCode(), "2")
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: v1, Version: "1"}))
+	addr := common.AddressFromVmCode(v1)
+
+	_, err = invokeContractAt(t, cache, addr, config.GetNativeUpgradeHeight()-1)
+	assert.NotNil(t, err)
+}
+
+func TestContractUpgradeDestroy(t *testing.T) {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+
+	v1 := upgradeCode(destroyCode(), "2")
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: v1, Version: "1"}))
+	addr := common.AddressFromVmCode(v1)
+	_, err = invokeContract(t, cache, addr)
+	assert.Nil(t, err)
+	_, err = invokeContract(t, cache, addr)
+	assert.Nil(t, err)
+
+	dep, err := cache.GetContract(addr)
+	assert.Nil(t, err)
+	assert.Nil(t, dep)
+	history, err := cache.GetContractHistory(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(history.Versions))
+}
+
+func TestContractUpgradeMigrate(t *testing.T) {
+	memback, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(memback))
+
+	v3 := storageGetCode()
+	v2 := deployCode(v3, "3", neovm.CONTRACT_MIGRATE_NAME)
+	v1 := append(storagePutCode(), upgradeCode(v2, "2")...)
+	assert.Nil(t, cache.PutContract(&payload.DeployCode{Code: v1, Version: "1"}))
+	addr, newAddr := common.AddressFromVmCode(v1), common.AddressFromVmCode(v3)
+	_, err = invokeContract(t, cache, addr)
+	assert.Nil(t, err)
+	_, err = invokeContract(t, cache, addr)
+	assert.Nil(t, err)
+
+	history, err := cache.GetContractHistory(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(history.Versions))
+	history, err = cache.GetContractHistory(newAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(history.Versions))
+	assert.Equal(t, "3", history.Versions[2].Version)
+	assert.Equal(t, newAddr, history.Versions[2].CodeHash)
+
+	result, err := invokeContract(t, cache, newAddr)
+	assert.Nil(t, err)
+	val, err := result.(types.StackItems).GetByteArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v"), val)
+}